@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
 	"github.com/aryan-more/simple_bank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
@@ -14,6 +15,11 @@ import (
 type CreateAccountRequest struct {
 	Owner    string `json:"owner" binding:"required"`
 	Currency string `json:"currency" binding:"required,currency"`
+	// Product defaults to a checking account.
+	Product string `json:"product" binding:"omitempty,alphanum,max=32"`
+	// Name tells the owner's accounts apart and defaults to the currency
+	// code. It must be unique among them.
+	Name string `json:"name" binding:"max=64"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
@@ -30,15 +36,32 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
+	if err := server.enabledCurrency(req.Currency); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+	if req.Product == "" {
+		req.Product = db.ProductChecking
+	}
+	if req.Name == "" {
+		req.Name = req.Currency
+	}
+
 	arg := db.CreateAccountParams{
 		Owner:    authPayload.Username,
 		Currency: req.Currency,
 		Balance:  0,
+		Product:  req.Product,
+		Name:     req.Name,
 	}
 
-	acc, err := server.store.CreateAccount(ctx, arg)
+	acc, err := server.store.CreateAccountTX(ctx, arg)
 
 	if err != nil {
+		if errors.Is(err, db.ErrCurrencyAccountExists) || errors.As(err, new(*db.AccountCapError)) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
@@ -56,35 +79,55 @@ func (server *Server) createAccount(ctx *gin.Context) {
 
 }
 
-type GetAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+type accountResponse struct {
+	db.Account
+	// AvailableBalance is the balance minus funds reserved by active holds.
+	AvailableBalance int64 `json:"available_balance"`
+	// AvailableCredit is what can still be spent, the available balance plus
+	// the overdraft limit.
+	AvailableCredit int64 `json:"available_credit"`
+	// The decimals are the balances in major units, e.g. "12.34".
+	BalanceDecimal          string `json:"balance_decimal"`
+	AvailableBalanceDecimal string `json:"available_balance_decimal"`
+	AvailableCreditDecimal  string `json:"available_credit_decimal"`
 }
 
 func (server *Server) getAccount(ctx *gin.Context) {
-	var req GetAccountRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleViewer)
+	if !ok {
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
-
+	held, err := server.store.GetHeldAmount(ctx, account.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	if authPayload.Username != account.Owner {
-		ctx.JSON(http.StatusUnauthorized, errors.New("account doesn't belog to authorized user"))
+	available, err := account.AvailableBalance(held)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	credit, err := account.AvailableCredit(held)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	currency, err := money.Lookup(account.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, accountResponse{
+		Account:                 account,
+		AvailableBalance:        available,
+		AvailableCredit:         credit,
+		BalanceDecimal:          money.New(account.Balance, currency).Decimal(),
+		AvailableBalanceDecimal: money.New(available, currency).Decimal(),
+		AvailableCreditDecimal:  money.New(credit, currency).Decimal(),
+	})
 }
 
 type listAccountRequest struct {
@@ -101,12 +144,12 @@ func (server *Server) listAccount(ctx *gin.Context) {
 	}
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	accounts, err := server.store.ListAccounts(
+	accounts, err := server.store.ListMemberAccounts(
 		ctx,
-		db.ListAccountsParams{
-			Owner:  authPayload.Username,
-			Limit:  req.PageSize,
-			Offset: (req.PageID - 1) * req.PageSize,
+		db.ListMemberAccountsParams{
+			Username: authPayload.Username,
+			Limit:    req.PageSize,
+			Offset:   (req.PageID - 1) * req.PageSize,
 		},
 	)
 
@@ -118,3 +161,120 @@ func (server *Server) listAccount(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, accounts)
 }
+
+type accountURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getMemberAccount loads the account named in the URI and checks that the
+// authenticated user is a member with at least role.
+func (server *Server) getMemberAccount(ctx *gin.Context, role string) (db.Account, bool) {
+	account, ok := server.getURIAccount(ctx)
+	if !ok {
+		return account, false
+	}
+
+	return account, server.authorizeAccount(ctx, account, role)
+}
+
+// getURIAccount loads the account named in the URI without checking who may
+// use it.
+func (server *Server) getURIAccount(ctx *gin.Context) (db.Account, bool) {
+	var req accountURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Account{}, false
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
+
+type updateAccountTierRequest struct {
+	Tier string `json:"tier" binding:"required,alphanum,max=32"`
+}
+
+// updateAccountTier moves an account to another pricing tier, which decides
+// the fee rules its transfers are charged with. Only bankers may do this.
+func (server *Server) updateAccountTier(ctx *gin.Context) {
+	req := bindJson[updateAccountTierRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	var uri accountURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !requireBanker(ctx, "change account tiers") {
+		return
+	}
+
+	account, err := server.store.UpdateAccountTier(ctx, db.UpdateAccountTierParams{
+		ID:   uri.ID,
+		Tier: req.Tier,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
+
+type updateOverdraftRequest struct {
+	OverdraftLimit   *int64 `json:"overdraft_limit" binding:"required,min=0"`
+	OverdraftRateBps int32  `json:"overdraft_rate_bps" binding:"min=0,max=10000"`
+}
+
+// updateAccountOverdraft sets how far an account may be overdrawn and the
+// interest charged while it is. Only bankers may do this. Lowering the limit
+// doesn't touch an account already overdrawn past it, it only blocks further
+// debits.
+func (server *Server) updateAccountOverdraft(ctx *gin.Context) {
+	req := bindJson[updateOverdraftRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	var uri accountURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !requireBanker(ctx, "change overdraft limits") {
+		return
+	}
+
+	account, err := server.store.UpdateAccountOverdraft(ctx, db.UpdateAccountOverdraftParams{
+		ID:               uri.ID,
+		OverdraftLimit:   *req.OverdraftLimit,
+		OverdraftRateBps: req.OverdraftRateBps,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
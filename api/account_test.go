@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -49,20 +50,20 @@ func TestListAccountAPI(t *testing.T) {
 				pageSize: n,
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.ListAccountsParams{
-					Limit:  int32(n),
-					Offset: 0,
-					Owner:  owner,
+				arg := db.ListMemberAccountsParams{
+					Limit:    int32(n),
+					Offset:   0,
+					Username: owner,
 				}
 
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+				store.EXPECT().ListMemberAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
 			},
 			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireMatchAccounts(t, recorder.Body, accounts)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 
@@ -74,7 +75,7 @@ func TestListAccountAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListMemberAccounts(gomock.Any(), gomock.Any()).Times(0)
 
 			},
 			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -91,13 +92,13 @@ func TestListAccountAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListMemberAccounts(gomock.Any(), gomock.Any()).Times(0)
 			},
 			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 
 			},
 		},
@@ -109,13 +110,13 @@ func TestListAccountAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListMemberAccounts(gomock.Any(), gomock.Any()).Times(0)
 			},
 			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 
 			},
 		},
@@ -127,13 +128,13 @@ func TestListAccountAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return(accounts, sql.ErrConnDone)
+				store.EXPECT().ListMemberAccounts(gomock.Any(), gomock.Any()).Times(1).Return(accounts, sql.ErrConnDone)
 			},
 			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 
 			},
 		},
@@ -189,14 +190,71 @@ func TestCreateAccount(t *testing.T) {
 				"currency": account.Currency,
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireMatchAccount(t, recorder.Body, account)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "Named",
+			body: gin.H{
+				"owner":    account.Owner,
+				"currency": account.Currency,
+				"name":     "bills",
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountParams{
+					Owner:    account.Owner,
+					Currency: account.Currency,
+					Product:  db.ProductChecking,
+					Name:     "bills",
+				}
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "SecondInCurrency",
+			body: gin.H{
+				"owner":    account.Owner,
+				"currency": account.Currency,
+				"name":     "bills",
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrCurrencyAccountExists)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "AccountCap",
+			body: gin.H{
+				"owner":    account.Owner,
+				"currency": account.Currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.Account{}, &db.AccountCapError{Owner: account.Owner, MaxAccounts: 10})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -206,13 +264,13 @@ func TestCreateAccount(t *testing.T) {
 				"currency": account.Currency,
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(10), time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(10), util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -222,7 +280,7 @@ func TestCreateAccount(t *testing.T) {
 				"currency": account.Currency,
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -237,13 +295,13 @@ func TestCreateAccount(t *testing.T) {
 				"currency": account.Currency,
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, sql.ErrConnDone)
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(1).Return(account, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -253,13 +311,13 @@ func TestCreateAccount(t *testing.T) {
 				"currency": "INR",
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -271,7 +329,7 @@ func TestCreateAccount(t *testing.T) {
 			buildStub: func(store *mockdb.MockStore) {
 				store.
 					EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTX(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(
 						account,
@@ -284,7 +342,7 @@ func TestCreateAccount(t *testing.T) {
 				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -296,7 +354,7 @@ func TestCreateAccount(t *testing.T) {
 			buildStub: func(store *mockdb.MockStore) {
 				store.
 					EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTX(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(
 						account,
@@ -309,7 +367,7 @@ func TestCreateAccount(t *testing.T) {
 				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 	}
@@ -348,6 +406,7 @@ func TestCreateAccount(t *testing.T) {
 func TestGetAccount(t *testing.T) {
 	owner := util.RandomString(5)
 	account := randomAccount(owner)
+	viewer := util.RandomOwner()
 
 	testcase := []struct {
 		name          string
@@ -361,6 +420,7 @@ func TestGetAccount(t *testing.T) {
 			accountID: account.ID,
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(int64(0), nil)
 
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -369,7 +429,86 @@ func TestGetAccount(t *testing.T) {
 				requireMatchAccount(t, recorder.Body, account)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "Viewer",
+			accountID: account.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: viewer})).
+					Times(1).Return(acceptedMember(account.ID, viewer, db.MemberRoleViewer), nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, viewer, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "HeldFunds",
+			accountID: account.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(int64(10), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp accountResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, account.Balance-10, rsp.AvailableBalance)
+				require.Equal(t, account.Balance-10, rsp.AvailableCredit)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "Overdraft",
+			accountID: account.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				overdrawn := account
+				overdrawn.Balance = -30
+				overdrawn.OverdraftLimit = 100
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(overdrawn, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(int64(10), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp accountResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, int64(-40), rsp.AvailableBalance)
+				require.Equal(t, int64(60), rsp.AvailableCredit)
+				require.Equal(t, "-0.30", rsp.BalanceDecimal)
+				require.Equal(t, "-0.40", rsp.AvailableBalanceDecimal)
+				require.Equal(t, "0.60", rsp.AvailableCreditDecimal)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "Overflow",
+			accountID: account.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				rich := account
+				rich.Balance = math.MaxInt64
+				rich.OverdraftLimit = 100
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(rich, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -384,7 +523,7 @@ func TestGetAccount(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(10), time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(10), util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -411,7 +550,7 @@ func TestGetAccount(t *testing.T) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -424,7 +563,7 @@ func TestGetAccount(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -437,7 +576,7 @@ func TestGetAccount(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
 			},
 		},
 	}
@@ -449,6 +588,7 @@ func TestGetAccount(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStub(store)
+			expectNotMember(store)
 
 			server := newTestServer(t, store)
 
@@ -467,6 +607,218 @@ func TestGetAccount(t *testing.T) {
 
 }
 
+func TestUpdateAccountTierAPI(t *testing.T) {
+	account := randomAccount(util.RandomOwner())
+	updated := account
+	updated.Tier = "premium"
+
+	testcase := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ok",
+			body: gin.H{"tier": "premium"},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountTierParams{ID: account.ID, Tier: "premium"}
+				store.EXPECT().UpdateAccountTier(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireMatchAccount(t, recorder.Body, updated)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{"tier": "premium"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountTier(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"tier": "premium"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountTier(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "InvalidTier",
+			body: gin.H{"tier": "gold plus"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountTier(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/tier", account.ID)
+			req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func TestUpdateAccountOverdraftAPI(t *testing.T) {
+	account := randomAccount(util.RandomOwner())
+	updated := account
+	updated.OverdraftLimit = 50000
+	updated.OverdraftRateBps = 1500
+
+	testcase := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ok",
+			body: gin.H{"overdraft_limit": 50000, "overdraft_rate_bps": 1500},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountOverdraftParams{ID: account.ID, OverdraftLimit: 50000, OverdraftRateBps: 1500}
+				store.EXPECT().UpdateAccountOverdraft(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireMatchAccount(t, recorder.Body, updated)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "RemoveOverdraft",
+			body: gin.H{"overdraft_limit": 0},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountOverdraftParams{ID: account.ID}
+				store.EXPECT().UpdateAccountOverdraft(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{"overdraft_limit": 50000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountOverdraft(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "MissingLimit",
+			body: gin.H{"overdraft_rate_bps": 1500},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountOverdraft(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NegativeLimit",
+			body: gin.H{"overdraft_limit": -1},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountOverdraft(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"overdraft_limit": 50000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountOverdraft(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/overdraft", account.ID)
+			req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
 func randomAccount(owner string) db.Account {
 	return db.Account{
 		ID:        util.RandomInt(1, 1000),
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/gin-gonic/gin"
+)
+
+type approvalResponse struct {
+	db.TransferApproval
+	// AmountDecimal is Amount in major units of the paying account's
+	// currency.
+	AmountDecimal string `json:"amount_decimal"`
+	Expired       bool   `json:"expired"`
+}
+
+func newApprovalResponse(approval db.TransferApproval, from db.Account) (approvalResponse, error) {
+	currency, err := money.Lookup(from.Currency)
+	if err != nil {
+		return approvalResponse{}, err
+	}
+	return approvalResponse{
+		TransferApproval: approval,
+		AmountDecimal:    money.New(approval.Amount, currency).Decimal(),
+		Expired:          approval.Expired(time.Now()),
+	}, nil
+}
+
+// needsApproval reports whether a transfer is large enough to wait for a
+// second person. Each currency has its own threshold, a zero one disables
+// dual approval.
+func (server *Server) needsApproval(code string, amount int64) bool {
+	currency, _ := currencies.lookup(code)
+	return currency.ApprovalThreshold > 0 && amount > currency.ApprovalThreshold
+}
+
+// requestApproval parks a transfer until a banker or another signer of the
+// paying account approves it.
+func (server *Server) requestApproval(ctx *gin.Context, arg db.TransferTxParams, from db.Account, username string) {
+	approval, err := server.store.RequestApprovalTX(ctx, db.RequestApprovalTxParams{
+		TransferTxParams: arg,
+		RequestedBy:      username,
+		ExpiresAt:        time.Now().Add(server.config.ApprovalDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := newApprovalResponse(approval, from)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, rsp)
+}
+
+// canDecide reports whether the authenticated user may approve or reject a
+// transfer paid from the from account. Bankers and signers of the paying
+// account may decide, as long as they didn't request the transfer themselves.
+func (server *Server) canDecide(ctx *gin.Context, payload *token.Payload, approval db.TransferApproval, from db.Account) (bool, error) {
+	if payload.Username == approval.RequestedBy {
+		return false, nil
+	}
+	if payload.Role == util.BankerRole {
+		return true, nil
+	}
+
+	member, err := server.accountMember(ctx, from, payload.Username)
+	if err != nil {
+		return false, err
+	}
+	return member.Allows(db.MemberRoleSigner), nil
+}
+
+type approvalURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getApprovalForUser loads the approval named in the URI with its paying
+// account and checks that the authenticated user is its requester or may
+// decide it, which is reported alongside.
+func (server *Server) getApprovalForUser(ctx *gin.Context) (approval db.TransferApproval, from db.Account, decider bool, ok bool) {
+	var req approvalURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return approval, from, false, false
+	}
+
+	approval, err := server.store.GetTransferApproval(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return approval, from, false, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return approval, from, false, false
+	}
+
+	from, err = server.store.GetAccount(ctx, approval.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return approval, from, false, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	decider, err = server.canDecide(ctx, authPayload, approval, from)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return approval, from, false, false
+	}
+	if authPayload.Username != approval.RequestedBy && !decider {
+		err := errors.New("transfer approval doesn't belong to authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return approval, from, false, false
+	}
+
+	return approval, from, decider, true
+}
+
+func (server *Server) getApproval(ctx *gin.Context) {
+	approval, from, _, ok := server.getApprovalForUser(ctx)
+	if !ok {
+		return
+	}
+
+	rsp, err := newApprovalResponse(approval, from)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type listApprovalsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected expired"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listApprovals shows bankers the approvals of every user and everyone else
+// only the approvals they requested.
+func (server *Server) listApprovals(ctx *gin.Context) {
+	var req listApprovalsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListTransferApprovalsParams{
+		Status: req.Status,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+	if arg.Status == "" {
+		arg.Status = db.ApprovalStatusPending
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.BankerRole {
+		arg.RequestedBy = sql.NullString{String: authPayload.Username, Valid: true}
+	}
+
+	approvals, err := server.store.ListTransferApprovals(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Approvals on a page are often paid from the same few accounts.
+	accounts := make(map[int64]db.Account)
+	rsp := make([]approvalResponse, len(approvals))
+	for i, approval := range approvals {
+		from, ok := accounts[approval.FromAccountID]
+		if !ok {
+			from, err = server.store.GetAccount(ctx, approval.FromAccountID)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			accounts[from.ID] = from
+		}
+		rsp[i], err = newApprovalResponse(approval, from)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type decideTransferResponse struct {
+	Approval approvalResponse `json:"approval"`
+	// Transfer is only set when the approval was granted.
+	Transfer *transferTxResponse `json:"transfer,omitempty"`
+}
+
+func (server *Server) approveTransfer(ctx *gin.Context) {
+	server.decideTransfer(ctx, true)
+}
+
+func (server *Server) rejectTransfer(ctx *gin.Context) {
+	server.decideTransfer(ctx, false)
+}
+
+func (server *Server) decideTransfer(ctx *gin.Context, approve bool) {
+	approval, from, decider, ok := server.getApprovalForUser(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !decider {
+		err := errors.New("transfer must be decided by a banker or signer other than its requester")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.DecideTransferTX(ctx, db.DecideTransferTxParams{
+		ApprovalID: approval.ID,
+		DecidedBy:  authPayload.Username,
+		Approve:    approve,
+	})
+	if err != nil {
+		txErrorResponse(ctx, err)
+		return
+	}
+
+	approvalRsp, err := newApprovalResponse(result.Approval, from)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	rsp := decideTransferResponse{Approval: approvalRsp}
+	if result.Transfer != nil {
+		transfer, err := newTransferTxResponse(*result.Transfer)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		rsp.Transfer = &transfer
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
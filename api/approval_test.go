@@ -0,0 +1,350 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomApproval(requestedBy string) db.TransferApproval {
+	return db.TransferApproval{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID:   util.RandomInt(1, 1000),
+		Amount:        util.RandomMoney(),
+		RequestedBy:   requestedBy,
+		Status:        db.ApprovalStatusPending,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+}
+
+func TestDecideTransferAPI(t *testing.T) {
+	requester := util.RandomOwner()
+	banker := util.RandomOwner()
+	signer := util.RandomOwner()
+	approval := randomApproval(requester)
+	from := db.Account{ID: approval.FromAccountID, Owner: util.RandomOwner(), Currency: "USD"}
+
+	testcase := []struct {
+		name          string
+		action        string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Approve",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+
+				arg := db.DecideTransferTxParams{
+					ApprovalID: approval.ID,
+					DecidedBy:  banker,
+					Approve:    true,
+				}
+				result := db.DecideTransferTxResult{
+					Approval: approval,
+					Transfer: &db.TransferTxResult{
+						Transfer:    db.Transfer{Amount: 1234},
+						FromAccount: from,
+					},
+				}
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp decideTransferResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				decimal, err := money.Format(approval.Amount, from.Currency)
+				require.NoError(t, err)
+				require.Equal(t, decimal, rsp.Approval.AmountDecimal)
+				require.NotNil(t, rsp.Transfer)
+				require.Equal(t, "12.34", rsp.Transfer.AmountDecimal)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "Reject",
+			action: "reject",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+
+				arg := db.DecideTransferTxParams{
+					ApprovalID: approval.ID,
+					DecidedBy:  banker,
+					Approve:    false,
+				}
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.DecideTransferTxResult{}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "RequesterCantApprove",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, requester, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "SignerApproves",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: from.ID, Username: signer})).
+					Times(1).
+					Return(acceptedMember(from.ID, signer, db.MemberRoleSigner), nil)
+
+				arg := db.DecideTransferTxParams{
+					ApprovalID: approval.ID,
+					DecidedBy:  signer,
+					Approve:    true,
+				}
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.DecideTransferTxResult{}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, signer, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:   "OwnerRejects",
+			action: "reject",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+
+				arg := db.DecideTransferTxParams{
+					ApprovalID: approval.ID,
+					DecidedBy:  from.Owner,
+					Approve:    false,
+				}
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.DecideTransferTxResult{}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, from.Owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:   "ViewerCantApprove",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(acceptedMember(from.ID, signer, db.MemberRoleViewer), nil)
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, signer, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:   "RequestingSignerCantApprove",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, requester, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:   "DepositorCantApprove",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+				expectNotMember(store)
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:   "Expired",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.DecideTransferTxResult{}, db.ErrApprovalExpired)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "InsufficientFunds",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(approval, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(approval.FromAccountID)).Times(1).Return(from, nil)
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.DecideTransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "NotFound",
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferApproval(gomock.Any(), gomock.Eq(approval.ID)).Times(1).Return(db.TransferApproval{}, sql.ErrNoRows)
+				store.EXPECT().DecideTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/approvals/%d/%s", approval.ID, tc.action)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func TestListApprovalsAPI(t *testing.T) {
+	requester := util.RandomOwner()
+	approvals := []db.TransferApproval{randomApproval(requester), randomApproval(requester)}
+	approvals[0].Amount = 1234
+	// Both approvals are paid from the same account, which is looked up once.
+	approvals[1].FromAccountID = approvals[0].FromAccountID
+	from := db.Account{ID: approvals[0].FromAccountID, Currency: "USD"}
+
+	testcase := []struct {
+		name      string
+		username  string
+		role      string
+		buildStub func(store *mockdb.MockStore)
+	}{
+		{
+			name:     "Depositor",
+			username: requester,
+			role:     util.DepositorRole,
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListTransferApprovalsParams{
+					Status:      db.ApprovalStatusPending,
+					RequestedBy: sql.NullString{String: requester, Valid: true},
+					Limit:       5,
+					Offset:      0,
+				}
+				store.EXPECT().ListTransferApprovals(gomock.Any(), gomock.Eq(arg)).Times(1).Return(approvals, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+			},
+		},
+		{
+			name:     "Banker",
+			username: util.RandomOwner(),
+			role:     util.BankerRole,
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListTransferApprovalsParams{
+					Status: db.ApprovalStatusPending,
+					Limit:  5,
+					Offset: 0,
+				}
+				store.EXPECT().ListTransferApprovals(gomock.Any(), gomock.Eq(arg)).Times(1).Return(approvals, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodGet, "/approvals?page_id=1&page_size=5", nil)
+			require.NoError(t, err)
+			addAuthorizationHeader(t, req, server.tokenMaker, authorizationTypeBearer, tc.username, tc.role, time.Minute)
+
+			server.router.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var rsp []approvalResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Len(t, rsp, len(approvals))
+			require.Equal(t, "12.34", rsp[0].AmountDecimal)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/gin-gonic/gin"
+)
+
+const dateLayout = "2006-01-02"
+
+type balanceRequest struct {
+	// At is an RFC 3339 timestamp or a date, which means the end of that day
+	// in UTC. It defaults to now.
+	At string `form:"at"`
+}
+
+type balanceResponse struct {
+	AccountID int64     `json:"account_id"`
+	Currency  string    `json:"currency"`
+	At        time.Time `json:"at"`
+	Balance   int64     `json:"balance"`
+	// BalanceDecimal is Balance in major units, e.g. "12.34".
+	BalanceDecimal string `json:"balance_decimal"`
+	// SnapshotAt is the snapshot the balance was computed from, nil when
+	// every entry up to At had to be summed.
+	SnapshotAt *time.Time `json:"snapshot_at"`
+}
+
+// parseBalanceTime parses the at parameter of balance queries, which
+// defaults to now.
+func parseBalanceTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now, nil
+	}
+	return parseTime(value, true)
+}
+
+// parseTime parses an RFC 3339 timestamp or a date in UTC, which stands for
+// the start of the day or, with endOfDay, its last instant.
+func parseTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a date", value)
+	}
+	if !endOfDay {
+		return day, nil
+	}
+	// Postgres keeps microseconds, so this is the last instant of the day.
+	return day.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
+
+// getAccountBalance returns the balance of an account at a point in time.
+func (server *Server) getAccountBalance(ctx *gin.Context) {
+	var req balanceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	at, err := parseBalanceTime(req.At, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleViewer)
+	if !ok {
+		return
+	}
+
+	balance, err := server.store.GetBalanceAt(ctx, db.GetBalanceAtParams{
+		AccountID: account.ID,
+		At:        at,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	decimal, err := money.Format(balance.Balance, account.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := balanceResponse{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		At:             at,
+		Balance:        balance.Balance,
+		BalanceDecimal: decimal,
+	}
+	if balance.SnapshotAt.Valid {
+		rsp.SnapshotAt = &balance.SnapshotAt.Time
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type listEntriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listAccountEntries lists the entries of an account oldest first, each with
+// the account's balance right after it.
+func (server *Server) listAccountEntries(ctx *gin.Context) {
+	var req listEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleViewer)
+	if !ok {
+		return
+	}
+
+	entries, err := server.store.ListEntries(ctx, db.ListEntriesParams{
+		AccountID: account.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBalanceTime(t *testing.T) {
+	now := time.Now()
+
+	at, err := parseBalanceTime("", now)
+	require.NoError(t, err)
+	require.Equal(t, now, at)
+
+	at, err = parseBalanceTime("2024-03-31T12:00:00Z", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC), at)
+
+	at, err = parseBalanceTime("2024-03-31", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 31, 23, 59, 59, 999999000, time.UTC), at)
+
+	_, err = parseBalanceTime("31/03/2024", now)
+	require.Error(t, err)
+}
+
+func TestGetAccountBalanceAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+
+	at := time.Date(2024, 3, 31, 23, 59, 59, 999999000, time.UTC)
+	snapshotAt := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	testcase := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Ok",
+			query: "?at=2024-03-31",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.GetBalanceAtParams{AccountID: account.ID, At: at}
+				store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.GetBalanceAtRow{
+						SnapshotAt: sql.NullTime{Time: snapshotAt, Valid: true},
+						Balance:    1234,
+					}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp balanceResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, account.ID, rsp.AccountID)
+				require.Equal(t, account.Currency, rsp.Currency)
+				require.Equal(t, int64(1234), rsp.Balance)
+				require.Equal(t, "12.34", rsp.BalanceDecimal)
+				require.True(t, at.Equal(rsp.At))
+				require.NotNil(t, rsp.SnapshotAt)
+				require.True(t, snapshotAt.Equal(*rsp.SnapshotAt))
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "NoSnapshot",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.GetBalanceAtRow{Balance: 10}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp balanceResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, int64(10), rsp.Balance)
+				require.Nil(t, rsp.SnapshotAt)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:  "InvalidAt",
+			query: "?at=yesterday",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(1).Return(db.GetBalanceAtRow{}, sql.ErrConnDone)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			expectNotMember(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%d/balance%s", account.ID, tc.query)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func TestListAccountEntriesAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+
+	entries := []db.ListEntriesRow{
+		{ID: 1, AccountID: account.ID, Amount: 100, RunningBalance: 100},
+		{ID: 2, AccountID: account.ID, Amount: -30, RunningBalance: 70},
+	}
+
+	testcase := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Ok",
+			query: "?page_id=2&page_size=5",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListEntriesParams{AccountID: account.ID, Limit: 5, Offset: 5}
+				store.EXPECT().ListEntries(gomock.Any(), gomock.Eq(arg)).Times(1).Return(entries, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.ListEntriesRow
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, entries, rsp)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "?page_id=1&page_size=50",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:  "UnauthorizedUser",
+			query: "?page_id=1&page_size=5",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			expectNotMember(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%d/entries%s", account.ID, tc.query)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best_effort"
+
+	maxBatchTransfers = 500
+)
+
+const (
+	batchItemCompleted = "completed"
+	batchItemFailed    = "failed"
+	batchItemInvalid   = "invalid"
+	batchItemSkipped   = "skipped"
+)
+
+type batchTransferRequest struct {
+	Mode string `json:"mode" binding:"required,oneof=atomic best_effort"`
+	// Transfers are decoded one by one so a malformed item is reported in its
+	// own result instead of rejecting the whole batch.
+	Transfers []json.RawMessage `json:"transfers" binding:"required,min=1,max=500"`
+}
+
+type batchTransferItemResponse struct {
+	Index  int                 `json:"index"`
+	Status string              `json:"status"`
+	Error  string              `json:"error,omitempty"`
+	Result *transferTxResponse `json:"result,omitempty"`
+	// Transfer is the failed transfer, for failures that were recorded, and
+	// AmountDecimal its amount in major units.
+	Transfer      *db.Transfer `json:"transfer,omitempty"`
+	AmountDecimal string       `json:"amount_decimal,omitempty"`
+}
+
+type batchTransferResponse struct {
+	Mode      string                      `json:"mode"`
+	Completed int                         `json:"completed"`
+	Failed    int                         `json:"failed"`
+	Items     []batchTransferItemResponse `json:"items"`
+}
+
+func newBatchTransferResponse(mode string, items []batchTransferItemResponse) batchTransferResponse {
+	rsp := batchTransferResponse{
+		Mode:  mode,
+		Items: items,
+	}
+	for _, item := range items {
+		if item.Status == batchItemCompleted {
+			rsp.Completed++
+		} else {
+			rsp.Failed++
+		}
+	}
+	return rsp
+}
+
+func (server *Server) createBatchTransfer(ctx *gin.Context) {
+	req := bindJson[batchTransferRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	items := make([]batchTransferItemResponse, len(req.Transfers))
+	params := make([]db.TransferTxParams, 0, len(req.Transfers))
+	indexes := make([]int, 0, len(req.Transfers))
+	accounts := make(map[int64]db.Account)
+
+	for i, raw := range req.Transfers {
+		items[i].Index = i
+
+		arg, status, err := server.validBatchItem(ctx, raw, authPayload.Username, accounts)
+		if err != nil {
+			if status == http.StatusInternalServerError {
+				ctx.JSON(status, errorResponse(err))
+				return
+			}
+			items[i].Status = batchItemInvalid
+			items[i].Error = err.Error()
+			continue
+		}
+
+		params = append(params, arg)
+		indexes = append(indexes, i)
+	}
+
+	atomic := req.Mode == batchModeAtomic
+	if atomic && len(params) < len(req.Transfers) {
+		for _, i := range indexes {
+			items[i].Status = batchItemSkipped
+		}
+		ctx.JSON(http.StatusBadRequest, newBatchTransferResponse(req.Mode, items))
+		return
+	}
+
+	status := http.StatusOK
+	if len(params) > 0 {
+		result, err := server.store.BatchTransferTX(ctx, db.BatchTransferTxParams{
+			Transfers: params,
+			Atomic:    atomic,
+			ChunkSize: server.config.BatchChunkSize,
+		})
+		if err != nil {
+			txErrorResponse(ctx, err)
+			return
+		}
+
+		for j, item := range result.Items {
+			i := indexes[j]
+			switch {
+			case item.Err == nil:
+				rsp, err := newTransferTxResponse(item.Result)
+				if err != nil {
+					ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+					return
+				}
+				items[i].Status = batchItemCompleted
+				items[i].Result = &rsp
+			case errors.Is(item.Err, db.ErrBatchAborted):
+				items[i].Status = batchItemSkipped
+			default:
+				items[i].Status = batchItemFailed
+				items[i].Error = item.Err.Error()
+				var failedErr *db.TransferFailedError
+				if errors.As(item.Err, &failedErr) {
+					decimal, err := money.Format(failedErr.Transfer.Amount, accounts[failedErr.Transfer.FromAccountID].Currency)
+					if err != nil {
+						ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+						return
+					}
+					items[i].Transfer = &failedErr.Transfer
+					items[i].AmountDecimal = decimal
+				}
+				if atomic {
+					status = txErrorStatus(item.Err)
+				}
+			}
+		}
+	}
+
+	ctx.JSON(status, newBatchTransferResponse(req.Mode, items))
+}
+
+// validBatchItem decodes and validates a single batch item the same way
+// createTransfer validates its request. Accounts are cached across items.
+func (server *Server) validBatchItem(ctx *gin.Context, raw json.RawMessage, username string, accounts map[int64]db.Account) (db.TransferTxParams, int, error) {
+	var item transferRequest
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return db.TransferTxParams{}, http.StatusBadRequest, err
+	}
+
+	if err := binding.Validator.ValidateStruct(&item); err != nil {
+		return db.TransferTxParams{}, http.StatusBadRequest, err
+	}
+
+	return server.validTransferItem(ctx, item, username, accounts)
+}
+
+// validTransferItem checks a decoded transfer the way createTransfer does,
+// for transfers submitted in bulk.
+func (server *Server) validTransferItem(ctx *gin.Context, item transferRequest, username string, accounts map[int64]db.Account) (db.TransferTxParams, int, error) {
+	amount, err := resolveAmount(item.Amount, item.Currency)
+	if err != nil {
+		return db.TransferTxParams{}, http.StatusBadRequest, err
+	}
+
+	from, status, err := server.cachedAccount(ctx, item.FromAccountID, item.Currency, accounts)
+	if err != nil {
+		return db.TransferTxParams{}, status, err
+	}
+
+	if status, err := server.checkMember(ctx, from, username, db.MemberRoleSigner); err != nil {
+		return db.TransferTxParams{}, status, err
+	}
+
+	if server.needsApproval(item.Currency, amount) {
+		currency, _ := currencies.lookup(item.Currency)
+		err := fmt.Errorf("transfers above %d need approval, submit them individually", currency.ApprovalThreshold)
+		return db.TransferTxParams{}, http.StatusBadRequest, err
+	}
+
+	toAccountID, status, err := server.transferTarget(ctx, item, amount, username, accounts)
+	if err != nil {
+		return db.TransferTxParams{}, status, err
+	}
+
+	return db.TransferTxParams{
+		FromAccountID:     item.FromAccountID,
+		ToAccountID:       toAccountID,
+		Amount:            amount,
+		Description:       item.Description,
+		ExternalReference: item.ExternalReference,
+	}, http.StatusOK, nil
+}
+
+func (server *Server) cachedAccount(ctx *gin.Context, accountID int64, currency string, accounts map[int64]db.Account) (db.Account, int, error) {
+	account, ok := accounts[accountID]
+	if !ok {
+		account, status, err := server.lookupAccount(ctx, accountID, currency)
+		if err == nil || status == http.StatusBadRequest {
+			accounts[accountID] = account
+		}
+		return account, status, err
+	}
+
+	if account.Currency != currency {
+		return account, http.StatusBadRequest, &currencyMismatchError{account, currency}
+	}
+
+	return account, http.StatusOK, nil
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferAPI(t *testing.T) {
+	currency := util.RandomCurrency()
+	owner := util.RandomOwner()
+
+	from := randomAccountWithCurrency(owner, currency)
+	to1 := randomAccountWithCurrency(util.RandomOwner(), currency)
+	to2 := randomAccountWithCurrency(util.RandomOwner(), currency)
+
+	invalidCurrency := currency
+	for currency == invalidCurrency {
+		invalidCurrency = util.RandomCurrency()
+	}
+
+	validItems := []gin.H{
+		{"from_account": from.ID, "to_account": to1.ID, "amount": 10, "currency": currency},
+		{"from_account": from.ID, "to_account": to2.ID, "amount": 20, "currency": currency},
+	}
+	mixedItems := []gin.H{
+		validItems[0],
+		{"from_account": from.ID, "to_account": to2.ID, "amount": 20, "currency": invalidCurrency},
+		{"from_account": from.ID, "to_account": to2.ID, "amount": -1, "currency": currency},
+	}
+
+	expectAccounts := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(to1.ID)).Times(1).Return(to1, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(to2.ID)).AnyTimes().Return(to2, nil)
+	}
+
+	completed := func(amount int64) db.BatchTransferItemResult {
+		return db.BatchTransferItemResult{Result: db.TransferTxResult{
+			Transfer:    db.Transfer{FromAccountID: from.ID, Amount: amount},
+			FromAccount: from,
+		}}
+	}
+	decimal := func(amount int64) string {
+		decimal, err := money.Format(amount, currency)
+		require.NoError(t, err)
+		return decimal
+	}
+
+	testcase := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "AtomicOk",
+			body: gin.H{"mode": batchModeAtomic, "transfers": validItems},
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+
+				arg := db.BatchTransferTxParams{
+					Transfers: []db.TransferTxParams{
+						{FromAccountID: from.ID, ToAccountID: to1.ID, Amount: 10},
+						{FromAccountID: from.ID, ToAccountID: to2.ID, Amount: 20},
+					},
+					Atomic:    true,
+					ChunkSize: 2,
+				}
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.BatchTransferTxResult{Items: []db.BatchTransferItemResult{completed(10), completed(20)}}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				rsp := decodeBatchResponse(t, recorder)
+				require.Equal(t, 2, rsp.Completed)
+				require.Zero(t, rsp.Failed)
+				require.Equal(t, decimal(20), rsp.Items[1].Result.AmountDecimal)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "AtomicInvalidItem",
+			body: gin.H{"mode": batchModeAtomic, "transfers": mixedItems},
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				rsp := decodeBatchResponse(t, recorder)
+				require.Equal(t, batchItemSkipped, rsp.Items[0].Status)
+				require.Equal(t, batchItemInvalid, rsp.Items[1].Status)
+				require.Equal(t, batchItemInvalid, rsp.Items[2].Status)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "AtomicFailed",
+			body: gin.H{"mode": batchModeAtomic, "transfers": validItems},
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.BatchTransferTxResult{Items: []db.BatchTransferItemResult{
+						{Err: db.ErrBatchAborted},
+						{Err: &db.TransferFailedError{
+							Transfer: db.Transfer{ID: 7, FromAccountID: from.ID, Amount: 20, Status: db.TransferStatusFailed},
+							Err:      db.ErrInsufficientFunds,
+						}},
+					}}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				rsp := decodeBatchResponse(t, recorder)
+				require.Equal(t, batchItemSkipped, rsp.Items[0].Status)
+				require.Nil(t, rsp.Items[0].Transfer)
+				require.Equal(t, batchItemFailed, rsp.Items[1].Status)
+				require.Equal(t, db.ErrInsufficientFunds.Error(), rsp.Items[1].Error)
+				require.Equal(t, int64(7), rsp.Items[1].Transfer.ID)
+				require.Equal(t, db.TransferStatusFailed, rsp.Items[1].Transfer.Status)
+				require.Equal(t, decimal(20), rsp.Items[1].AmountDecimal)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "BestEffort",
+			body: gin.H{"mode": batchModeBestEffort, "transfers": mixedItems},
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+
+				arg := db.BatchTransferTxParams{
+					Transfers: []db.TransferTxParams{
+						{FromAccountID: from.ID, ToAccountID: to1.ID, Amount: 10},
+					},
+					ChunkSize: 2,
+				}
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.BatchTransferTxResult{Items: []db.BatchTransferItemResult{completed(10)}}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				rsp := decodeBatchResponse(t, recorder)
+				require.Equal(t, 1, rsp.Completed)
+				require.Equal(t, 2, rsp.Failed)
+				require.Equal(t, batchItemCompleted, rsp.Items[0].Status)
+				require.Equal(t, batchItemInvalid, rsp.Items[1].Status)
+				require.Equal(t, batchItemInvalid, rsp.Items[2].Status)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{"mode": batchModeBestEffort, "transfers": validItems},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				rsp := decodeBatchResponse(t, recorder)
+				require.Equal(t, 2, rsp.Failed)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, to1.Owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "InvalidMode",
+			body: gin.H{"mode": "sometimes", "transfers": validItems},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "EmptyBatch",
+			body: gin.H{"mode": batchModeAtomic, "transfers": []gin.H{}},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			expectNotMember(store)
+			server := newTestServer(t, store)
+			server.config.BatchChunkSize = 2
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewBuffer(data))
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func decodeBatchResponse(t *testing.T, recorder *httptest.ResponseRecorder) batchTransferResponse {
+	var rsp batchTransferResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	return rsp
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+// currencyRegistry caches the currency catalog, the currency validator runs
+// on every request and can't query the database. It's refreshed on an
+// interval and right after a change made through this server.
+type currencyRegistry struct {
+	mu         sync.RWMutex
+	currencies map[string]db.Currency
+}
+
+// currencies is the registry of the process. The validator engine gin binds
+// requests with is shared by the whole process, and so is what it checks.
+var currencies = &currencyRegistry{currencies: make(map[string]db.Currency)}
+
+// refresh reloads the catalog, keeping the cached one if it can't.
+func (registry *currencyRegistry) refresh(ctx context.Context, store db.Querier) error {
+	currencies, err := store.ListCurrencies(ctx)
+	if err != nil {
+		return err
+	}
+	registry.set(currencies)
+	return nil
+}
+
+func (registry *currencyRegistry) set(currencies []db.Currency) {
+	byCode := make(map[string]db.Currency, len(currencies))
+	for _, currency := range currencies {
+		byCode[currency.Code] = currency
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.currencies = byCode
+}
+
+// lookup returns a currency of the catalog, enabled or not.
+func (registry *currencyRegistry) lookup(code string) (db.Currency, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	currency, ok := registry.currencies[code]
+	return currency, ok
+}
+
+// validCurrency accepts the currencies of the catalog. Disabled currencies
+// are accepted too, so their accounts keep working, only account creation
+// checks the currency is enabled.
+func (registry *currencyRegistry) validCurrency(fl validator.FieldLevel) bool {
+	code, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+	_, ok = registry.lookup(code)
+	return ok
+}
+
+// RefreshCurrencies reloads the cached currency catalog. It must be called
+// once before the server starts, then to pick up changes made through other
+// servers.
+func (server *Server) RefreshCurrencies(ctx context.Context) error {
+	return currencies.refresh(ctx, server.store)
+}
+
+type currencyResponse struct {
+	Code       string `json:"code"`
+	Numeric    string `json:"numeric"`
+	MinorUnits int    `json:"minor_units"`
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	// ApprovalThreshold is in minor units, transfers above it wait for a
+	// second person. Zero disables dual approval.
+	ApprovalThreshold int64 `json:"approval_threshold"`
+	// PayeeCoolingOffAmount is in minor units, the most a new payee can
+	// receive until its cooling-off period ends.
+	PayeeCoolingOffAmount int64     `json:"payee_cooling_off_amount"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+func newCurrencyResponse(currency db.Currency) currencyResponse {
+	// Codes are checked against the ISO 4217 registry when they are added.
+	iso, _ := money.Lookup(currency.Code)
+	return currencyResponse{
+		Code:                  currency.Code,
+		Numeric:               iso.Numeric,
+		MinorUnits:            iso.MinorUnits,
+		Name:                  iso.Name,
+		Enabled:               currency.Enabled,
+		ApprovalThreshold:     currency.ApprovalThreshold,
+		PayeeCoolingOffAmount: currency.PayeeCoolingOffAmount,
+		CreatedAt:             currency.CreatedAt,
+		UpdatedAt:             currency.UpdatedAt,
+	}
+}
+
+// listCurrencies returns the currency catalog. Every user may read it to
+// know which currencies accounts can be opened in.
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	currencies, err := server.store.ListCurrencies(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]currencyResponse, len(currencies))
+	for i, currency := range currencies {
+		rsp[i] = newCurrencyResponse(currency)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type createCurrencyRequest struct {
+	Code string `json:"code" binding:"required,len=3,uppercase"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled"`
+	// ApprovalThreshold in minor units defaults to zero, which disables dual
+	// approval until one is set.
+	ApprovalThreshold int64 `json:"approval_threshold" binding:"min=0"`
+	// PayeeCoolingOffAmount in minor units defaults to zero, so new payees
+	// can't be paid in the currency until they cooled off.
+	PayeeCoolingOffAmount int64 `json:"payee_cooling_off_amount" binding:"min=0"`
+}
+
+// createCurrency adds an ISO 4217 currency to the catalog, with the system
+// accounts fees and interest in it are booked to. Only bankers may do this.
+func (server *Server) createCurrency(ctx *gin.Context) {
+	req := bindJson[createCurrencyRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	if !requireBanker(ctx, "manage currencies") {
+		return
+	}
+
+	if _, err := money.Lookup(req.Code); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateCurrencyParams{
+		Code:                  req.Code,
+		Enabled:               true,
+		ApprovalThreshold:     req.ApprovalThreshold,
+		PayeeCoolingOffAmount: req.PayeeCoolingOffAmount,
+	}
+	if req.Enabled != nil {
+		arg.Enabled = *req.Enabled
+	}
+	currency, err := server.store.CreateCurrencyTX(ctx, arg)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.refreshCurrencies(ctx)
+	ctx.JSON(http.StatusOK, newCurrencyResponse(currency))
+}
+
+type currencyURIRequest struct {
+	Code string `uri:"code" binding:"required,len=3"`
+}
+
+type updateCurrencyRequest struct {
+	Enabled               *bool  `json:"enabled" binding:"required_without_all=ApprovalThreshold PayeeCoolingOffAmount"`
+	ApprovalThreshold     *int64 `json:"approval_threshold" binding:"omitempty,min=0"`
+	PayeeCoolingOffAmount *int64 `json:"payee_cooling_off_amount" binding:"omitempty,min=0"`
+}
+
+// updateCurrency enables or disables a currency or changes its approval
+// threshold or payee cooling-off amount. Disabling one stops new accounts
+// from being opened in it. Only bankers may do this.
+func (server *Server) updateCurrency(ctx *gin.Context) {
+	req := bindJson[updateCurrencyRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	var uri currencyURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !requireBanker(ctx, "manage currencies") {
+		return
+	}
+
+	arg := db.UpdateCurrencyParams{Code: strings.ToUpper(uri.Code)}
+	if req.Enabled != nil {
+		arg.Enabled = sql.NullBool{Bool: *req.Enabled, Valid: true}
+	}
+	if req.ApprovalThreshold != nil {
+		arg.ApprovalThreshold = sql.NullInt64{Int64: *req.ApprovalThreshold, Valid: true}
+	}
+	if req.PayeeCoolingOffAmount != nil {
+		arg.PayeeCoolingOffAmount = sql.NullInt64{Int64: *req.PayeeCoolingOffAmount, Valid: true}
+	}
+
+	currency, err := server.store.UpdateCurrency(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.refreshCurrencies(ctx)
+	ctx.JSON(http.StatusOK, newCurrencyResponse(currency))
+}
+
+// refreshCurrencies picks up a change to the catalog straight away. The
+// change is made already, so a failed refresh only delays it until the next
+// scheduled one.
+func (server *Server) refreshCurrencies(ctx *gin.Context) {
+	if err := currencies.refresh(ctx, server.store); err != nil {
+		ctx.Error(fmt.Errorf("refresh currencies: %w", err))
+	}
+}
+
+// enabledCurrency reports whether accounts can be opened in a currency.
+func (server *Server) enabledCurrency(code string) error {
+	currency, ok := currencies.lookup(code)
+	if !ok || !currency.Enabled {
+		return fmt.Errorf("currency %s isn't offered", code)
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrenciesAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{
+		{Code: "JPY", Enabled: false},
+		{Code: "USD", Enabled: true},
+	}, nil)
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/currencies", nil)
+	require.NoError(t, err)
+	addAuthorizationHeader(t, req, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []currencyResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp, 2)
+	require.Equal(t, "JPY", rsp[0].Code)
+	require.Equal(t, "392", rsp[0].Numeric)
+	require.Equal(t, 0, rsp[0].MinorUnits)
+	require.False(t, rsp[0].Enabled)
+	require.Equal(t, "USD", rsp[1].Code)
+	require.Equal(t, 2, rsp[1].MinorUnits)
+	require.True(t, rsp[1].Enabled)
+}
+
+func TestCreateCurrencyAPI(t *testing.T) {
+	banker := util.RandomOwner()
+	catalog := []db.Currency{
+		{Code: "EUR", Enabled: true},
+		{Code: "GBP", Enabled: true},
+		{Code: "USD", Enabled: true},
+	}
+
+	testcase := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ok",
+			body: gin.H{"code": "GBP"},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateCurrencyParams{Code: "GBP", Enabled: true}
+				store.EXPECT().CreateCurrencyTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Currency{Code: "GBP", Enabled: true}, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(catalog, nil)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp currencyResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "GBP", rsp.Code)
+				require.Equal(t, "826", rsp.Numeric)
+				require.True(t, rsp.Enabled)
+
+				// The registry picks the new currency up straight away.
+				require.NoError(t, server.enabledCurrency("GBP"))
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "Disabled",
+			body: gin.H{"code": "GBP", "enabled": false},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateCurrencyParams{Code: "GBP", Enabled: false}
+				store.EXPECT().CreateCurrencyTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Currency{Code: "GBP"}, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(catalog, nil)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "ApprovalThreshold",
+			body: gin.H{"code": "GBP", "approval_threshold": 500000},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateCurrencyParams{Code: "GBP", Enabled: true, ApprovalThreshold: 500000}
+				store.EXPECT().CreateCurrencyTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.Currency{Code: "GBP", Enabled: true, ApprovalThreshold: 500000}, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(catalog, nil)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp currencyResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(500000), rsp.ApprovalThreshold)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotISO4217",
+			body: gin.H{"code": "XYZ"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCurrencyTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "LowerCase",
+			body: gin.H{"code": "gbp"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCurrencyTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "AlreadyExists",
+			body: gin.H{"code": "USD"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCurrencyTX(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{}, &pq.Error{Code: "23505"})
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{"code": "GBP"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCurrencyTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, "/currencies", bytes.NewBuffer(data))
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, server, recorder)
+		})
+	}
+}
+
+func TestUpdateCurrencyAPI(t *testing.T) {
+	banker := util.RandomOwner()
+
+	testcase := []struct {
+		name          string
+		code          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Disable",
+			code: "EUR",
+			body: gin.H{"enabled": false},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateCurrencyParams{Code: "EUR", Enabled: sql.NullBool{Bool: false, Valid: true}}
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Currency{Code: "EUR"}, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{
+					{Code: "EUR", Enabled: false},
+					{Code: "USD", Enabled: true},
+				}, nil)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Error(t, server.enabledCurrency("EUR"))
+				require.NoError(t, server.enabledCurrency("USD"))
+
+				// Disabled currencies still pass validation, for the
+				// accounts already held in them.
+				_, ok := currencies.lookup("EUR")
+				require.True(t, ok)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "ApprovalThreshold",
+			code: "EUR",
+			body: gin.H{"approval_threshold": 5000},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateCurrencyParams{Code: "EUR", ApprovalThreshold: sql.NullInt64{Int64: 5000, Valid: true}}
+				updated := db.Currency{Code: "EUR", Enabled: true, ApprovalThreshold: 5000}
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{updated}, nil)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp currencyResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Enabled)
+				require.Equal(t, int64(5000), rsp.ApprovalThreshold)
+				require.True(t, server.needsApproval("EUR", 5001))
+				require.False(t, server.needsApproval("EUR", 5000))
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "PayeeCoolingOffAmount",
+			code: "EUR",
+			body: gin.H{"payee_cooling_off_amount": 2500},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateCurrencyParams{Code: "EUR", PayeeCoolingOffAmount: sql.NullInt64{Int64: 2500, Valid: true}}
+				updated := db.Currency{Code: "EUR", Enabled: true, PayeeCoolingOffAmount: 2500}
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{updated}, nil)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp currencyResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(2500), rsp.PayeeCoolingOffAmount)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NegativeApprovalThreshold",
+			code: "EUR",
+			body: gin.H{"approval_threshold": -1},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotFound",
+			code: "GBP",
+			body: gin.H{"enabled": true},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{}, sql.ErrNoRows)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NothingToUpdate",
+			code: "EUR",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotBanker",
+			code: "EUR",
+			body: gin.H{"enabled": false},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/currencies/%s", tc.code)
+			req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(data))
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, server, recorder)
+		})
+	}
+}
+
+func TestCurrencyCatalogRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := util.RandomOwner()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Any()).Times(0)
+	server := newTestServer(t, store)
+	currencies.set([]db.Currency{
+		{Code: "EUR", Enabled: false},
+		{Code: "USD", Enabled: true},
+	})
+
+	for currency, status := range map[string]int{
+		// Accounts can't be opened in a disabled currency.
+		"EUR": http.StatusForbidden,
+		// A currency missing from the catalog fails validation.
+		"CAD": http.StatusBadRequest,
+	} {
+		data, err := json.Marshal(gin.H{"owner": owner, "currency": currency})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewBuffer(data))
+		require.NoError(t, err)
+		addAuthorizationHeader(t, req, server.tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+
+		server.router.ServeHTTP(recorder, req)
+		require.Equal(t, status, recorder.Code, currency)
+	}
+}
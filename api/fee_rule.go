@@ -0,0 +1,125 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var errFeeBounds = errors.New("min_fee can't be more than max_fee")
+
+type createFeeRuleRequest struct {
+	Currency string `json:"currency" binding:"required,currency"`
+	// Tier is left out for the rule of every tier without its own.
+	Tier       string `json:"tier" binding:"omitempty,alphanum,max=32"`
+	Flat       int64  `json:"flat" binding:"min=0"`
+	PercentBps int32  `json:"percent_bps" binding:"min=0,max=10000"`
+	MinFee     *int64 `json:"min_fee" binding:"omitempty,min=0"`
+	MaxFee     *int64 `json:"max_fee" binding:"omitempty,min=0"`
+}
+
+// createFeeRule adds the fee rule of a currency and tier. Amounts are in
+// minor units. Only bankers may do this.
+func (server *Server) createFeeRule(ctx *gin.Context) {
+	req := bindJson[createFeeRuleRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	if !requireBanker(ctx, "manage fee rules") {
+		return
+	}
+
+	if req.MinFee != nil && req.MaxFee != nil && *req.MinFee > *req.MaxFee {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errFeeBounds))
+		return
+	}
+
+	arg := db.CreateFeeRuleParams{
+		Currency:   req.Currency,
+		Tier:       sql.NullString{String: req.Tier, Valid: req.Tier != ""},
+		Flat:       req.Flat,
+		PercentBps: req.PercentBps,
+	}
+	if req.MinFee != nil {
+		arg.MinFee = sql.NullInt64{Int64: *req.MinFee, Valid: true}
+	}
+	if req.MaxFee != nil {
+		arg.MaxFee = sql.NullInt64{Int64: *req.MaxFee, Valid: true}
+	}
+
+	rule, err := server.store.CreateFeeRule(ctx, arg)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+type listFeeRulesRequest struct {
+	Currency string `form:"currency" binding:"omitempty,currency"`
+}
+
+// listFeeRules shows the fee rules, only those of one currency when it's
+// asked for. Only bankers may do this.
+func (server *Server) listFeeRules(ctx *gin.Context) {
+	var req listFeeRulesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !requireBanker(ctx, "manage fee rules") {
+		return
+	}
+
+	rules, err := server.store.ListFeeRules(ctx, sql.NullString{String: req.Currency, Valid: req.Currency != ""})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+type feeRuleURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// deleteFeeRule removes a fee rule, transfers it applied to fall back to the
+// rule of every tier or go free. Only bankers may do this.
+func (server *Server) deleteFeeRule(ctx *gin.Context) {
+	var uri feeRuleURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !requireBanker(ctx, "manage fee rules") {
+		return
+	}
+
+	if _, err := server.store.DeleteFeeRule(ctx, uri.ID); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,351 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func randomFeeRule() db.FeeRule {
+	return db.FeeRule{
+		ID:         util.RandomInt(1, 1000),
+		Currency:   util.RandomCurrency(),
+		Tier:       sql.NullString{String: "premium", Valid: true},
+		Flat:       util.RandomInt(0, 100),
+		PercentBps: int32(util.RandomInt(0, 100)),
+	}
+}
+
+func TestCreateFeeRuleAPI(t *testing.T) {
+	banker := util.RandomOwner()
+	rule := randomFeeRule()
+
+	testcase := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ok",
+			body: gin.H{
+				"currency":    rule.Currency,
+				"tier":        rule.Tier.String,
+				"flat":        rule.Flat,
+				"percent_bps": rule.PercentBps,
+				"min_fee":     10,
+				"max_fee":     500,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateFeeRuleParams{
+					Currency:   rule.Currency,
+					Tier:       rule.Tier,
+					Flat:       rule.Flat,
+					PercentBps: rule.PercentBps,
+					MinFee:     sql.NullInt64{Int64: 10, Valid: true},
+					MaxFee:     sql.NullInt64{Int64: 500, Valid: true},
+				}
+				store.EXPECT().CreateFeeRule(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rule, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.FeeRule
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, rule.ID, got.ID)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "EveryTier",
+			body: gin.H{"currency": rule.Currency, "flat": 25},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateFeeRuleParams{Currency: rule.Currency, Flat: 25}
+				store.EXPECT().CreateFeeRule(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.FeeRule{}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "MinAboveMax",
+			body: gin.H{"currency": rule.Currency, "min_fee": 500, "max_fee": 10},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "InvalidPercent",
+			body: gin.H{"currency": rule.Currency, "percent_bps": 10001},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "UnknownCurrency",
+			body: gin.H{"currency": "XYZ", "flat": 25},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "AlreadyExists",
+			body: gin.H{"currency": rule.Currency, "tier": rule.Tier.String},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRule(gomock.Any(), gomock.Any()).Times(1).Return(db.FeeRule{}, &pq.Error{Code: "23505"})
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{"currency": rule.Currency, "flat": 25},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFeeRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, "/fee-rules", bytes.NewBuffer(data))
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func TestListFeeRulesAPI(t *testing.T) {
+	banker := util.RandomOwner()
+	rules := []db.FeeRule{randomFeeRule(), randomFeeRule()}
+
+	testcase := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ok",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListFeeRules(gomock.Any(), gomock.Eq(sql.NullString{})).Times(1).Return(rules, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []db.FeeRule
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got, len(rules))
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:  "ByCurrency",
+			query: "?currency=EUR",
+			buildStub: func(store *mockdb.MockStore) {
+				currency := sql.NullString{String: "EUR", Valid: true}
+				store.EXPECT().ListFeeRules(gomock.Any(), gomock.Eq(currency)).Times(1).Return([]db.FeeRule{}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:  "UnknownCurrency",
+			query: "?currency=XYZ",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListFeeRules(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotBanker",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListFeeRules(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodGet, "/fee-rules"+tc.query, nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func TestDeleteFeeRuleAPI(t *testing.T) {
+	banker := util.RandomOwner()
+	rule := randomFeeRule()
+
+	testcase := []struct {
+		name          string
+		ID            int64
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ok",
+			ID:   rule.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteFeeRule(gomock.Any(), gomock.Eq(rule.ID)).Times(1).Return(rule, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotFound",
+			ID:   rule.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteFeeRule(gomock.Any(), gomock.Eq(rule.ID)).Times(1).Return(db.FeeRule{}, sql.ErrNoRows)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "InvalidID",
+			ID:   0,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteFeeRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotBanker",
+			ID:   rule.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteFeeRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, banker, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/fee-rules/%d", tc.ID), nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type holdResponse struct {
+	db.Hold
+	// AmountDecimal and CapturedAmountDecimal are the amounts in major units
+	// of the currency of the accounts, which both share.
+	AmountDecimal         string `json:"amount_decimal"`
+	CapturedAmountDecimal string `json:"captured_amount_decimal"`
+	Expired               bool   `json:"expired"`
+}
+
+// newHoldResponse describes a hold between accounts in the currency of
+// account, either of them.
+func newHoldResponse(hold db.Hold, account db.Account) (holdResponse, error) {
+	currency, err := money.Lookup(account.Currency)
+	if err != nil {
+		return holdResponse{}, err
+	}
+	return holdResponse{
+		Hold:                  hold,
+		AmountDecimal:         money.New(hold.Amount, currency).Decimal(),
+		CapturedAmountDecimal: money.New(hold.CapturedAmount, currency).Decimal(),
+		Expired:               hold.Expired(time.Now()),
+	}, nil
+}
+
+type createHoldRequest struct {
+	AccountID   int64        `json:"account_id" binding:"required,min=1"`
+	ToAccountID int64        `json:"to_account_id" binding:"required,min=1"`
+	Amount      money.Amount `json:"amount" binding:"required,gt=0"`
+	Currency    string       `json:"currency" binding:"required,currency"`
+	// ExpiresIn is the lifetime of the hold in seconds, defaults to the
+	// configured hold duration which is also the maximum.
+	ExpiresIn int64 `json:"expires_in" binding:"omitempty,min=1"`
+}
+
+func (server *Server) createHold(ctx *gin.Context) {
+	req := bindJson[createHoldRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	duration := server.config.HoldDuration
+	if req.ExpiresIn != 0 {
+		requested := time.Duration(req.ExpiresIn) * time.Second
+		if requested > duration {
+			err := fmt.Errorf("hold can't last longer than %s", duration)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		duration = requested
+	}
+
+	amount, err := resolveAmount(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.validAccount(ctx, req.AccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	if !server.authorizeAccount(ctx, account, db.MemberRoleSigner) {
+		return
+	}
+
+	if _, valid = server.validAccount(ctx, req.ToAccountID, req.Currency); !valid {
+		return
+	}
+
+	hold, err := server.store.PlaceHoldTX(ctx, db.PlaceHoldTxParams{
+		AccountID:   req.AccountID,
+		ToAccountID: req.ToAccountID,
+		Amount:      amount,
+		ExpiresAt:   time.Now().Add(duration),
+	})
+	if err != nil {
+		txErrorResponse(ctx, err)
+		return
+	}
+
+	rsp, err := newHoldResponse(hold, account)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type holdURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getHoldForParty loads the hold named in the URI and checks that the
+// authenticated user is a member with at least role of one of the accounts
+// in allowed, which is returned alongside.
+func (server *Server) getHoldForParty(ctx *gin.Context, allowed func(hold db.Hold) []int64, role string) (db.Hold, db.Account, bool) {
+	var req holdURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Hold{}, db.Account{}, false
+	}
+
+	hold, err := server.store.GetHold(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return hold, db.Account{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return hold, db.Account{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, accountID := range allowed(hold) {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return hold, db.Account{}, false
+		}
+		member, err := server.accountMember(ctx, account, authPayload.Username)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return hold, db.Account{}, false
+		}
+		if member.Allows(role) {
+			return hold, account, true
+		}
+	}
+
+	err = errors.New("hold doesn't belong to authenticated user")
+	ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	return hold, db.Account{}, false
+}
+
+func holdParties(hold db.Hold) []int64 {
+	return []int64{hold.AccountID, hold.ToAccountID}
+}
+
+func holdPayee(hold db.Hold) []int64 {
+	return []int64{hold.ToAccountID}
+}
+
+func (server *Server) getHold(ctx *gin.Context) {
+	hold, account, ok := server.getHoldForParty(ctx, holdParties, db.MemberRoleViewer)
+	if !ok {
+		return
+	}
+
+	rsp, err := newHoldResponse(hold, account)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type captureHoldRequest struct {
+	// Amount to capture, omit to capture the full hold. A decimal amount is
+	// in the currency of the held account.
+	Amount money.Amount `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// captureHold is called by the payee to settle the hold.
+func (server *Server) captureHold(ctx *gin.Context) {
+	var req captureHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hold, account, ok := server.getHoldForParty(ctx, holdPayee, db.MemberRoleSigner)
+	if !ok {
+		return
+	}
+
+	// The payee's account shares the currency of the held account.
+	amount, err := resolveAmount(req.Amount, account.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CaptureHoldTX(ctx, db.CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		txErrorResponse(ctx, err)
+		return
+	}
+
+	holdRsp, err := newHoldResponse(result.Hold, account)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	transfer, err := newTransferTxResponse(result.Transfer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, captureHoldResponse{
+		Hold:     holdRsp,
+		Transfer: transfer,
+	})
+}
+
+type captureHoldResponse struct {
+	Hold     holdResponse       `json:"hold"`
+	Transfer transferTxResponse `json:"transfer"`
+}
+
+// releaseHold is called by the payee to cancel the hold. The payer has to
+// wait for the hold to expire.
+func (server *Server) releaseHold(ctx *gin.Context) {
+	hold, account, ok := server.getHoldForParty(ctx, holdPayee, db.MemberRoleSigner)
+	if !ok {
+		return
+	}
+
+	hold, err := server.store.ReleaseHoldTX(ctx, hold.ID)
+	if err != nil {
+		txErrorResponse(ctx, err)
+		return
+	}
+
+	rsp, err := newHoldResponse(hold, account)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// listProducts returns the account products accounts can be opened with.
+func (server *Server) listProducts(ctx *gin.Context) {
+	products, err := server.store.ListProducts(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, products)
+}
+
+type listAccrualsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=31"`
+}
+
+// listInterestAccruals lists the daily interest accrued on an account, most
+// recent first. Accruals are posted to the account once their month is over.
+func (server *Server) listInterestAccruals(ctx *gin.Context) {
+	var req listAccrualsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleViewer)
+	if !ok {
+		return
+	}
+
+	accruals, err := server.store.ListInterestAccruals(ctx, db.ListInterestAccrualsParams{
+		AccountID: account.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accruals)
+}
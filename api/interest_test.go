@@ -0,0 +1,178 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListProductsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	products := []db.Product{
+		{Code: "checking", Type: "checking", DayCount: "act/365", Rounding: "half_even"},
+		{Code: "savings", Type: "savings", AnnualRateBps: 150, DayCount: "act/365", Rounding: "half_even"},
+	}
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListProducts(gomock.Any()).Times(1).Return(products, nil)
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/products", nil)
+	require.NoError(t, err)
+	addAuthorizationHeader(t, req, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+
+	server.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []db.Product
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, products, rsp)
+}
+
+func TestCreateSavingsAccountAPI(t *testing.T) {
+	owner := util.RandomOwner()
+
+	for product, want := range map[string]string{"": db.ProductChecking, "savings": "savings"} {
+		t.Run(want, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			arg := db.CreateAccountParams{Owner: owner, Currency: "USD", Product: want, Name: "USD"}
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().CreateAccountTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+				Return(db.Account{ID: 1, Owner: owner, Currency: "USD", Product: want}, nil)
+			server := newTestServer(t, store)
+
+			data, err := json.Marshal(gin.H{"owner": owner, "currency": "USD", "product": product})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewBuffer(data))
+			require.NoError(t, err)
+			addAuthorizationHeader(t, req, server.tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+
+			server.router.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestListInterestAccrualsAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+	account.Product = "savings"
+
+	accruals := []db.InterestAccrual{
+		{
+			AccountID:     account.ID,
+			AsOf:          time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+			Balance:       100000,
+			AnnualRateBps: 150,
+			AmountMicros:  4109589,
+		},
+		{
+			AccountID:     account.ID,
+			AsOf:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Balance:       100000,
+			AnnualRateBps: 150,
+			AmountMicros:  4109589,
+			JournalID:     sql.NullInt64{Int64: 7, Valid: true},
+			PostedAt:      sql.NullTime{Time: time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC), Valid: true},
+		},
+	}
+
+	testcase := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Ok",
+			query: "?page_id=1&page_size=31",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListInterestAccrualsParams{AccountID: account.ID, Limit: 31, Offset: 0}
+				store.EXPECT().ListInterestAccruals(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accruals, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.InterestAccrual
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp, 2)
+				require.Equal(t, int64(4109589), rsp[0].AmountMicros)
+				require.False(t, rsp[0].PostedAt.Valid)
+				require.Equal(t, int64(7), rsp[1].JournalID.Int64)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "?page_id=1&page_size=32",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListInterestAccruals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:  "UnauthorizedUser",
+			query: "?page_id=1&page_size=5",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListInterestAccruals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			expectNotMember(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%d/interest%s", account.ID, tc.query)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
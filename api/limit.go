@@ -0,0 +1,123 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type limitsResponse struct {
+	AccountID int64 `json:"account_id"`
+	// Effective limits, null means unlimited.
+	PerTransaction *int64 `json:"per_transaction"`
+	Daily          *int64 `json:"daily"`
+	Monthly        *int64 `json:"monthly"`
+	// Amounts already sent in the current daily and monthly windows.
+	UsedDaily   int64 `json:"used_daily"`
+	UsedMonthly int64 `json:"used_monthly"`
+}
+
+func nullInt64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
+func ptrNullInt64(n *int64) sql.NullInt64 {
+	if n == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *n, Valid: true}
+}
+
+func (server *Server) writeLimits(ctx *gin.Context, account db.Account) {
+	limits, err := server.store.GetEffectiveLimits(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	totals, err := server.store.GetOutgoingTotals(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limitsResponse{
+		AccountID:      account.ID,
+		PerTransaction: nullInt64Ptr(limits.PerTransaction),
+		Daily:          nullInt64Ptr(limits.Daily),
+		Monthly:        nullInt64Ptr(limits.Monthly),
+		UsedDaily:      totals.Daily,
+		UsedMonthly:    totals.Monthly,
+	})
+}
+
+func (server *Server) getAccountLimits(ctx *gin.Context) {
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleViewer)
+	if !ok {
+		return
+	}
+
+	server.writeLimits(ctx, account)
+}
+
+// updateLimitsRequest sets the account's own limits. A null limit falls back
+// to the currency default, and limits may only tighten the default.
+type updateLimitsRequest struct {
+	PerTransaction *int64 `json:"per_transaction" binding:"omitempty,gt=0"`
+	Daily          *int64 `json:"daily" binding:"omitempty,gt=0"`
+	Monthly        *int64 `json:"monthly" binding:"omitempty,gt=0"`
+}
+
+func (server *Server) updateAccountLimits(ctx *gin.Context) {
+	req := bindJson[updateLimitsRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleOwner)
+	if !ok {
+		return
+	}
+
+	defaults, err := server.store.GetCurrencyLimits(ctx, account.Currency)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	checks := []struct {
+		name  string
+		value *int64
+		max   sql.NullInt64
+	}{
+		{db.LimitPerTransaction, req.PerTransaction, defaults.PerTransaction},
+		{db.LimitDaily, req.Daily, defaults.Daily},
+		{db.LimitMonthly, req.Monthly, defaults.Monthly},
+	}
+	for _, check := range checks {
+		if check.value != nil && check.max.Valid && *check.value > check.max.Int64 {
+			err := fmt.Errorf("%s limit can't exceed the %s default of %d", check.name, account.Currency, check.max.Int64)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	_, err = server.store.UpsertAccountLimits(ctx, db.UpsertAccountLimitsParams{
+		AccountID:      account.ID,
+		PerTransaction: ptrNullInt64(req.PerTransaction),
+		Daily:          ptrNullInt64(req.Daily),
+		Monthly:        ptrNullInt64(req.Monthly),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.writeLimits(ctx, account)
+}
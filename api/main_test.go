@@ -1,24 +1,36 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"os"
 	"testing"
 	"time"
 
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
 	db "github.com/aryan-more/simple_bank/db/sqlc"
 	"github.com/aryan-more/simple_bank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
 
 	config := util.Config{
-		TokenKey:   util.RandomString(32),
-		AccessTime: time.Minute,
+		TokenKey:         util.RandomString(32),
+		AccessTime:       time.Minute,
+		HoldDuration:     time.Hour,
+		PayeeCoolingOff:  time.Hour,
+		ApprovalDuration: time.Hour,
 	}
 	server, err := NewServer(store, config)
 	require.NoError(t, err)
+	currencies.set([]db.Currency{
+		{Code: "CAD", Enabled: true, ApprovalThreshold: 100000, PayeeCoolingOffAmount: 1000},
+		{Code: "EUR", Enabled: true, ApprovalThreshold: 100000, PayeeCoolingOffAmount: 1000},
+		{Code: "USD", Enabled: true, ApprovalThreshold: 100000, PayeeCoolingOffAmount: 1000},
+	})
 
 	return server
 
@@ -28,3 +40,29 @@ func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
 }
+
+// acceptedMember returns a member who accepted their invitation.
+func acceptedMember(accountID int64, username string, role string) db.AccountMember {
+	return db.AccountMember{
+		AccountID:  accountID,
+		Username:   username,
+		Role:       role,
+		AcceptedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+}
+
+// expectNotMember makes every user but the primary owner a stranger to every
+// account, unless the test case expected a membership first.
+func expectNotMember(store *mockdb.MockStore) {
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).AnyTimes().Return(db.AccountMember{}, sql.ErrNoRows)
+}
+
+// expectReadTX runs the function passed to ReadTX against the mock store.
+func expectReadTX(store *mockdb.MockStore) {
+	store.EXPECT().
+		ReadTX(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, fn func(q db.Querier) error) error {
+			return fn(store)
+		})
+}
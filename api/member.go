@@ -0,0 +1,217 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// accountMember returns the membership of username in account. The primary
+// owner is always an owner member, so it isn't looked up. Users who aren't
+// members get a membership without a role, which allows nothing, as does a
+// pending invitation.
+func (server *Server) accountMember(ctx *gin.Context, account db.Account, username string) (db.AccountMember, error) {
+	if account.Owner == username {
+		return db.AccountMember{
+			AccountID:  account.ID,
+			Username:   username,
+			Role:       db.MemberRoleOwner,
+			AddedBy:    username,
+			CreatedAt:  account.CreatedAt,
+			AcceptedAt: sql.NullTime{Time: account.CreatedAt, Valid: true},
+		}, nil
+	}
+
+	member, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  username,
+	})
+	if err == sql.ErrNoRows {
+		return db.AccountMember{}, nil
+	}
+	return member, err
+}
+
+// checkMember checks that username has at least role on account. On failure
+// it returns the HTTP status describing the error instead of writing it, so
+// callers handling many accounts can report errors per item.
+func (server *Server) checkMember(ctx *gin.Context, account db.Account, username string, role string) (int, error) {
+	member, err := server.accountMember(ctx, account, username)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if member.Role == "" || !member.AcceptedAt.Valid {
+		return http.StatusUnauthorized, fmt.Errorf("account [%d] doesn't belong to authenticated user", account.ID)
+	}
+	if !member.Allows(role) {
+		return http.StatusForbidden, fmt.Errorf("account [%d] needs a %s, authenticated user is a %s", account.ID, role, member.Role)
+	}
+	return http.StatusOK, nil
+}
+
+// authorizeAccount is checkMember for handlers of a single account.
+func (server *Server) authorizeAccount(ctx *gin.Context, account db.Account, role string) bool {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	status, err := server.checkMember(ctx, account, authPayload.Username, role)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+// listAccountMembers lists who can use an account and how, pending
+// invitations included.
+func (server *Server) listAccountMembers(ctx *gin.Context) {
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleViewer)
+	if !ok {
+		return
+	}
+
+	members, err := server.store.ListAccountMembers(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+type addMemberRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Role     string `json:"role" binding:"required,oneof=owner signer viewer"`
+}
+
+// addAccountMember invites another user to an account with a role, which
+// they only get once they accept. Only owners may do this.
+func (server *Server) addAccountMember(ctx *gin.Context) {
+	req := bindJson[addMemberRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleOwner)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	member, err := server.store.CreateAccountMember(ctx, db.CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  req.Username,
+		Role:      req.Role,
+		AddedBy:   authPayload.Username,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("user %s not found", req.Username)))
+				return
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(fmt.Errorf("%s is already a member or invited", req.Username)))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// listMemberInvites lists the invitations to accounts the authenticated
+// user hasn't answered yet.
+func (server *Server) listMemberInvites(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	invites, err := server.store.ListMemberInvites(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invites)
+}
+
+// acceptAccountMember accepts the authenticated user's invitation to an
+// account, giving them its role. Invitations are declined by leaving the
+// account.
+func (server *Server) acceptAccountMember(ctx *gin.Context) {
+	account, ok := server.getURIAccount(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	member, err := server.store.AcceptAccountMember(ctx, db.AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  authPayload.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := fmt.Errorf("no pending invitation to account [%d]", account.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+type memberURIRequest struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required"`
+}
+
+// removeAccountMember takes a user's role on an account away. Owners may
+// remove anyone but the primary owner, other members only themselves.
+// Invitees decline an invitation by removing themselves.
+func (server *Server) removeAccountMember(ctx *gin.Context) {
+	var uri memberURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	var account db.Account
+	var ok bool
+	if uri.Username == authPayload.Username {
+		account, ok = server.getURIAccount(ctx)
+	} else {
+		account, ok = server.getMemberAccount(ctx, db.MemberRoleOwner)
+	}
+	if !ok {
+		return
+	}
+
+	if uri.Username == account.Owner {
+		err := errors.New("the primary owner can't be removed")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	removed, err := server.store.DeleteAccountMember(ctx, db.DeleteAccountMemberParams{
+		AccountID: account.ID,
+		Username:  uri.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if removed == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("%s isn't a member", uri.Username)))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"expvar"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getMetrics serves the process' expvar variables, including the store's
+// transaction retry counters under "db_tx". Only bankers may read them.
+func (server *Server) getMetrics(ctx *gin.Context) {
+	if !requireBanker(ctx, "read metrics") {
+		return
+	}
+
+	expvar.Handler().ServeHTTP(ctx.Writer, ctx.Request)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
 	"github.com/gin-gonic/gin"
 )
 
@@ -50,3 +51,15 @@ func authMiddleWare(tokenMaker token.Maker) gin.HandlerFunc {
 
 	}
 }
+
+// requireBanker writes a 403 response and returns false unless the
+// authenticated user is a banker.
+func requireBanker(ctx *gin.Context, action string) bool {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.BankerRole {
+		err := fmt.Errorf("only bankers can %s", action)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return false
+	}
+	return true
+}
@@ -19,10 +19,11 @@ func addAuthorizationHeader(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
 
-	token, err := tokenMaker.CreateToken(username, duration)
+	token, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
@@ -39,7 +40,7 @@ func TestAuthorizationMiddleware(t *testing.T) {
 		{
 			name: "Ok",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(5), time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(5), util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -48,7 +49,7 @@ func TestAuthorizationMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedType",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, "oauth2", util.RandomString(5), time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, "oauth2", util.RandomString(5), util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -65,7 +66,7 @@ func TestAuthorizationMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, "", util.RandomString(5), time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, "", util.RandomString(5), util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -75,7 +76,7 @@ func TestAuthorizationMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(5), -time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomString(5), util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/aryan-more/simple_bank/pain"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxPain001Size bounds the size of an uploaded pain.001 file.
+const maxPain001Size = 5 << 20
+
+const pain002ContentType = "application/xml"
+
+var errPainBatchAborted = errors.New("not executed, another transaction in the file was rejected")
+
+type pain001Request struct {
+	// Mode is how the transfers are executed, as for batch transfers. It
+	// defaults to best_effort.
+	Mode string `form:"mode" binding:"omitempty,oneof=atomic best_effort"`
+}
+
+// importPain001 executes the credit transfers of an uploaded pain.001 file
+// as a batch and answers with a pain.002 status report.
+func (server *Server) importPain001(ctx *gin.Context) {
+	var req pain001Request
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = batchModeBestEffort
+	}
+
+	initiation, err := pain.Parse(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxPain001Size))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if count := initiation.Count(); count > maxBatchTransfers {
+		err := fmt.Errorf("file has %d transactions, at most %d are allowed", count, maxBatchTransfers)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	report := &pain.Report{
+		MessageID:  "STS-" + initiation.MessageID,
+		CreatedAt:  time.Now(),
+		Initiation: initiation,
+		Statuses:   make([][]pain.Status, len(initiation.Payments)),
+	}
+
+	if report.Rejection = initiation.CheckTotals(); report.Rejection != nil {
+		writePain002(ctx, http.StatusBadRequest, report)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	accounts := make(map[int64]db.Account)
+
+	// positions maps each transfer that passed validation back to its place
+	// in the file.
+	type position struct{ payment, transfer int }
+	var params []db.TransferTxParams
+	var positions []position
+
+	for i, payment := range initiation.Payments {
+		report.Statuses[i] = make([]pain.Status, len(payment.Transfers))
+		for j, transfer := range payment.Transfers {
+			if transfer.Rejection != nil {
+				report.Statuses[i][j] = *transfer.Rejection
+				continue
+			}
+
+			arg, status, err := server.validPainTransfer(ctx, payment, transfer, authPayload.Username, accounts)
+			if err != nil {
+				if status == http.StatusInternalServerError {
+					ctx.JSON(status, errorResponse(err))
+					return
+				}
+				report.Statuses[i][j] = *pain.Reject(painReason(status, err), err)
+				continue
+			}
+
+			params = append(params, arg)
+			positions = append(positions, position{i, j})
+		}
+	}
+
+	atomic := req.Mode == batchModeAtomic
+	if atomic && len(params) < initiation.Count() {
+		for _, p := range positions {
+			report.Statuses[p.payment][p.transfer] = *pain.Reject(pain.ReasonNarrative, errPainBatchAborted)
+		}
+		writePain002(ctx, http.StatusBadRequest, report)
+		return
+	}
+
+	status := http.StatusOK
+	if len(params) > 0 {
+		result, err := server.store.BatchTransferTX(ctx, db.BatchTransferTxParams{
+			Transfers: params,
+			Atomic:    atomic,
+			ChunkSize: server.config.BatchChunkSize,
+		})
+		if err != nil {
+			txErrorResponse(ctx, err)
+			return
+		}
+
+		for k, item := range result.Items {
+			p := positions[k]
+			switch {
+			case item.Err == nil:
+				reference := strconv.FormatInt(item.Result.Transfer.ID, 10)
+				report.Statuses[p.payment][p.transfer] = pain.Accept(reference)
+			case errors.Is(item.Err, db.ErrBatchAborted):
+				report.Statuses[p.payment][p.transfer] = *pain.Reject(pain.ReasonNarrative, errPainBatchAborted)
+			default:
+				report.Statuses[p.payment][p.transfer] = *pain.Reject(painTxReason(item.Err), item.Err)
+				if atomic {
+					status = txErrorStatus(item.Err)
+				}
+			}
+		}
+	}
+
+	writePain002(ctx, status, report)
+}
+
+// validPainTransfer checks a credit transfer of a pain.001 file the same
+// way a batch transfer item is checked.
+func (server *Server) validPainTransfer(ctx *gin.Context, payment pain.Payment, transfer pain.CreditTransfer, username string, accounts map[int64]db.Account) (db.TransferTxParams, int, error) {
+	item := transferRequest{
+		FromAccountID:     payment.FromAccountID,
+		ToAccountID:       transfer.ToAccountID,
+		Amount:            money.MinorUnits(transfer.Amount),
+		Currency:          transfer.Currency,
+		Description:       transfer.Remittance,
+		ExternalReference: transfer.Reference(),
+	}
+	if err := binding.Validator.ValidateStruct(&item); err != nil {
+		return db.TransferTxParams{}, http.StatusBadRequest, err
+	}
+
+	return server.validTransferItem(ctx, item, username, accounts)
+}
+
+// painReason returns the reason code for a transfer that failed validation.
+func painReason(status int, err error) string {
+	switch {
+	case errors.As(err, new(*currencyMismatchError)):
+		return pain.ReasonCurrency
+	case status == http.StatusNotFound:
+		return pain.ReasonIncorrectAccount
+	case status == http.StatusUnauthorized:
+		return pain.ReasonTransactionForbidden
+	default:
+		return pain.ReasonNarrative
+	}
+}
+
+// painTxReason returns the reason code for a transfer that failed to execute.
+func painTxReason(err error) string {
+	switch {
+	case errors.Is(err, db.ErrInsufficientFunds):
+		return pain.ReasonInsufficientFunds
+	case errors.As(err, new(*db.LimitExceededError)):
+		return pain.ReasonAmountNotAllowed
+	default:
+		return pain.ReasonNarrative
+	}
+}
+
+func writePain002(ctx *gin.Context, status int, report *pain.Report) {
+	data, err := report.Marshal()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.Data(status, pain002ContentType, data)
+}
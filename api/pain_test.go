@@ -0,0 +1,281 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/pain"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type painTestTransfer struct {
+	endToEndID string
+	to         int64
+	amount     string
+	currency   string
+}
+
+// pain001Document returns a pain.001 file paying transfers out of account
+// from.
+func pain001Document(from int64, transactions int, transfers ...painTestTransfer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+<CstmrCdtTrfInitn>
+<GrpHdr><MsgId>MSG-1</MsgId><CreDtTm>2024-03-28T09:00:00</CreDtTm><NbOfTxs>%d</NbOfTxs></GrpHdr>
+<PmtInf><PmtInfId>PMT-1</PmtInfId><PmtMtd>TRF</PmtMtd>
+<DbtrAcct><Id><Othr><Id>%d</Id></Othr></Id></DbtrAcct>
+`, transactions, from)
+	for _, transfer := range transfers {
+		fmt.Fprintf(&b, `<CdtTrfTxInf><PmtId><EndToEndId>%s</EndToEndId></PmtId>
+<Amt><InstdAmt Ccy="%s">%s</InstdAmt></Amt>
+<CdtrAcct><Id><Othr><Id>%d</Id></Othr></Id></CdtrAcct>
+<RmtInf><Ustrd>invoice %s</Ustrd></RmtInf></CdtTrfTxInf>
+`, transfer.endToEndID, transfer.currency, transfer.amount, transfer.to, transfer.endToEndID)
+	}
+	b.WriteString("</PmtInf>\n</CstmrCdtTrfInitn>\n</Document>\n")
+	return b.String()
+}
+
+func TestImportPain001API(t *testing.T) {
+	owner := util.RandomOwner()
+
+	from := randomAccountWithCurrency(owner, "USD")
+	from.ID = 10
+	to1 := randomAccountWithCurrency(util.RandomOwner(), "USD")
+	to1.ID = 20
+	to2 := randomAccountWithCurrency(util.RandomOwner(), "EUR")
+	to2.ID = 21
+
+	valid := []painTestTransfer{
+		{"E2E-1", to1.ID, "10.50", "USD"},
+		{"E2E-2", to1.ID, "2", "USD"},
+	}
+	mixed := []painTestTransfer{
+		valid[0],
+		{"E2E-2", to2.ID, "2", "USD"},
+		{"E2E-3", 99, "1", "USD"},
+	}
+
+	expectAccounts := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(to1.ID)).Times(1).Return(to1, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(to2.ID)).AnyTimes().Return(to2, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(99))).AnyTimes().Return(db.Account{}, sql.ErrNoRows)
+	}
+
+	depositor := func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+	}
+
+	testcase := []struct {
+		name          string
+		query         string
+		body          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: pain001Document(from.ID, 2, valid...),
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+
+				arg := db.BatchTransferTxParams{
+					Transfers: []db.TransferTxParams{
+						{FromAccountID: from.ID, ToAccountID: to1.ID, Amount: 1050, Description: "invoice E2E-1", ExternalReference: "E2E-1"},
+						{FromAccountID: from.ID, ToAccountID: to1.ID, Amount: 200, Description: "invoice E2E-2", ExternalReference: "E2E-2"},
+					},
+				}
+				result := db.BatchTransferTxResult{Items: make([]db.BatchTransferItemResult, 2)}
+				result.Items[0].Result.Transfer.ID = 501
+				result.Items[1].Result.Transfer.ID = 502
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, pain002ContentType, recorder.Header().Get("Content-Type"))
+
+				body := recorder.Body.String()
+				require.Contains(t, body, pain.Pain002Namespace)
+				require.Contains(t, body, "<OrgnlMsgId>MSG-1</OrgnlMsgId>")
+				require.Contains(t, body, "<GrpSts>ACSC</GrpSts>")
+				require.Contains(t, body, "<AcctSvcrRef>501</AcctSvcrRef>")
+				require.Contains(t, body, "<AcctSvcrRef>502</AcctSvcrRef>")
+			},
+			setupAuth: depositor,
+		},
+		{
+			name: "BestEffortInvalidItems",
+			body: pain001Document(from.ID, 3, mixed...),
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+
+				result := db.BatchTransferTxResult{Items: []db.BatchTransferItemResult{{Err: db.ErrInsufficientFunds}}}
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "<GrpSts>RJCT</GrpSts>")
+				require.Contains(t, body, "<Cd>"+pain.ReasonInsufficientFunds+"</Cd>")
+				require.Contains(t, body, "<Cd>"+pain.ReasonCurrency+"</Cd>")
+				require.Contains(t, body, "<Cd>"+pain.ReasonIncorrectAccount+"</Cd>")
+			},
+			setupAuth: depositor,
+		},
+		{
+			name:  "AtomicInvalidItem",
+			query: "?mode=atomic",
+			body:  pain001Document(from.ID, 3, mixed...),
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "<GrpSts>RJCT</GrpSts>")
+				require.Contains(t, body, errPainBatchAborted.Error())
+				require.NotContains(t, body, "<TxSts>ACSC</TxSts>")
+			},
+			setupAuth: depositor,
+		},
+		{
+			name:  "AtomicFailed",
+			query: "?mode=atomic",
+			body:  pain001Document(from.ID, 2, valid...),
+			buildStub: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+
+				result := db.BatchTransferTxResult{Items: []db.BatchTransferItemResult{
+					{Err: db.ErrBatchAborted},
+					{Err: db.ErrInsufficientFunds},
+				}}
+				store.EXPECT().
+					BatchTransferTX(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.BatchTransferTxParams) (db.BatchTransferTxResult, error) {
+						require.True(t, arg.Atomic)
+						return result, nil
+					})
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "<Cd>"+pain.ReasonInsufficientFunds+"</Cd>")
+			},
+			setupAuth: depositor,
+		},
+		{
+			name: "NotOwner",
+			body: pain001Document(from.ID, 1, valid[0]),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "<Cd>"+pain.ReasonTransactionForbidden+"</Cd>")
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "TransactionCountMismatch",
+			body: pain001Document(from.ID, 3, valid...),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "<GrpSts>RJCT</GrpSts>")
+				require.Contains(t, body, "<Cd>"+pain.ReasonTransactionCount+"</Cd>")
+			},
+			setupAuth: depositor,
+		},
+		{
+			name: "InvalidDocument",
+			body: "<Document><CstmrCdtTrfInitn>",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+			},
+			setupAuth: depositor,
+		},
+		{
+			name:  "InvalidMode",
+			query: "?mode=all",
+			body:  pain001Document(from.ID, 2, valid...),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: depositor,
+		},
+		{
+			name: "InternalError",
+			body: pain001Document(from.ID, 2, valid...),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrConnDone)
+				store.EXPECT().BatchTransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+			setupAuth: depositor,
+		},
+		{
+			name: "NoAuthorization",
+			body: pain001Document(from.ID, 2, valid...),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			expectNotMember(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, "/transfers/pain001"+tc.query, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/xml")
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
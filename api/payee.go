@@ -0,0 +1,220 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// createPayeeRequest names the payee's account either by AccountID or by
+// Recipient, the same way transferRequest does.
+type createPayeeRequest struct {
+	Nickname  string `json:"nickname" binding:"required,max=64"`
+	AccountID int64  `json:"account_id" binding:"required_without=Recipient,gte=0"`
+	Recipient string `json:"recipient" binding:"required_without=AccountID,excluded_with=AccountID,max=254"`
+	// RecipientAccountName picks one of the recipient's accounts when they
+	// hold more than one in the currency.
+	RecipientAccountName string `json:"recipient_account_name" binding:"excluded_without=Recipient,max=64"`
+	Currency             string `json:"currency" binding:"required,currency"`
+}
+
+func (server *Server) createPayee(ctx *gin.Context) {
+	req := bindJson[createPayeeRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	var account db.Account
+	var status int
+	var err error
+	if req.Recipient != "" {
+		_, account, status, err = server.resolveRecipient(ctx, req.Recipient, req.RecipientAccountName, req.Currency)
+	} else {
+		account, status, err = server.lookupAccount(ctx, req.AccountID, req.Currency)
+	}
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	payee, err := server.store.CreatePayee(ctx, db.CreatePayeeParams{
+		Owner:     authPayload.Username,
+		Nickname:  req.Nickname,
+		AccountID: account.ID,
+		Currency:  account.Currency,
+	})
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payee)
+}
+
+type payeeURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getOwnedPayee loads the payee named in the URI and checks that it belongs
+// to the authenticated user.
+func (server *Server) getOwnedPayee(ctx *gin.Context) (db.Payee, bool) {
+	var req payeeURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Payee{}, false
+	}
+
+	payee, err := server.store.GetPayee(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return payee, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return payee, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Username != payee.Owner {
+		err := errors.New("payee doesn't belong to authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return payee, false
+	}
+
+	return payee, true
+}
+
+func (server *Server) getPayee(ctx *gin.Context) {
+	payee, ok := server.getOwnedPayee(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payee)
+}
+
+type listPayeesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listPayees(ctx *gin.Context) {
+	var req listPayeesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	payees, err := server.store.ListPayees(ctx, db.ListPayeesParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payees)
+}
+
+type updatePayeeRequest struct {
+	Nickname string `json:"nickname" binding:"required,max=64"`
+}
+
+// updatePayee only renames the payee. Pointing a payee at another account
+// has to go through a new payee so the cooling-off period applies again.
+func (server *Server) updatePayee(ctx *gin.Context) {
+	req := bindJson[updatePayeeRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	payee, ok := server.getOwnedPayee(ctx)
+	if !ok {
+		return
+	}
+
+	payee, err := server.store.UpdatePayeeNickname(ctx, db.UpdatePayeeNicknameParams{
+		ID:       payee.ID,
+		Nickname: req.Nickname,
+	})
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payee)
+}
+
+func (server *Server) deletePayee(ctx *gin.Context) {
+	payee, ok := server.getOwnedPayee(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeletePayee(ctx, payee.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// resolvePayee returns the account a transfer to payeeID pays into. Large
+// transfers to payees added within the cooling-off period are refused.
+func (server *Server) resolvePayee(ctx *gin.Context, payeeID int64, username string, currency string, amount int64) (int64, int, error) {
+	payee, err := server.store.GetPayee(ctx, payeeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, http.StatusNotFound, err
+		}
+		return 0, http.StatusInternalServerError, err
+	}
+
+	if payee.Owner != username {
+		err := fmt.Errorf("payee [%d] doesn't belong to authenticated user", payee.ID)
+		return 0, http.StatusUnauthorized, err
+	}
+
+	if payee.Currency != currency {
+		err := fmt.Errorf("payee [%d] currency mismatch %s vs %s", payee.ID, payee.Currency, currency)
+		return 0, http.StatusBadRequest, err
+	}
+
+	// Each currency caps what a new payee can receive while cooling off.
+	catalog, _ := currencies.lookup(currency)
+	coolingOffEnds := payee.CreatedAt.Add(server.config.PayeeCoolingOff)
+	if amount > catalog.PayeeCoolingOffAmount && time.Now().Before(coolingOffEnds) {
+		err := fmt.Errorf("payee [%d] can't receive more than %d until %s",
+			payee.ID, catalog.PayeeCoolingOffAmount, coolingOffEnds.Format(time.RFC3339))
+		return 0, http.StatusUnprocessableEntity, err
+	}
+
+	return payee.AccountID, http.StatusOK, nil
+}
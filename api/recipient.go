@@ -0,0 +1,97 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type lookupRecipientRequest struct {
+	Recipient string `form:"recipient" binding:"required,max=254"`
+	// RecipientAccountName picks one of the recipient's accounts when they
+	// hold more than one in the currency.
+	RecipientAccountName string `form:"recipient_account_name" binding:"max=64"`
+	Currency             string `form:"currency" binding:"required,currency"`
+}
+
+type lookupRecipientResponse struct {
+	Recipient string `json:"recipient"`
+	Name      string `json:"name"`
+	Currency  string `json:"currency"`
+}
+
+// lookupRecipient lets a sender confirm who a username or email pays before
+// sending money. Only a masked name is returned, never the account ID.
+func (server *Server) lookupRecipient(ctx *gin.Context) {
+	var req lookupRecipientRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, _, status, err := server.resolveRecipient(ctx, req.Recipient, req.RecipientAccountName, req.Currency)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, lookupRecipientResponse{
+		Recipient: req.Recipient,
+		Name:      maskName(user.FullName),
+		Currency:  req.Currency,
+	})
+}
+
+// resolveRecipient finds the account a username or email receives the given
+// currency in. A recipient with several accounts in the currency has to be
+// given the name of one, money is never sent to a guessed account. Like
+// lookupAccount it returns the status instead of writing it.
+func (server *Server) resolveRecipient(ctx *gin.Context, recipient string, accountName string, currency string) (db.User, db.Account, int, error) {
+	user, err := server.store.GetUserByUsernameOrEmail(ctx, recipient)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return user, db.Account{}, http.StatusNotFound, fmt.Errorf("recipient %s not found", recipient)
+		}
+		return user, db.Account{}, http.StatusInternalServerError, err
+	}
+
+	arg := db.ListRecipientAccountsParams{
+		Owner:    user.Username,
+		Currency: currency,
+	}
+	if accountName != "" {
+		arg.Name = sql.NullString{String: accountName, Valid: true}
+	}
+	accounts, err := server.store.ListRecipientAccounts(ctx, arg)
+	if err != nil {
+		return user, db.Account{}, http.StatusInternalServerError, err
+	}
+
+	switch {
+	case len(accounts) == 0 && accountName != "":
+		return user, db.Account{}, http.StatusNotFound, fmt.Errorf("recipient %s has no %s account called %q", recipient, currency, accountName)
+	case len(accounts) == 0:
+		return user, db.Account{}, http.StatusNotFound, fmt.Errorf("recipient %s has no %s account", recipient, currency)
+	case len(accounts) > 1:
+		err := fmt.Errorf("recipient %s has more than one %s account, name one with recipient_account_name", recipient, currency)
+		return user, db.Account{}, http.StatusConflict, err
+	}
+
+	return user, accounts[0], http.StatusOK, nil
+}
+
+// maskName keeps the first letter of every word, "John Smith" becomes
+// "J*** S****".
+func maskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(first) + strings.Repeat("*", utf8.RuneCountInString(word[size:]))
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupRecipientAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.FullName = "Jane Doe"
+	account := randomAccount(user.Username)
+	sender := util.RandomOwner()
+
+	testcase := []struct {
+		name          string
+		recipient     string
+		accountName   string
+		currency      string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Ok",
+			recipient: user.Email,
+			currency:  account.Currency,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Eq(db.ListRecipientAccountsParams{
+					Owner:    user.Username,
+					Currency: account.Currency,
+				})).Times(1).Return([]db.Account{account}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp map[string]interface{}
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, "J*** D**", rsp["name"])
+				require.Equal(t, user.Email, rsp["recipient"])
+				require.NotContains(t, rsp, "account_id")
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, sender, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "UserNotFound",
+			recipient: user.Username,
+			currency:  account.Currency,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, sender, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "NoAccountInCurrency",
+			recipient: user.Username,
+			currency:  account.Currency,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, sender, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "Ambiguous",
+			recipient: user.Username,
+			currency:  account.Currency,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{account, account}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, sender, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:        "ByAccountName",
+			recipient:   user.Username,
+			accountName: "Savings",
+			currency:    account.Currency,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Eq(db.ListRecipientAccountsParams{
+					Owner:    user.Username,
+					Currency: account.Currency,
+					Name:     sql.NullString{String: "Savings", Valid: true},
+				})).Times(1).Return([]db.Account{account}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, sender, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "InvalidCurrency",
+			recipient: user.Username,
+			currency:  "XYZ",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, sender, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			recipient: user.Username,
+			currency:  account.Currency,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodGet, "/recipients", nil)
+			require.NoError(t, err)
+
+			q := req.URL.Query()
+			q.Add("recipient", tc.recipient)
+			if tc.accountName != "" {
+				q.Add("recipient_account_name", tc.accountName)
+			}
+			q.Add("currency", tc.currency)
+			req.URL.RawQuery = q.Encode()
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func TestMaskName(t *testing.T) {
+	require.Equal(t, "J*** S****", maskName("John Smith"))
+	require.Equal(t, "Z**", maskName("  Zoë "))
+	require.Equal(t, "", maskName(""))
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// getReconciliation returns the latest reconciliation run.
+func (server *Server) getReconciliation(ctx *gin.Context) {
+	if !requireBanker(ctx, "read reconciliation reports") {
+		return
+	}
+
+	run, err := server.store.GetLatestReconciliationRun(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, run)
+}
+
+type listReconciliationsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listReconciliations(ctx *gin.Context) {
+	var req listReconciliationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !requireBanker(ctx, "read reconciliation reports") {
+		return
+	}
+
+	runs, err := server.store.ListReconciliationRuns(ctx, db.ListReconciliationRunsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// runReconciliation reconciles the ledger straight away instead of waiting
+// for the scheduled run.
+func (server *Server) runReconciliation(ctx *gin.Context) {
+	if !requireBanker(ctx, "run reconciliation") {
+		return
+	}
+
+	run, _, err := server.store.ReconcileTX(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, run)
+}
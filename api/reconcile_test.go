@@ -0,0 +1,145 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomReconciliationRun() db.ReconciliationRun {
+	return db.ReconciliationRun{
+		ID:              util.RandomInt(1, 1000),
+		StartedAt:       time.Now().Add(-time.Second),
+		FinishedAt:      time.Now(),
+		DriftedAccounts: 1,
+		Report:          json.RawMessage(`{"accounts":[{"account_id":1,"drift":10}],"transfers":[]}`),
+	}
+}
+
+func TestReconciliationAPI(t *testing.T) {
+	run := randomReconciliationRun()
+
+	testcase := []struct {
+		name          string
+		method        string
+		url           string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Run",
+			method: http.MethodPost,
+			url:    "/reconciliations",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTX(gomock.Any()).Times(1).Return(run, db.ReconcileReport{}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireMatchReconciliationRun(t, recorder, run)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "RunNotBanker",
+			method: http.MethodPost,
+			url:    "/reconciliations",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTX(gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name:   "Latest",
+			method: http.MethodGet,
+			url:    "/reconciliations/latest",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLatestReconciliationRun(gomock.Any()).Times(1).Return(run, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireMatchReconciliationRun(t, recorder, run)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "LatestNeverRun",
+			method: http.MethodGet,
+			url:    "/reconciliations/latest",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLatestReconciliationRun(gomock.Any()).Times(1).Return(db.ReconciliationRun{}, sql.ErrNoRows)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/reconciliations?page_id=1&page_size=5",
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListReconciliationRunsParams{Limit: 5, Offset: 0}
+				store.EXPECT().ListReconciliationRuns(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.ReconciliationRun{run}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []db.ReconciliationRun
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got, 1)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func requireMatchReconciliationRun(t *testing.T, recorder *httptest.ResponseRecorder, run db.ReconciliationRun) {
+	var got db.ReconciliationRun
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	require.Equal(t, run.ID, got.ID)
+	require.Equal(t, run.DriftedAccounts, got.DriftedAccounts)
+	require.JSONEq(t, string(run.Report), string(got.Report))
+}
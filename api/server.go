@@ -1,11 +1,16 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"net/http"
 
 	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
 	"github.com/aryan-more/simple_bank/token"
 	"github.com/aryan-more/simple_bank/util"
+	"github.com/aryan-more/simple_bank/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -16,6 +21,7 @@ type Server struct {
 	config     util.Config
 	tokenMaker token.Maker
 	router     *gin.Engine
+	webhooks   *webhook.Deliverer
 }
 
 func NewServer(store db.Store, config util.Config) (*Server, error) {
@@ -30,11 +36,15 @@ func NewServer(store db.Store, config util.Config) (*Server, error) {
 		store:      store,
 		tokenMaker: tokenMaker,
 		config:     config,
+		webhooks: webhook.NewDeliverer(store, webhook.NewClient(config.WebhookTimeout),
+			config.WebhookMaxAttempts, config.WebhookBackoff, config.WebhookMaxBackoff),
 	}
 
 	v, ok := binding.Validator.Engine().(*validator.Validate)
 	if ok {
-		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("currency", currencies.validCurrency)
+		v.RegisterValidation("webhook_event", validWebhookEvent)
+		v.RegisterCustomTypeFunc(amountValue, money.Amount{})
 	}
 
 	server.setupRouter()
@@ -49,10 +59,66 @@ func (server *Server) setupRouter() {
 	router.POST("/users/login", server.loginUser)
 
 	authRoutes := router.Group("/").Use(authMiddleWare(server.tokenMaker))
+	authRoutes.PUT("/users/:username/settings", server.updateUserSettings)
 	authRoutes.POST("/accounts", server.createAccount)
 	authRoutes.GET("/accounts/:id", server.getAccount)
 	authRoutes.GET("/accounts", server.listAccount)
+	authRoutes.GET("/accounts/:id/limits", server.getAccountLimits)
+	authRoutes.PUT("/accounts/:id/limits", server.updateAccountLimits)
+	authRoutes.PUT("/accounts/:id/tier", server.updateAccountTier)
+	authRoutes.POST("/fee-rules", server.createFeeRule)
+	authRoutes.GET("/fee-rules", server.listFeeRules)
+	authRoutes.DELETE("/fee-rules/:id", server.deleteFeeRule)
+	authRoutes.PUT("/accounts/:id/overdraft", server.updateAccountOverdraft)
+	authRoutes.GET("/accounts/:id/balance", server.getAccountBalance)
+	authRoutes.GET("/accounts/:id/entries", server.listAccountEntries)
+	authRoutes.GET("/accounts/:id/statements", server.getAccountStatement)
+	authRoutes.GET("/accounts/:id/interest", server.listInterestAccruals)
+	authRoutes.GET("/accounts/:id/members", server.listAccountMembers)
+	authRoutes.POST("/accounts/:id/members", server.addAccountMember)
+	authRoutes.POST("/accounts/:id/members/accept", server.acceptAccountMember)
+	authRoutes.DELETE("/accounts/:id/members/:username", server.removeAccountMember)
+	authRoutes.GET("/invites", server.listMemberInvites)
 	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.GET("/transfers", server.listTransfers)
+	authRoutes.GET("/transfers/:id", server.getTransfer)
+	authRoutes.POST("/transfers/batch", server.createBatchTransfer)
+	authRoutes.POST("/transfers/pain001", server.importPain001)
+	authRoutes.GET("/recipients", server.lookupRecipient)
+
+	authRoutes.GET("/approvals", server.listApprovals)
+	authRoutes.GET("/approvals/:id", server.getApproval)
+	authRoutes.POST("/approvals/:id/approve", server.approveTransfer)
+	authRoutes.POST("/approvals/:id/reject", server.rejectTransfer)
+
+	authRoutes.POST("/payees", server.createPayee)
+	authRoutes.GET("/payees", server.listPayees)
+	authRoutes.GET("/payees/:id", server.getPayee)
+	authRoutes.PUT("/payees/:id", server.updatePayee)
+	authRoutes.DELETE("/payees/:id", server.deletePayee)
+
+	authRoutes.POST("/holds", server.createHold)
+	authRoutes.GET("/holds/:id", server.getHold)
+	authRoutes.POST("/holds/:id/capture", server.captureHold)
+	authRoutes.POST("/holds/:id/release", server.releaseHold)
+
+	authRoutes.POST("/webhooks", server.createWebhook)
+	authRoutes.GET("/webhooks", server.listWebhooks)
+	authRoutes.GET("/webhooks/:id", server.getWebhook)
+	authRoutes.DELETE("/webhooks/:id", server.deleteWebhook)
+	authRoutes.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+	authRoutes.POST("/webhooks/:id/test", server.testWebhook)
+
+	authRoutes.GET("/products", server.listProducts)
+
+	authRoutes.GET("/currencies", server.listCurrencies)
+	authRoutes.POST("/currencies", server.createCurrency)
+	authRoutes.PUT("/currencies/:code", server.updateCurrency)
+
+	authRoutes.GET("/metrics", server.getMetrics)
+	authRoutes.GET("/reconciliations", server.listReconciliations)
+	authRoutes.GET("/reconciliations/latest", server.getReconciliation)
+	authRoutes.POST("/reconciliations", server.runReconciliation)
 
 	server.router = router
 }
@@ -63,6 +129,49 @@ func errorResponse(err error) gin.H {
 	}
 }
 
+// txErrorStatus maps errors returned by store transactions to a status code,
+// business rule violations are reported as unprocessable requests.
+func txErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrSelfApproval):
+		return http.StatusForbidden
+	case errors.As(err, new(*db.LimitExceededError)),
+		errors.Is(err, db.ErrInsufficientFunds),
+		errors.Is(err, db.ErrFeeOverflow),
+		errors.Is(err, money.ErrOverflow),
+		errors.Is(err, db.ErrHoldNotActive),
+		errors.Is(err, db.ErrHoldExpired),
+		errors.Is(err, db.ErrCaptureExceedsHold),
+		errors.Is(err, db.ErrBatchAborted),
+		errors.Is(err, db.ErrApprovalNotPending),
+		errors.Is(err, db.ErrApprovalExpired),
+		errors.As(err, new(*db.InvalidTransitionError)),
+		errors.As(err, new(*db.UnbalancedJournalError)),
+		errors.Is(err, db.ErrEmptyJournal):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func txErrorResponse(ctx *gin.Context, err error) {
+	rsp := errorResponse(err)
+
+	var limitErr *db.LimitExceededError
+	if errors.As(err, &limitErr) {
+		rsp["limit"] = limitErr
+	}
+
+	var failedErr *db.TransferFailedError
+	if errors.As(err, &failedErr) {
+		rsp["transfer"] = failedErr.Transfer
+	}
+
+	ctx.JSON(txErrorStatus(err), rsp)
+}
+
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/statement"
+	"github.com/gin-gonic/gin"
+)
+
+type statementRequest struct {
+	// From and To are RFC 3339 timestamps or dates, a date in To includes
+	// the whole day.
+	From   string `form:"from" binding:"required"`
+	To     string `form:"to" binding:"required"`
+	Format string `form:"format" binding:"omitempty,oneof=csv pdf camt053 ofx"`
+}
+
+// getAccountStatement streams the statement of an account over a period.
+func (server *Server) getAccountStatement(ctx *gin.Context) {
+	var req statementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	from, err := parseTime(req.From, false)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	to, err := parseTime(req.To, true)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if to.Before(from) {
+		err := errors.New("statement period ends before it starts")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.getMemberAccount(ctx, db.MemberRoleViewer)
+	if !ok {
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = statement.FormatCSV
+	}
+	ctx.Header("Content-Type", statement.ContentType(req.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		statement.Filename(account.ID, from, to, req.Format)))
+
+	w := statement.NewWriter(req.Format, ctx.Writer)
+	err = server.store.ReadTX(ctx, func(q db.Querier) error {
+		return statement.Generate(ctx, q, account, from, to, w)
+	})
+	if err != nil {
+		// Once the statement started streaming the status is gone, all that
+		// is left is to cut the response short.
+		if !ctx.Writer.Written() {
+			ctx.Header("Content-Type", "")
+			ctx.Header("Content-Disposition", "")
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		log.Printf("Cannot write statement of account %d: %s", account.ID, err)
+		ctx.Abort()
+	}
+}
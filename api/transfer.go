@@ -5,17 +5,29 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
 	"github.com/aryan-more/simple_bank/token"
 	"github.com/gin-gonic/gin"
 )
 
+// transferRequest names the payee by exactly one of ToAccountID, Recipient,
+// a username or email resolved to the recipient's account in Currency, or
+// PayeeID, one of the sender's saved payees. RecipientAccountName picks one
+// of the recipient's accounts when they hold more than one in Currency.
+// Amount is minor units or a decimal string in Currency.
 type transferRequest struct {
-	FromAccountID int64  `json:"from_account" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	Currency      string `json:"currency" binding:"required,currency"`
+	FromAccountID        int64        `json:"from_account" binding:"required,min=1"`
+	ToAccountID          int64        `json:"to_account" binding:"required_without_all=Recipient PayeeID,gte=0"`
+	Recipient            string       `json:"recipient" binding:"excluded_with=ToAccountID PayeeID,max=254"`
+	RecipientAccountName string       `json:"recipient_account_name" binding:"excluded_without=Recipient,max=64"`
+	PayeeID              int64        `json:"payee_id" binding:"excluded_with=ToAccountID Recipient,gte=0"`
+	Amount               money.Amount `json:"amount" binding:"required,gt=0"`
+	Currency             string       `json:"currency" binding:"required,currency"`
+	Description          string       `json:"description" binding:"max=140"`
+	ExternalReference    string       `json:"external_reference" binding:"max=64"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -25,65 +37,247 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	amount, err := resolveAmount(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	account, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
 
-	if account.Owner != authPayload.Username {
-		err := errors.New("from account doesn't belong to authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !server.authorizeAccount(ctx, account, db.MemberRoleSigner) {
 		return
 	}
 
-	if !valid {
-		err := errors.New("invalid currency")
+	toAccountID, status, err := server.transferTarget(ctx, req, amount, authPayload.Username, make(map[int64]db.Account))
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	arg := db.TransferTxParams{
+		Amount:            amount,
+		FromAccountID:     req.FromAccountID,
+		ToAccountID:       toAccountID,
+		Description:       req.Description,
+		ExternalReference: req.ExternalReference,
+	}
+
+	if server.needsApproval(req.Currency, amount) {
+		server.requestApproval(ctx, arg, account, authPayload.Username)
+		return
+	}
+
+	result, err := server.store.TransferTX(ctx, arg)
+
+	if err != nil {
+		txErrorResponse(ctx, err)
+		return
+	}
+
+	rsp, err := newTransferTxResponse(result)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// transferTxResponse is the result of a transfer with its amounts also in
+// major units of the sender's currency.
+type transferTxResponse struct {
+	db.TransferTxResult
+	AmountDecimal      string `json:"amount_decimal"`
+	FeeDecimal         string `json:"fee_decimal"`
+	FromBalanceDecimal string `json:"from_balance_decimal"`
+	ToBalanceDecimal   string `json:"to_balance_decimal"`
+}
+
+func newTransferTxResponse(result db.TransferTxResult) (transferTxResponse, error) {
+	currency, err := money.Lookup(result.FromAccount.Currency)
+	if err != nil {
+		return transferTxResponse{}, err
+	}
+	return transferTxResponse{
+		TransferTxResult:   result,
+		AmountDecimal:      money.New(result.Transfer.Amount, currency).Decimal(),
+		FeeDecimal:         money.New(result.Fee, currency).Decimal(),
+		FromBalanceDecimal: money.New(result.FromAccount.Balance, currency).Decimal(),
+		ToBalanceDecimal:   money.New(result.ToAccount.Balance, currency).Decimal(),
+	}, nil
+}
+
+type transferURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type transferResponse struct {
+	db.Transfer
+	History []db.TransferStatusHistory `json:"history"`
+}
+
+// getTransfer returns a transfer with its status history to the members of
+// either party.
+func (server *Server) getTransfer(ctx *gin.Context) {
+	var req transferURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	transfer, err := server.store.GetTransfer(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-	if !valid {
-		err := errors.New("invalid currency")
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	party := false
+	for _, accountID := range []int64{transfer.FromAccountID, transfer.ToAccountID} {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		member, err := server.accountMember(ctx, account, authPayload.Username)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if member.Allows(db.MemberRoleViewer) {
+			party = true
+			break
+		}
+	}
+
+	if !party {
+		err := errors.New("transfer doesn't belong to authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	history, err := server.store.ListTransferStatusHistory(ctx, transfer.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transferResponse{
+		Transfer: transfer,
+		History:  history,
+	})
+}
+
+type listTransfersRequest struct {
+	AccountID int64 `form:"account_id" binding:"required,min=1"`
+	// Query matches the description or external reference, case insensitive.
+	Query    string `form:"q" binding:"max=140"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// listTransfers returns the transfer history of an account, newest first.
+func (server *Server) listTransfers(ctx *gin.Context) {
+	var req listTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	arg := db.TransferTxParams{
-		Amount:        req.Amount,
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	result, err := server.store.TransferTX(ctx, arg)
+	if !server.authorizeAccount(ctx, account, db.MemberRoleViewer) {
+		return
+	}
 
+	transfers, err := server.store.SearchTransfers(ctx, db.SearchTransfersParams{
+		AccountID: req.AccountID,
+		Query:     likeEscaper.Replace(req.Query),
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+// transferTarget resolves the account a transfer request pays into. Accounts
+// looked up by ID are cached in accounts.
+func (server *Server) transferTarget(ctx *gin.Context, req transferRequest, amount int64, username string, accounts map[int64]db.Account) (int64, int, error) {
+	switch {
+	case req.PayeeID != 0:
+		return server.resolvePayee(ctx, req.PayeeID, username, req.Currency, amount)
+	case req.Recipient != "":
+		_, account, status, err := server.resolveRecipient(ctx, req.Recipient, req.RecipientAccountName, req.Currency)
+		return account.ID, status, err
+	default:
+		account, status, err := server.cachedAccount(ctx, req.ToAccountID, req.Currency, accounts)
+		return account.ID, status, err
+	}
 }
 
 func (server *Server) validAccount(ctx *gin.Context, accounID int64, currency string) (db.Account, bool) {
+	account, status, err := server.lookupAccount(ctx, accounID, currency)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
+
+// lookupAccount loads an account and checks its currency. On failure it
+// returns the HTTP status describing the error instead of writing it, so
+// callers handling many accounts can report errors per item.
+func (server *Server) lookupAccount(ctx *gin.Context, accounID int64, currency string) (db.Account, int, error) {
 	account, err := server.store.GetAccount(ctx, accounID)
 
 	if err != nil {
 
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return account, false
+			return account, http.StatusNotFound, err
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return account, false
+		return account, http.StatusInternalServerError, err
 
 	}
 
 	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch %s vs %s", account.ID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return account, false
+		return account, http.StatusBadRequest, &currencyMismatchError{account, currency}
 	}
 
-	return account, true
+	return account, http.StatusOK, nil
+}
+
+// currencyMismatchError is returned for an account that isn't held in the
+// currency of a transfer.
+type currencyMismatchError struct {
+	account  db.Account
+	currency string
+}
+
+func (e *currencyMismatchError) Error() string {
+	return fmt.Sprintf("account [%d] currency mismatch %s vs %s", e.account.ID, e.account.Currency, e.currency)
 }
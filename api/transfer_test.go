@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	mockdb "github.com/aryan-more/simple_bank/db/mock"
 	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
 	"github.com/aryan-more/simple_bank/token"
 	"github.com/aryan-more/simple_bank/util"
 	"github.com/gin-gonic/gin"
@@ -34,6 +36,11 @@ func TestTransferAPI(t *testing.T) {
 	}
 	user3 := randomAccountWithCurrency(util.RandomOwner(), invalidCurrency)
 
+	recipient, _ := randomUser(t)
+	recipient.Username = username2
+
+	payee := randomPayee(username1, user2)
+
 	testcase := []struct {
 		name          string
 		body          gin.H
@@ -59,13 +66,500 @@ func TestTransferAPI(t *testing.T) {
 					ToAccountID:   user2.ID,
 				}
 
-				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{Amount: arg.Amount}, FromAccount: user1}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "Signer",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: user1.ID, Username: username2})).
+					Times(1).Return(acceptedMember(user1.ID, username2, db.MemberRoleSigner), nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(1).Return(user2, nil)
+
+				arg := db.TransferTxParams{
+					Amount:        int64(amount),
+					FromAccountID: user1.ID,
+					ToAccountID:   user2.ID,
+				}
+
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{Amount: arg.Amount}, FromAccount: user1}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username2, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "Viewer",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: user1.ID, Username: username2})).
+					Times(1).Return(acceptedMember(user1.ID, username2, db.MemberRoleViewer), nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username2, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "WithMemo",
+			body: gin.H{
+				"from_account":       user1.ID,
+				"to_account":         user2.ID,
+				"amount":             amount,
+				"currency":           currency,
+				"description":        "rent for march",
+				"external_reference": "INV-0042",
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(1).Return(user2, nil)
+
+				arg := db.TransferTxParams{
+					Amount:            int64(amount),
+					FromAccountID:     user1.ID,
+					ToAccountID:       user2.ID,
+					Description:       "rent for march",
+					ExternalReference: "INV-0042",
+				}
+
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{Amount: arg.Amount}, FromAccount: user1}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "ByRecipient",
+			body: gin.H{
+				"from_account": user1.ID,
+				"recipient":    recipient.Email,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(recipient.Email)).Times(1).Return(recipient, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Eq(db.ListRecipientAccountsParams{
+					Owner:    username2,
+					Currency: currency,
+				})).Times(1).Return([]db.Account{user2}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(0)
+
+				arg := db.TransferTxParams{
+					Amount:        int64(amount),
+					FromAccountID: user1.ID,
+					ToAccountID:   user2.ID,
+				}
+
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{Amount: arg.Amount}, FromAccount: user1}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "RecipientNotFound",
+			body: gin.H{
+				"from_account": user1.ID,
+				"recipient":    recipient.Username,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{}, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "AmbiguousRecipient",
+			body: gin.H{
+				"from_account": user1.ID,
+				"recipient":    recipient.Username,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				savings := user2
+				savings.ID++
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{user2, savings}, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "RecipientAccountName",
+			body: gin.H{
+				"from_account":           user1.ID,
+				"recipient":              recipient.Username,
+				"recipient_account_name": "Savings",
+				"amount":                 amount,
+				"currency":               currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().ListRecipientAccounts(gomock.Any(), gomock.Eq(db.ListRecipientAccountsParams{
+					Owner:    username2,
+					Currency: currency,
+					Name:     sql.NullString{String: "Savings", Valid: true},
+				})).Times(1).Return([]db.Account{user2}, nil)
+
+				arg := db.TransferTxParams{
+					Amount:        int64(amount),
+					FromAccountID: user1.ID,
+					ToAccountID:   user2.ID,
+				}
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{Amount: arg.Amount}, FromAccount: user1}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "AccountNameWithoutRecipient",
+			body: gin.H{
+				"from_account":           user1.ID,
+				"to_account":             user2.ID,
+				"recipient_account_name": "Savings",
+				"amount":                 amount,
+				"currency":               currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "RecipientAndAccount",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"recipient":    recipient.Username,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "NoRecipient",
+			body: gin.H{
+				"from_account": user1.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "ByPayee",
+			body: gin.H{
+				"from_account": user1.ID,
+				"payee_id":     payee.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+
+				arg := db.TransferTxParams{
+					Amount:        int64(amount),
+					FromAccountID: user1.ID,
+					ToAccountID:   user2.ID,
+				}
+
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{Amount: arg.Amount}, FromAccount: user1}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "PayeeCoolingOff",
+			body: gin.H{
+				"from_account": user1.ID,
+				"payee_id":     payee.ID,
+				"amount":       5000,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "PayeeCooledOff",
+			body: gin.H{
+				"from_account": user1.ID,
+				"payee_id":     payee.ID,
+				"amount":       5000,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				oldPayee := payee
+				oldPayee.CreatedAt = time.Now().Add(-2 * time.Hour)
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(oldPayee, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{FromAccount: user1}, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "PayeeOfAnotherUser",
+			body: gin.H{
+				"from_account": user1.ID,
+				"payee_id":     payee.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				otherPayee := payee
+				otherPayee.Owner = util.RandomOwner()
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(otherPayee, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "PayeeAndRecipient",
+			body: gin.H{
+				"from_account": user1.ID,
+				"payee_id":     payee.ID,
+				"recipient":    recipient.Username,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "NeedsApproval",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       200000,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(1).Return(user2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RequestApprovalTX(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.RequestApprovalTxParams) (db.TransferApproval, error) {
+						require.Equal(t, user1.ID, arg.FromAccountID)
+						require.Equal(t, user2.ID, arg.ToAccountID)
+						require.Equal(t, int64(200000), arg.Amount)
+						require.Equal(t, username1, arg.RequestedBy)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Second)
+						return db.TransferApproval{ID: 1, Status: db.ApprovalStatusPending, ExpiresAt: arg.ExpiresAt}, nil
+					})
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "DecimalAmount",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       "0.10",
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(1).Return(user2, nil)
+
+				arg := db.TransferTxParams{
+					Amount:        int64(amount),
+					FromAccountID: user1.ID,
+					ToAccountID:   user2.ID,
+				}
+
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{Amount: arg.Amount}, FromAccount: user1}, nil)
 			},
 			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp transferTxResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int64(amount), rsp.Transfer.Amount)
+				require.Equal(t, "0.10", rsp.AmountDecimal)
+				require.Equal(t, "0.00", rsp.FeeDecimal)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "DecimalAmountTooPrecise",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       "0.105",
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "NegativeDecimalAmount",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       "-0.10",
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "MemoTooLong",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       amount,
+				"currency":     currency,
+				"description":  util.RandomString(141),
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -92,7 +586,96 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "Overflow",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(1).Return(user2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, money.ErrOverflow)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "LimitExceeded",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(1).Return(user2, nil)
+
+				limitErr := &db.LimitExceededError{Limit: db.LimitDaily, Max: 5, Total: int64(amount)}
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, limitErr)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var rsp struct {
+					Limit db.LimitExceededError `json:"limit"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, db.LimitDaily, rsp.Limit.Limit)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "FailureRecorded",
+			body: gin.H{
+				"from_account": user1.ID,
+				"to_account":   user2.ID,
+				"amount":       amount,
+				"currency":     currency,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user1.ID)).Times(1).Return(user1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(user2.ID)).Times(1).Return(user2, nil)
+
+				failed := db.Transfer{
+					ID:            util.RandomInt(1, 1000),
+					FromAccountID: user1.ID,
+					ToAccountID:   user2.ID,
+					Amount:        int64(amount),
+					Status:        db.TransferStatusFailed,
+					FailureReason: db.ErrInsufficientFunds.Error(),
+				}
+				failedErr := &db.TransferFailedError{Transfer: failed, Err: db.ErrInsufficientFunds}
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{Transfer: failed}, failedErr)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var rsp struct {
+					Error    string      `json:"error"`
+					Transfer db.Transfer `json:"transfer"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, db.ErrInsufficientFunds.Error(), rsp.Error)
+				require.Equal(t, db.TransferStatusFailed, rsp.Transfer.Status)
+				require.Equal(t, db.ErrInsufficientFunds.Error(), rsp.Transfer.FailureReason)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -108,7 +691,7 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -129,7 +712,7 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username2, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username2, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -150,7 +733,7 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, user3.Owner, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, user3.Owner, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -171,7 +754,7 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -192,7 +775,7 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
 			},
 		},
 		{
@@ -213,7 +796,7 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, time.Minute)
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, username1, util.DepositorRole, time.Minute)
 			},
 		},
 	}
@@ -229,6 +812,7 @@ func TestTransferAPI(t *testing.T) {
 
 				store := mockdb.NewMockStore(ctrl)
 				tc.buildStub(store)
+				expectNotMember(store)
 				server := newTestServer(t, store)
 
 				data, err := json.Marshal(tc.body)
@@ -250,3 +834,199 @@ func TestTransferAPI(t *testing.T) {
 	}
 
 }
+
+func TestListTransfersAPI(t *testing.T) {
+	owner := util.RandomOwner()
+	account := randomAccount(owner)
+
+	transfers := make([]db.Transfer, 5)
+	for i := range transfers {
+		transfers[i] = db.Transfer{
+			ID:            util.RandomInt(1, 1000),
+			FromAccountID: account.ID,
+			ToAccountID:   util.RandomInt(1, 1000),
+			Amount:        util.RandomMoney(),
+			Description:   "invoice 100%",
+		}
+	}
+
+	testcase := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Ok",
+			query: "invoice 100%",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.SearchTransfersParams{
+					AccountID: account.ID,
+					Query:     `invoice 100\%`,
+					Limit:     5,
+					Offset:    5,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.Transfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp, len(transfers))
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, owner, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			expectNotMember(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodGet, "/transfers", nil)
+			require.NoError(t, err)
+
+			q := req.URL.Query()
+			q.Add("account_id", fmt.Sprintf("%d", account.ID))
+			q.Add("q", tc.query)
+			q.Add("page_id", "2")
+			q.Add("page_size", "5")
+			req.URL.RawQuery = q.Encode()
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
+func TestGetTransferAPI(t *testing.T) {
+	sender := randomAccount(util.RandomOwner())
+	receiver := randomAccountWithCurrency(util.RandomOwner(), sender.Currency)
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: sender.ID,
+		ToAccountID:   receiver.ID,
+		Amount:        util.RandomMoney(),
+		Status:        db.TransferStatusCompleted,
+	}
+	history := []db.TransferStatusHistory{
+		{TransferID: transfer.ID, ToStatus: db.TransferStatusProcessing},
+		{TransferID: transfer.ID, FromStatus: sql.NullString{String: db.TransferStatusProcessing, Valid: true}, ToStatus: db.TransferStatusCompleted},
+	}
+
+	testcase := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Receiver",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sender.ID)).Times(1).Return(sender, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(receiver.ID)).Times(1).Return(receiver, nil)
+				store.EXPECT().ListTransferStatusHistory(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(history, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp transferResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, db.TransferStatusCompleted, rsp.Status)
+				require.Len(t, rsp.History, 2)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, receiver.Owner, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(sender, nil)
+				store.EXPECT().ListTransferStatusHistory(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.Transfer{}, sql.ErrNoRows)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, sender.Owner, util.DepositorRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			expectNotMember(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/transfers/%d", transfer.ID)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
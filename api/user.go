@@ -7,6 +7,7 @@ import (
 	"time"
 
 	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
 	"github.com/aryan-more/simple_bank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
@@ -16,6 +17,9 @@ type userResponse struct {
 	Username          string    `json:"username"`
 	FullName          string    `json:"full_name"`
 	Email             string    `json:"email"`
+	Role              string    `json:"role"`
+	MultipleAccounts  bool      `json:"multiple_accounts"`
+	MaxAccounts       int32     `json:"max_accounts"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 }
@@ -25,6 +29,9 @@ func createUserResponse(user db.User) userResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              user.Role,
+		MultipleAccounts:  user.MultipleAccounts,
+		MaxAccounts:       user.MaxAccounts,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -57,7 +64,7 @@ func (server *Server) createUser(ctx *gin.Context) {
 		Email:          req.Email,
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	user, err := server.store.CreateUserTX(ctx, arg)
 
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
@@ -117,7 +124,7 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, err := server.tokenMaker.CreateToken(req.Username, server.config.AccessTime)
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTime)
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -132,6 +139,63 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+type userURIRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// updateUserSettingsRequest changes the account settings of a user, omitted
+// settings are left as they are.
+type updateUserSettingsRequest struct {
+	// MultipleAccounts opts in to more than one account per currency.
+	MultipleAccounts *bool `json:"multiple_accounts"`
+	// MaxAccounts caps the accounts the user may own, only bankers may
+	// change it.
+	MaxAccounts *int32 `json:"max_accounts" binding:"omitempty,min=1,max=100"`
+}
+
+// updateUserSettings lets users change their own settings and bankers those
+// of anyone.
+func (server *Server) updateUserSettings(ctx *gin.Context) {
+	req := bindJson[updateUserSettingsRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	var uri userURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username != authPayload.Username && !requireBanker(ctx, "change other users' settings") {
+		return
+	}
+	if req.MaxAccounts != nil && !requireBanker(ctx, "change account caps") {
+		return
+	}
+
+	arg := db.UpdateUserSettingsParams{Username: uri.Username}
+	if req.MultipleAccounts != nil {
+		arg.MultipleAccounts = sql.NullBool{Bool: *req.MultipleAccounts, Valid: true}
+	}
+	if req.MaxAccounts != nil {
+		arg.MaxAccounts = sql.NullInt32{Int32: *req.MaxAccounts, Valid: true}
+	}
+
+	user, err := server.store.UpdateUserSettings(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createUserResponse(user))
+}
+
 func bindJson[T any](ctx *gin.Context) *T {
 	body := new(T)
 	if err := ctx.ShouldBindJSON(body); err != nil {
@@ -10,9 +10,11 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	mockdb "github.com/aryan-more/simple_bank/db/mock"
 	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
 	"github.com/aryan-more/simple_bank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -63,6 +65,7 @@ func randomUser(t *testing.T) (user db.User, password string) {
 		FullName:       util.RandomOwner(),
 		HashedPassword: hashedPassword,
 		Email:          util.RandomEmail(util.RandomOwner()),
+		Role:           util.DepositorRole,
 	}
 
 	return
@@ -93,7 +96,7 @@ func TestCreateUserAPI(t *testing.T) {
 				}
 				store.
 					EXPECT().
-					CreateUser(gomock.Any(), eqCreateUserMatcher(arg, password)).
+					CreateUserTX(gomock.Any(), eqCreateUserMatcher(arg, password)).
 					Times(1).
 					Return(user, nil)
 			},
@@ -111,7 +114,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Username,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -126,7 +129,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Username,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -141,7 +144,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Username,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserTX(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -157,7 +160,7 @@ func TestCreateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTX(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.User{}, sql.ErrConnDone)
 			},
@@ -175,7 +178,7 @@ func TestCreateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTX(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(
 						db.User{},
@@ -331,6 +334,134 @@ func TestLoginUser(t *testing.T) {
 	}
 }
 
+func TestUpdateUserSettingsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	updated := user
+	updated.MultipleAccounts = true
+
+	testcase := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		responseCheck func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OptIn",
+			body: gin.H{"multiple_accounts": true},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateUserSettingsParams{
+					Username:         user.Username,
+					MultipleAccounts: sql.NullBool{Bool: true, Valid: true},
+				}
+				store.EXPECT().UpdateUserSettings(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.MultipleAccounts)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "OtherUser",
+			body: gin.H{"multiple_accounts": true},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "OwnCap",
+			body: gin.H{"max_accounts": 50},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+		},
+		{
+			name: "BankerSetsCap",
+			body: gin.H{"max_accounts": 50},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateUserSettingsParams{
+					Username:    user.Username,
+					MaxAccounts: sql.NullInt32{Int32: 50, Valid: true},
+				}
+				store.EXPECT().UpdateUserSettings(gomock.Any(), gomock.Eq(arg)).Times(1).Return(user, nil)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "InvalidCap",
+			body: gin.H{"max_accounts": 0},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"multiple_accounts": false},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserSettings(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+			},
+			responseCheck: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationHeader(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.BankerRole, time.Minute)
+			},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/users/%s/settings", user.Username)
+			req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, req)
+			tc.responseCheck(t, recorder)
+		})
+	}
+}
+
 func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
@@ -1,15 +1,34 @@
 package api
 
 import (
-	"github.com/aryan-more/simple_bank/util"
+	"reflect"
+
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/aryan-more/simple_bank/webhook"
 	"github.com/go-playground/validator/v10"
 )
 
-var validCurrency validator.Func = func(fl validator.FieldLevel) bool {
-	currency, ok := fl.Field().Interface().(string)
-	if ok && util.ValidCurrency(currency) {
-		return true
+// amountValue lets amount fields be validated like integers, e.g. with gt=0,
+// by their sign. Their value depends on a currency validation doesn't know.
+func amountValue(field reflect.Value) any {
+	if amount, ok := field.Interface().(money.Amount); ok {
+		return amount.Sign()
 	}
+	return nil
+}
+
+// resolveAmount converts an amount of a request into minor units of
+// currency.
+func resolveAmount(amount money.Amount, code string) (int64, error) {
+	currency, err := money.Lookup(code)
+	if err != nil {
+		return 0, err
+	}
+	m, err := amount.In(currency)
+	return m.Amount(), err
+}
 
-	return false
+var validWebhookEvent validator.Func = func(fl validator.FieldLevel) bool {
+	eventType, ok := fl.Field().Interface().(string)
+	return ok && webhook.ValidEventType(eventType)
 }
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/token"
+	"github.com/aryan-more/simple_bank/webhook"
+	"github.com/aryan-more/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// webhookResponse leaves out the signing secret, which is only shown once
+// when the subscription is created.
+type webhookResponse struct {
+	ID         int64     `json:"id"`
+	Owner      string    `json:"owner"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newWebhookResponse(subscription db.WebhookSubscription) webhookResponse {
+	return webhookResponse{
+		ID:         subscription.ID,
+		Owner:      subscription.Owner,
+		URL:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,webhook_event"`
+}
+
+type createWebhookResponse struct {
+	webhookResponse
+	Secret string `json:"secret"`
+}
+
+func (server *Server) createWebhook(ctx *gin.Context) {
+	req := bindJson[createWebhookRequest](ctx)
+	if req == nil {
+		return
+	}
+
+	if err := webhook.CheckURL(ctx, req.URL); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	subscription, err := server.store.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Owner:      authPayload.Username,
+		Url:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createWebhookResponse{
+		webhookResponse: newWebhookResponse(subscription),
+		Secret:          subscription.Secret,
+	})
+}
+
+type webhookURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getOwnedWebhook loads the subscription named in the URI and checks that it
+// belongs to the authenticated user.
+func (server *Server) getOwnedWebhook(ctx *gin.Context) (db.WebhookSubscription, bool) {
+	var req webhookURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.WebhookSubscription{}, false
+	}
+
+	subscription, err := server.store.GetWebhookSubscription(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return subscription, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return subscription, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Username != subscription.Owner {
+		err := errors.New("webhook doesn't belong to authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return subscription, false
+	}
+
+	return subscription, true
+}
+
+func (server *Server) getWebhook(ctx *gin.Context) {
+	subscription, ok := server.getOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(subscription))
+}
+
+type listWebhooksRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	var req listWebhooksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	subscriptions, err := server.store.ListWebhookSubscriptions(ctx, db.ListWebhookSubscriptionsParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]webhookResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		rsp[i] = newWebhookResponse(subscription)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	subscription, ok := server.getOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteWebhookSubscription(ctx, subscription.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+type listWebhookDeliveriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listWebhookDeliveries shows the delivery log of a subscription, newest
+// first.
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var req listWebhookDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	subscription, ok := server.getOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          req.PageSize,
+		Offset:         (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// testWebhook sends a sample event to the subscription straight away and
+// returns the logged delivery. The delivery is logged already leased so the
+// dispatcher leaves it alone while it's sent here. A failed test is retried
+// like any other delivery.
+func (server *Server) testWebhook(ctx *gin.Context) {
+	subscription, ok := server.getOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	sample, err := json.Marshal(gin.H{"message": "this is a test event"})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload, err := json.Marshal(worker.Message{
+		AggregateType: db.AggregateUser,
+		AggregateID:   subscription.Owner,
+		EventType:     webhook.EventTest,
+		Payload:       sample,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	leasedAt := time.Now()
+	lease := server.webhooks.Lease()
+	delivery, err := server.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventType:      webhook.EventTest,
+		Payload:        payload,
+		NextAttemptAt:  sql.NullTime{Time: leasedAt.Add(lease), Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	delivery, err = server.webhooks.Deliver(ctx, webhook.Target{
+		DeliveryID: delivery.ID,
+		EventType:  delivery.EventType,
+		Attempts:   delivery.Attempts,
+		Payload:    delivery.Payload,
+		URL:        subscription.Url,
+		Secret:     subscription.Secret,
+		Deadline:   leasedAt.Add(lease / 2),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
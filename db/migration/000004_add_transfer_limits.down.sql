@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS entries_account_id_created_at_idx;
+
+DROP TABLE IF EXISTS account_limits;
+
+DROP TABLE IF EXISTS currency_limits;
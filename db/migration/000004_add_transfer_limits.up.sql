@@ -0,0 +1,27 @@
+CREATE TABLE "currency_limits" (
+  "currency" varchar PRIMARY KEY,
+  "per_transaction" bigint,
+  "daily" bigint,
+  "monthly" bigint
+);
+
+CREATE TABLE "account_limits" (
+  "account_id" bigint PRIMARY KEY,
+  "per_transaction" bigint,
+  "daily" bigint,
+  "monthly" bigint,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "account_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+COMMENT ON COLUMN "currency_limits"."per_transaction" IS 'null means unlimited';
+
+COMMENT ON COLUMN "account_limits"."per_transaction" IS 'null falls back to the currency limit';
+
+INSERT INTO "currency_limits" ("currency", "per_transaction", "daily", "monthly") VALUES
+  ('USD', 1000000, 2500000, 10000000),
+  ('CAD', 1000000, 2500000, 10000000),
+  ('EUR', 1000000, 2500000, 10000000);
@@ -0,0 +1,11 @@
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "external_reference";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "description";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "transfer_id";
+
+DROP INDEX IF EXISTS transfers_external_reference_idx;
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "external_reference";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "description";
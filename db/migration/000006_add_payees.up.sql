@@ -0,0 +1,18 @@
+CREATE TABLE "payees" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "nickname" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payees" ADD CONSTRAINT "owner_nickname_key" UNIQUE ("owner", "nickname");
+
+ALTER TABLE "payees" ADD CONSTRAINT "owner_payee_account_key" UNIQUE ("owner", "account_id");
+
+COMMENT ON COLUMN "payees"."created_at" IS 'start of the cooling-off period for large transfers';
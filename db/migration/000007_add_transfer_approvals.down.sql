@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS transfer_approvals;
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS system_accounts;
+
+DELETE FROM accounts WHERE owner = 'bankfees';
+
+DELETE FROM users WHERE username = 'bankfees';
+
+DROP TABLE IF EXISTS fee_rules;
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "kind";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "tier";
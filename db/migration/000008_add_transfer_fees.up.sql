@@ -0,0 +1,50 @@
+ALTER TABLE "accounts" ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard';
+
+ALTER TABLE "entries" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'transfer';
+
+CREATE TABLE "fee_rules" (
+  "id" bigserial PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "tier" varchar,
+  "flat" bigint NOT NULL DEFAULT 0,
+  "percent_bps" integer NOT NULL DEFAULT 0,
+  "min_fee" bigint,
+  "max_fee" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "system_accounts" (
+  "purpose" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  PRIMARY KEY ("purpose", "currency")
+);
+
+CREATE UNIQUE INDEX ON "fee_rules" ("currency", COALESCE("tier", ''));
+
+ALTER TABLE "system_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "accounts"."tier" IS 'pricing tier used to pick fee rules';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer or fee';
+
+COMMENT ON COLUMN "fee_rules"."tier" IS 'null applies to every tier without its own rule';
+
+COMMENT ON COLUMN "fee_rules"."percent_bps" IS 'percentage of the amount in basis points';
+
+COMMENT ON COLUMN "fee_rules"."min_fee" IS 'null means no minimum';
+
+COMMENT ON COLUMN "fee_rules"."max_fee" IS 'null means no maximum';
+
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES
+  ('bankfees', '', 'Fee revenue', 'fees@simplebank.internal');
+
+WITH "fee_accounts" AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency") VALUES
+    ('bankfees', 0, 'USD'),
+    ('bankfees', 0, 'CAD'),
+    ('bankfees', 0, 'EUR')
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'fee_revenue', "currency", "id" FROM "fee_accounts";
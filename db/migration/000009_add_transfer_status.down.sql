@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS transfer_status_history;
+
+DROP INDEX IF EXISTS transfers_status_idx;
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "updated_at";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "failure_reason";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "status";
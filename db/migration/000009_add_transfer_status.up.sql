@@ -0,0 +1,45 @@
+ALTER TABLE "transfers" ADD COLUMN "status" varchar NOT NULL DEFAULT 'completed';
+
+ALTER TABLE "transfers" ADD COLUMN "failure_reason" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "updated_at" timestamptz NOT NULL DEFAULT (now());
+
+CREATE TABLE "transfer_status_history" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "from_status" varchar,
+  "to_status" varchar NOT NULL,
+  "reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_status_history" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfer_status_history" ("transfer_id");
+
+CREATE INDEX ON "transfers" ("status");
+
+COMMENT ON COLUMN "transfers"."status" IS 'pending, processing, completed, failed or reversed';
+
+COMMENT ON COLUMN "transfers"."failure_reason" IS 'set when the transfer failed';
+
+COMMENT ON COLUMN "transfer_status_history"."from_status" IS 'null for the status a transfer was created with';
+
+-- Approvals requested before this migration get the pending transfer they
+-- would have been created with.
+DO $$
+DECLARE
+  approval record;
+  pending_id bigint;
+BEGIN
+  FOR approval IN SELECT * FROM "transfer_approvals" WHERE "status" = 'pending' AND "transfer_id" IS NULL LOOP
+    INSERT INTO "transfers" ("from_account_id", "to_account_id", "amount", "description", "external_reference", "status", "created_at")
+    VALUES (approval.from_account_id, approval.to_account_id, approval.amount, approval.description, approval.external_reference, 'pending', approval.created_at)
+    RETURNING "id" INTO pending_id;
+
+    UPDATE "transfer_approvals" SET "transfer_id" = pending_id WHERE "id" = approval.id;
+  END LOOP;
+END $$;
+
+INSERT INTO "transfer_status_history" ("transfer_id", "to_status", "created_at")
+SELECT "id", "status", "created_at" FROM "transfers";
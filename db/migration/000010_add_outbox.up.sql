@@ -0,0 +1,19 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ON "outbox" ("id") WHERE "published_at" IS NULL;
+
+CREATE INDEX ON "outbox" ("aggregate_type", "aggregate_id");
+
+COMMENT ON COLUMN "outbox"."aggregate_id" IS 'events of one aggregate are published in id order';
+
+COMMENT ON COLUMN "outbox"."published_at" IS 'null until a sink accepted the event';
@@ -0,0 +1,12 @@
+CREATE TABLE "reconciliation_runs" (
+  "id" bigserial PRIMARY KEY,
+  "started_at" timestamptz NOT NULL,
+  "finished_at" timestamptz NOT NULL DEFAULT (now()),
+  "drifted_accounts" int NOT NULL,
+  "transfer_issues" int NOT NULL,
+  "report" jsonb NOT NULL
+);
+
+CREATE INDEX ON "reconciliation_runs" ("started_at");
+
+COMMENT ON COLUMN "reconciliation_runs"."report" IS 'every drifted account and broken transfer found by the run';
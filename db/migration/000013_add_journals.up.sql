@@ -0,0 +1,31 @@
+CREATE TABLE "journals" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint,
+  "description" varchar NOT NULL DEFAULT '',
+  "external_reference" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "journals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "journals" ("transfer_id");
+
+ALTER TABLE "entries" ADD COLUMN "journal_id" bigint;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("journal_id") REFERENCES "journals" ("id");
+
+CREATE INDEX ON "entries" ("journal_id");
+
+COMMENT ON COLUMN "journals"."transfer_id" IS 'transfer the journal settles, null for other postings';
+
+COMMENT ON COLUMN "entries"."journal_id" IS 'the entries of a journal sum to zero per currency';
+
+-- Every transfer that already moved money becomes a journal of its transfer
+-- and fee entries.
+INSERT INTO "journals" ("transfer_id", "description", "external_reference", "created_at")
+SELECT "id", "description", "external_reference", "created_at" FROM "transfers" t
+WHERE EXISTS (SELECT 1 FROM "entries" e WHERE e."transfer_id" = t."id");
+
+UPDATE "entries" e SET "journal_id" = j."id"
+FROM "journals" j
+WHERE j."transfer_id" = e."transfer_id";
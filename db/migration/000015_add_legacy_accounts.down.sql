@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "entries"."kind" IS 'transfer or fee';
+
+DROP TABLE IF EXISTS legacy_accounts;
@@ -0,0 +1,11 @@
+CREATE TABLE "legacy_accounts" (
+  "legacy_id" varchar PRIMARY KEY,
+  "account_id" bigint UNIQUE NOT NULL,
+  "imported_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "legacy_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON TABLE "legacy_accounts" IS 'accounts imported from the old core, an account and its history are imported together';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, fee or import';
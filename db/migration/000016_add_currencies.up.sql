@@ -0,0 +1,17 @@
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+INSERT INTO "currencies" ("code") VALUES
+  ('USD'),
+  ('CAD'),
+  ('EUR');
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+COMMENT ON TABLE "currencies" IS 'currencies offered by the bank, an ISO 4217 code with its minor units known to the money package';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'new accounts can only be opened in enabled currencies, accounts in a disabled one keep working';
@@ -0,0 +1,15 @@
+DELETE FROM system_accounts WHERE purpose = 'interest_expense';
+
+DELETE FROM accounts WHERE owner = 'bankinterest';
+
+DELETE FROM users WHERE username = 'bankinterest';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, fee or import';
+
+DROP TABLE IF EXISTS interest_accrual_days;
+
+DROP TABLE IF EXISTS interest_accruals;
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "product";
+
+DROP TABLE IF EXISTS products;
@@ -0,0 +1,75 @@
+CREATE TABLE "products" (
+  "code" varchar PRIMARY KEY,
+  "type" varchar NOT NULL,
+  "annual_rate_bps" integer NOT NULL DEFAULT 0,
+  "day_count" varchar NOT NULL DEFAULT 'act/365',
+  "rounding" varchar NOT NULL DEFAULT 'half_even',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+INSERT INTO "products" ("code", "type", "annual_rate_bps") VALUES
+  ('checking', 'checking', 0),
+  ('savings', 'savings', 150);
+
+ALTER TABLE "accounts" ADD COLUMN "product" varchar NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product") REFERENCES "products" ("code");
+
+CREATE TABLE "interest_accruals" (
+  "account_id" bigint NOT NULL,
+  "as_of" timestamptz NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate_bps" integer NOT NULL,
+  "amount_micros" bigint NOT NULL,
+  "journal_id" bigint,
+  "posted_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "as_of")
+);
+
+CREATE TABLE "interest_accrual_days" (
+  "as_of" timestamptz PRIMARY KEY,
+  "accruals" integer NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("journal_id") REFERENCES "journals" ("id");
+
+CREATE INDEX ON "interest_accruals" ("as_of") WHERE "posted_at" IS NULL;
+
+COMMENT ON COLUMN "products"."type" IS 'checking or savings, only savings accounts earn interest';
+
+COMMENT ON COLUMN "products"."annual_rate_bps" IS 'annual interest rate in basis points';
+
+COMMENT ON COLUMN "products"."day_count" IS 'act/365, act/360, act/act or 30/360';
+
+COMMENT ON COLUMN "products"."rounding" IS 'half_up, half_even or down, applied when accrued interest is posted';
+
+COMMENT ON COLUMN "interest_accruals"."as_of" IS 'midnight UTC ending the day the interest accrued over';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end-of-day balance, taken from the balance snapshot at as_of';
+
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'unrounded interest in millionths of a minor unit';
+
+COMMENT ON COLUMN "interest_accruals"."journal_id" IS 'journal that posted the interest of the month, null when it rounded to nothing';
+
+COMMENT ON COLUMN "interest_accruals"."posted_at" IS 'null until the month is posted';
+
+COMMENT ON TABLE "interest_accrual_days" IS 'days interest was accrued for, including days with nothing to accrue';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, fee, import or interest';
+
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES
+  ('bankinterest', '', 'Interest expense', 'interest@simplebank.internal');
+
+WITH "interest_accounts" AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency") VALUES
+    ('bankinterest', 0, 'USD'),
+    ('bankinterest', 0, 'CAD'),
+    ('bankinterest', 0, 'EUR')
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'interest_expense', "currency", "id" FROM "interest_accounts";
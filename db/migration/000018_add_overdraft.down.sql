@@ -0,0 +1,11 @@
+DELETE FROM system_accounts WHERE purpose = 'overdraft_interest';
+
+DELETE FROM accounts WHERE owner = 'bankoverdraft';
+
+DELETE FROM users WHERE username = 'bankoverdraft';
+
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'unrounded interest in millionths of a minor unit';
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "overdraft_rate_bps";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "overdraft_limit";
@@ -0,0 +1,22 @@
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD COLUMN "overdraft_rate_bps" integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero debits may take the available balance';
+
+COMMENT ON COLUMN "accounts"."overdraft_rate_bps" IS 'annual interest charged on overdrawn balances in basis points, 0 charges none';
+
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'unrounded interest in millionths of a minor unit, negative when charged on an overdraft';
+
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES
+  ('bankoverdraft', '', 'Overdraft interest income', 'overdraft@simplebank.internal');
+
+WITH "overdraft_accounts" AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency") VALUES
+    ('bankoverdraft', 0, 'USD'),
+    ('bankoverdraft', 0, 'CAD'),
+    ('bankoverdraft', 0, 'EUR')
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'overdraft_interest', "currency", "id" FROM "overdraft_accounts";
@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "accounts"."owner" IS NULL;
+
+DROP TABLE IF EXISTS account_members;
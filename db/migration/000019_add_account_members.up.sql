@@ -0,0 +1,23 @@
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "added_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("added_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "account_members" ("username");
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner, signer or viewer: owners manage members, signers move money, viewers only read';
+
+COMMENT ON COLUMN "accounts"."owner" IS 'primary owner, always an owner member who can''t be removed';
+
+INSERT INTO "account_members" ("account_id", "username", "role", "added_by")
+SELECT "id", "owner", 'owner', "owner" FROM "accounts";
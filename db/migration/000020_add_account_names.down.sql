@@ -0,0 +1,23 @@
+-- Going back to one account per owner and currency only works while nobody
+-- has more than one. Stop before anything is dropped rather than halfway
+-- through on the unique constraint.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "accounts" GROUP BY "owner", "currency" HAVING COUNT(*) > 1
+  ) THEN
+    RAISE EXCEPTION 'some owners have more than one account in a currency, merge or close them before migrating down';
+  END IF;
+END $$;
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "max_accounts";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "multiple_accounts";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "owner_name_key";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "name";
+
+DROP INDEX IF EXISTS accounts_owner_currency_idx;
+
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner","currency");
@@ -0,0 +1,28 @@
+ALTER TABLE "accounts" ADD COLUMN "name" varchar;
+
+UPDATE "accounts" SET "name" = "currency";
+
+ALTER TABLE "accounts" ALTER COLUMN "name" SET NOT NULL;
+
+ALTER TABLE "accounts" DROP CONSTRAINT "owner_currency_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_name_key" UNIQUE ("owner", "name");
+
+CREATE INDEX ON "accounts" ("owner", "currency");
+
+COMMENT ON COLUMN "accounts"."name" IS 'chosen by the owner, defaults to the currency code';
+
+ALTER TABLE "users" ADD COLUMN "multiple_accounts" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "users" ADD COLUMN "max_accounts" integer NOT NULL DEFAULT 10;
+
+COMMENT ON COLUMN "users"."multiple_accounts" IS 'opted in to more than one account per currency';
+
+COMMENT ON COLUMN "users"."max_accounts" IS 'most accounts the user may own';
+
+-- Nobody loses accounts they already own to the cap.
+UPDATE "users" u SET "max_accounts" = c."accounts"
+FROM (
+  SELECT "owner", COUNT(*) AS "accounts" FROM "accounts" GROUP BY "owner"
+) c
+WHERE c."owner" = u."username" AND c."accounts" > u."max_accounts";
@@ -0,0 +1,3 @@
+DELETE FROM "account_members" WHERE "accepted_at" IS NULL;
+
+ALTER TABLE IF EXISTS "account_members" DROP COLUMN IF EXISTS "accepted_at";
@@ -0,0 +1,5 @@
+ALTER TABLE "account_members" ADD COLUMN "accepted_at" timestamptz;
+
+UPDATE "account_members" SET "accepted_at" = "created_at";
+
+COMMENT ON COLUMN "account_members"."accepted_at" IS 'null while the invitation is pending, the role only applies once accepted';
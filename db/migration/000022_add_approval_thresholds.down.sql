@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS "currencies" DROP COLUMN IF EXISTS "approval_threshold";
@@ -0,0 +1,7 @@
+ALTER TABLE "currencies" ADD COLUMN "approval_threshold" bigint NOT NULL DEFAULT 0;
+
+-- The threshold used to be one setting for every currency, 10,000.00 in the
+-- shipped configuration.
+UPDATE "currencies" SET "approval_threshold" = 1000000;
+
+COMMENT ON COLUMN "currencies"."approval_threshold" IS 'transfers above it in minor units wait for a second person, 0 disables dual approval';
@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS "currencies" DROP COLUMN IF EXISTS "payee_cooling_off_amount";
@@ -0,0 +1,7 @@
+ALTER TABLE "currencies" ADD COLUMN "payee_cooling_off_amount" bigint NOT NULL DEFAULT 0;
+
+-- The amount used to be one setting for every currency, 1,000.00 in the
+-- shipped configuration.
+UPDATE "currencies" SET "payee_cooling_off_amount" = 100000;
+
+COMMENT ON COLUMN "currencies"."payee_cooling_off_amount" IS 'most a new payee can receive in minor units until its cooling-off period ends';
@@ -2,9 +2,11 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product,
+  name
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetAccount :one
@@ -12,6 +14,29 @@ SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
 
+-- name: GetAccountByOwner :one
+-- The owner's oldest account in the currency.
+SELECT * FROM accounts
+WHERE owner = $1 AND currency = $2
+ORDER BY id
+LIMIT 1;
+
+-- name: ListRecipientAccounts :many
+-- The owner's accounts in the currency, only the one called name when it's
+-- given. Two rows are enough to tell the recipient is ambiguous.
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner) AND currency = sqlc.arg(currency)
+  AND (sqlc.narg(name)::varchar IS NULL OR name = sqlc.narg(name))
+ORDER BY id
+LIMIT 2;
+
+-- name: CountOwnerAccounts :one
+SELECT
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE currency = sqlc.arg(currency)) AS in_currency
+FROM accounts
+WHERE owner = sqlc.arg(owner);
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1
@@ -37,6 +62,19 @@ SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: UpdateAccountTier :one
+UPDATE accounts
+SET tier = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountOverdraft :one
+UPDATE accounts
+SET overdraft_limit = $2,
+  overdraft_rate_bps = $3
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteAccount :exec
 DELETE FROM accounts
 WHERE id = $1;
@@ -0,0 +1,62 @@
+-- name: CreateTransferApproval :one
+INSERT INTO transfer_approvals (
+  from_account_id,
+  to_account_id,
+  amount,
+  description,
+  external_reference,
+  requested_by,
+  expires_at,
+  transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetTransferApproval :one
+SELECT * FROM transfer_approvals
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferApprovalForUpdate :one
+SELECT * FROM transfer_approvals
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListTransferApprovals :many
+SELECT * FROM transfer_approvals
+WHERE status = sqlc.arg(status)
+  AND (sqlc.narg(requested_by)::varchar IS NULL OR requested_by = sqlc.narg(requested_by))
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: DecideTransferApproval :one
+UPDATE transfer_approvals
+SET status = sqlc.arg(status),
+  decided_by = sqlc.arg(decided_by),
+  transfer_id = sqlc.arg(transfer_id),
+  decided_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ExpireTransferApprovals :execrows
+WITH expired AS (
+  UPDATE transfer_approvals
+  SET status = 'expired',
+    decided_at = now()
+  WHERE status = 'pending'
+    AND expires_at <= now()
+  RETURNING transfer_id
+), failed AS (
+  UPDATE transfers
+  SET status = 'failed',
+    failure_reason = 'approval expired',
+    updated_at = now()
+  WHERE id IN (SELECT transfer_id FROM expired)
+    AND status = 'pending'
+  RETURNING *
+), events AS (
+  INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload)
+  SELECT 'transfer', id::varchar, 'transfer.failed', to_jsonb(failed) FROM failed
+)
+INSERT INTO transfer_status_history (transfer_id, from_status, to_status, reason)
+SELECT id, 'pending', 'failed', 'approval expired' FROM failed;
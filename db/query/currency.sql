@@ -0,0 +1,26 @@
+-- name: CreateCurrency :one
+INSERT INTO currencies (
+  code,
+  enabled,
+  approval_threshold,
+  payee_cooling_off_amount
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY code;
+
+-- name: UpdateCurrency :one
+UPDATE currencies
+SET enabled = COALESCE(sqlc.narg(enabled), enabled),
+  approval_threshold = COALESCE(sqlc.narg(approval_threshold), approval_threshold),
+  payee_cooling_off_amount = COALESCE(sqlc.narg(payee_cooling_off_amount), payee_cooling_off_amount),
+  updated_at = now()
+WHERE code = sqlc.arg(code)
+RETURNING *;
@@ -1,9 +1,14 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id,
+  description,
+  external_reference,
+  kind,
+  journal_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4, $5, $6, $7
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -11,7 +16,13 @@ SELECT * FROM entries
 WHERE id = $1 LIMIT 1;
 
 -- name: ListEntries :many
-SELECT * FROM entries
+-- The running balance is taken over every entry of the account, the window
+-- is evaluated before the page is cut.
+SELECT
+  id, account_id, amount, created_at, transfer_id, description,
+  external_reference, kind, journal_id,
+  (SUM(amount) OVER (ORDER BY id))::bigint AS running_balance
+FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -0,0 +1,43 @@
+-- name: CreateFeeRule :one
+INSERT INTO fee_rules (
+  currency,
+  tier,
+  flat,
+  percent_bps,
+  min_fee,
+  max_fee
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetFeeRule :one
+SELECT * FROM fee_rules
+WHERE currency = sqlc.arg(currency)
+  AND (tier = sqlc.arg(tier) OR tier IS NULL)
+ORDER BY tier IS NULL
+LIMIT 1;
+
+-- name: ListFeeRules :many
+-- All fee rules, only those of the currency when it's given. The rule for
+-- every tier comes first in each currency.
+SELECT * FROM fee_rules
+WHERE sqlc.narg(currency)::varchar IS NULL OR currency = sqlc.narg(currency)
+ORDER BY currency, tier NULLS FIRST, id;
+
+-- name: DeleteFeeRule :one
+DELETE FROM fee_rules
+WHERE id = $1
+RETURNING *;
+
+-- name: GetSystemAccount :one
+SELECT * FROM system_accounts
+WHERE purpose = $1 AND currency = $2 LIMIT 1;
+
+-- name: CreateSystemAccount :one
+INSERT INTO system_accounts (
+  purpose,
+  currency,
+  account_id
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
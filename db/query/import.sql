@@ -0,0 +1,36 @@
+-- name: CreateImportedAccount :one
+INSERT INTO accounts (
+  owner,
+  balance,
+  currency,
+  created_at,
+  name
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: CreateImportedEntry :exec
+-- Imported entries keep the time they were booked in the old core. They
+-- belong to no transfer or journal.
+INSERT INTO entries (
+  account_id,
+  amount,
+  created_at,
+  description,
+  external_reference,
+  kind
+) VALUES (
+  $1, $2, $3, $4, $5, 'import'
+);
+
+-- name: CreateLegacyAccount :one
+INSERT INTO legacy_accounts (
+  legacy_id,
+  account_id
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetLegacyAccount :one
+SELECT * FROM legacy_accounts
+WHERE legacy_id = $1 LIMIT 1;
@@ -0,0 +1,84 @@
+-- name: GetProduct :one
+SELECT * FROM products
+WHERE code = $1 LIMIT 1;
+
+-- name: ListProducts :many
+SELECT * FROM products
+ORDER BY code;
+
+-- name: GetLatestAccrualDay :one
+SELECT MAX(as_of)::timestamptz AS as_of FROM interest_accrual_days;
+
+-- name: ListInterestBalances :many
+-- The end-of-day balances accruing interest over the day ending at as_of,
+-- with the rate they accrue at: savings accounts in credit earn the rate of
+-- their product, overdrawn accounts are charged their overdraft rate.
+SELECT
+  s.account_id,
+  s.balance,
+  (CASE WHEN s.balance > 0 THEN p.annual_rate_bps ELSE a.overdraft_rate_bps END)::integer AS annual_rate_bps,
+  p.day_count
+FROM balance_snapshots s
+JOIN accounts a ON a.id = s.account_id
+JOIN products p ON p.code = a.product
+WHERE s.as_of = sqlc.arg(as_of)
+  AND (
+    (p.type = 'savings' AND p.annual_rate_bps > 0 AND s.balance > 0)
+    OR (a.overdraft_rate_bps > 0 AND s.balance < 0)
+  )
+ORDER BY s.account_id;
+
+-- name: CreateInterestAccrual :exec
+INSERT INTO interest_accruals (
+  account_id,
+  as_of,
+  balance,
+  annual_rate_bps,
+  amount_micros
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: CreateInterestAccrualDay :exec
+INSERT INTO interest_accrual_days (
+  as_of,
+  accruals
+) VALUES (
+  $1, $2
+);
+
+-- name: ListUnpostedInterest :many
+-- The interest accrued and not posted yet per account and month, for the
+-- days ending at or before before. A day belongs to the month it started in.
+-- Interest earned and overdraft interest charged are summed apart, they are
+-- posted to different system accounts.
+SELECT
+  i.account_id,
+  a.currency,
+  p.rounding,
+  date_trunc('month', i.as_of - interval '1 day', 'UTC')::timestamptz AS month,
+  (i.amount_micros < 0)::boolean AS overdraft,
+  SUM(i.amount_micros)::bigint AS amount_micros
+FROM interest_accruals i
+JOIN accounts a ON a.id = i.account_id
+JOIN products p ON p.code = a.product
+WHERE i.posted_at IS NULL
+  AND i.as_of <= sqlc.arg(before)
+GROUP BY i.account_id, a.currency, p.rounding, month, overdraft
+ORDER BY month, i.account_id, overdraft;
+
+-- name: MarkInterestPosted :execrows
+UPDATE interest_accruals
+SET journal_id = sqlc.arg(journal_id),
+  posted_at = now()
+WHERE account_id = sqlc.arg(account_id)
+  AND posted_at IS NULL
+  AND date_trunc('month', as_of - interval '1 day', 'UTC') = sqlc.arg(month)
+  AND (amount_micros < 0) = sqlc.arg(overdraft);
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY as_of DESC
+LIMIT $2
+OFFSET $3;
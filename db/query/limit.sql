@@ -0,0 +1,53 @@
+-- name: GetAccountLimits :one
+SELECT * FROM account_limits
+WHERE account_id = $1 LIMIT 1;
+
+-- name: UpsertAccountLimits :one
+INSERT INTO account_limits (
+  account_id,
+  per_transaction,
+  daily,
+  monthly
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (account_id) DO UPDATE
+SET per_transaction = EXCLUDED.per_transaction,
+  daily = EXCLUDED.daily,
+  monthly = EXCLUDED.monthly,
+  updated_at = now()
+RETURNING *;
+
+-- name: CreateCurrencyLimits :one
+INSERT INTO currency_limits (
+  currency,
+  per_transaction,
+  daily,
+  monthly
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetCurrencyLimits :one
+SELECT * FROM currency_limits
+WHERE currency = $1 LIMIT 1;
+
+-- name: GetEffectiveLimits :one
+SELECT
+  a.id AS account_id,
+  COALESCE(al.per_transaction, cl.per_transaction) AS per_transaction,
+  COALESCE(al.daily, cl.daily) AS daily,
+  COALESCE(al.monthly, cl.monthly) AS monthly
+FROM accounts a
+LEFT JOIN account_limits al ON al.account_id = a.id
+LEFT JOIN currency_limits cl ON cl.currency = a.currency
+WHERE a.id = $1;
+
+-- name: GetOutgoingTotals :one
+SELECT
+  COALESCE(-SUM(amount) FILTER (WHERE created_at > now() - interval '1 day'), 0)::bigint AS daily,
+  COALESCE(-SUM(amount) FILTER (WHERE created_at >= date_trunc('month', now())), 0)::bigint AS monthly
+FROM entries
+WHERE account_id = $1
+  AND amount < 0
+  AND kind = 'transfer'
+  AND created_at >= LEAST(now() - interval '1 day', date_trunc('month', now()));
@@ -0,0 +1,45 @@
+-- name: CreateAccountMember :one
+INSERT INTO account_members (
+  account_id,
+  username,
+  role,
+  added_by,
+  accepted_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetAccountMember :one
+SELECT * FROM account_members
+WHERE account_id = $1 AND username = $2 LIMIT 1;
+
+-- name: ListAccountMembers :many
+SELECT * FROM account_members
+WHERE account_id = $1
+ORDER BY created_at, username;
+
+-- name: ListMemberInvites :many
+-- The invitations a user hasn't accepted or declined yet.
+SELECT * FROM account_members
+WHERE username = $1 AND accepted_at IS NULL
+ORDER BY created_at, account_id;
+
+-- name: AcceptAccountMember :one
+UPDATE account_members
+SET accepted_at = now()
+WHERE account_id = $1 AND username = $2 AND accepted_at IS NULL
+RETURNING *;
+
+-- name: ListMemberAccounts :many
+-- The accounts a user is a member of, in any role, leaving out pending
+-- invitations.
+SELECT a.* FROM accounts a
+JOIN account_members m ON m.account_id = a.id
+WHERE m.username = $1 AND m.accepted_at IS NOT NULL
+ORDER BY a.id
+LIMIT $2
+OFFSET $3;
+
+-- name: DeleteAccountMember :execrows
+DELETE FROM account_members
+WHERE account_id = $1 AND username = $2;
@@ -0,0 +1,41 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox (
+  aggregate_type,
+  aggregate_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox
+WHERE id = $1 LIMIT 1;
+
+-- name: ListUnpublishedOutboxEvents :many
+SELECT * FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox
+SET published_at = now(),
+  attempts = attempts + 1,
+  last_error = ''
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1,
+  last_error = $2
+WHERE id = $1;
+
+-- name: TryLockOutbox :one
+SELECT pg_try_advisory_xact_lock(hashtext('outbox'));
+
+-- name: ListAggregateOutboxEvents :many
+SELECT * FROM outbox
+WHERE aggregate_type = $1
+  AND aggregate_id = $2
+ORDER BY id;
@@ -0,0 +1,67 @@
+-- name: ListBalanceDrift :many
+SELECT a.id AS account_id,
+  a.owner,
+  a.currency,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_total,
+  (a.balance - COALESCE(SUM(e.amount), 0))::bigint AS drift
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListTransferDiscrepancies :many
+WITH legs AS (
+  SELECT t.id,
+    t.status,
+    t.amount,
+    COUNT(e.id) FILTER (WHERE e.kind = 'transfer') AS transfer_entries,
+    COALESCE(SUM(e.amount) FILTER (WHERE e.kind = 'transfer'), 0) AS transfer_total,
+    COUNT(e.id) FILTER (
+      WHERE e.kind = 'transfer'
+        AND ((e.account_id = t.from_account_id AND e.amount = -t.amount)
+          OR (e.account_id = t.to_account_id AND e.amount = t.amount))
+    ) AS matching_entries,
+    COUNT(e.id) FILTER (WHERE e.kind = 'fee') AS fee_entries,
+    COALESCE(SUM(e.amount) FILTER (WHERE e.kind = 'fee'), 0) AS fee_total
+  FROM transfers t
+  LEFT JOIN entries e ON e.transfer_id = t.id
+  GROUP BY t.id
+)
+SELECT id AS transfer_id,
+  status,
+  amount,
+  transfer_entries::int AS transfer_entries,
+  transfer_total::bigint AS transfer_total,
+  matching_entries::int AS matching_entries,
+  fee_entries::int AS fee_entries,
+  fee_total::bigint AS fee_total
+FROM legs
+WHERE (status = 'completed' AND (transfer_entries <> 2 OR matching_entries <> 2))
+  OR (status IN ('pending', 'processing', 'failed') AND transfer_entries + fee_entries > 0)
+  OR transfer_total <> 0
+  OR fee_total <> 0
+  OR fee_entries NOT IN (0, 2)
+ORDER BY id;
+
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (
+  started_at,
+  drifted_accounts,
+  transfer_issues,
+  report
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetLatestReconciliationRun :one
+SELECT * FROM reconciliation_runs
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListReconciliationRuns :many
+SELECT * FROM reconciliation_runs
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
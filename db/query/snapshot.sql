@@ -0,0 +1,51 @@
+-- name: CreateBalanceSnapshots :execrows
+-- Snapshots every account that existed at as_of, starting from its previous
+-- snapshot so only the entries of the days since have to be summed.
+INSERT INTO balance_snapshots (account_id, as_of, balance)
+SELECT
+  a.id,
+  sqlc.arg(as_of),
+  COALESCE(s.balance, 0) + COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id
+      AND e.created_at >= COALESCE(s.as_of, '-infinity')
+      AND e.created_at < sqlc.arg(as_of)
+  ), 0)
+FROM accounts a
+LEFT JOIN LATERAL (
+  SELECT as_of, balance FROM balance_snapshots
+  WHERE account_id = a.id AND as_of < sqlc.arg(as_of)
+  ORDER BY as_of DESC
+  LIMIT 1
+) s ON true
+WHERE a.created_at < sqlc.arg(as_of)
+ON CONFLICT (account_id, as_of) DO NOTHING;
+
+-- name: GetLatestSnapshotTime :one
+SELECT MAX(as_of)::timestamptz AS as_of FROM balance_snapshots;
+
+-- name: GetFirstAccountTime :one
+SELECT MIN(created_at)::timestamptz AS created_at FROM accounts;
+
+-- name: GetBalanceSnapshot :one
+SELECT * FROM balance_snapshots
+WHERE account_id = $1 AND as_of = $2 LIMIT 1;
+
+-- name: GetBalanceAt :one
+-- The balance of an account after every entry created up to and including
+-- at: its last snapshot before at plus the entries since.
+SELECT
+  s.as_of AS snapshot_at,
+  (COALESCE(s.balance, 0) + COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = sqlc.arg(account_id)
+      AND e.created_at >= COALESCE(s.as_of, '-infinity')
+      AND e.created_at <= sqlc.arg(at)
+  ), 0))::bigint AS balance
+FROM (SELECT 1) one
+LEFT JOIN LATERAL (
+  SELECT as_of, balance FROM balance_snapshots
+  WHERE account_id = sqlc.arg(account_id) AND as_of <= sqlc.arg(at)
+  ORDER BY as_of DESC
+  LIMIT 1
+) s ON true;
@@ -0,0 +1,26 @@
+-- name: ListStatementEntries :many
+-- Pages through the entries of an account in a period by entry id. The
+-- counterparty of a transfer entry is the other side of its transfer, fee
+-- and other entries have none.
+SELECT
+  e.id,
+  e.amount,
+  e.created_at,
+  e.kind,
+  e.description,
+  e.external_reference,
+  e.transfer_id,
+  c.id AS counterparty_account_id,
+  c.owner AS counterparty_owner
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id AND e.kind = 'transfer'
+LEFT JOIN accounts c ON c.id = CASE
+  WHEN t.from_account_id = e.account_id THEN t.to_account_id
+  ELSE t.from_account_id
+END
+WHERE e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)
+  AND e.created_at <= sqlc.arg(to_time)
+  AND e.id > sqlc.arg(after_id)
+ORDER BY e.id
+LIMIT sqlc.arg(page_size);
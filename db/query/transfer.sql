@@ -2,15 +2,46 @@
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  description,
+  external_reference,
+  status
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = sqlc.arg(status),
+  failure_reason = sqlc.arg(failure_reason),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateTransferStatusHistory :one
+INSERT INTO transfer_status_history (
+  transfer_id,
+  from_status,
+  to_status,
+  reason
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListTransferStatusHistory :many
+SELECT * FROM transfer_status_history
+WHERE transfer_id = $1
+ORDER BY id;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE 
@@ -19,3 +50,15 @@ WHERE
 ORDER BY id
 LIMIT $3
 OFFSET $4;
+
+-- name: SearchTransfers :many
+SELECT * FROM transfers
+WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND (
+    sqlc.arg(query)::text = ''
+    OR description ILIKE '%' || sqlc.arg(query)::text || '%'
+    OR external_reference ILIKE '%' || sqlc.arg(query)::text || '%'
+  )
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
@@ -9,4 +9,21 @@ INSERT INTO users (
 ) RETURNING *;
 
 -- name: GetUser :one
-SELECT * FROM users WHERE username = $1 LIMIT 1;
\ No newline at end of file
+SELECT * FROM users WHERE username = $1 LIMIT 1;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateUserSettings :one
+UPDATE users
+SET multiple_accounts = COALESCE(sqlc.narg(multiple_accounts), multiple_accounts),
+  max_accounts = COALESCE(sqlc.narg(max_accounts), max_accounts)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: GetUserByUsernameOrEmail :one
+SELECT * FROM users
+WHERE username = sqlc.arg(identifier)
+   OR lower(email) = lower(sqlc.arg(identifier))
+LIMIT 1;
@@ -7,13 +7,14 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name
 `
 
 type AddAccountBalanceParams struct {
@@ -30,28 +31,68 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
 	)
 	return i, err
 }
 
+const countOwnerAccounts = `-- name: CountOwnerAccounts :one
+SELECT
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE currency = $1) AS in_currency
+FROM accounts
+WHERE owner = $2
+`
+
+type CountOwnerAccountsParams struct {
+	Currency string `json:"currency"`
+	Owner    string `json:"owner"`
+}
+
+type CountOwnerAccountsRow struct {
+	Total      int64 `json:"total"`
+	InCurrency int64 `json:"in_currency"`
+}
+
+func (q *Queries) CountOwnerAccounts(ctx context.Context, arg CountOwnerAccountsParams) (CountOwnerAccountsRow, error) {
+	row := q.db.QueryRowContext(ctx, countOwnerAccounts, arg.Currency, arg.Owner)
+	var i CountOwnerAccountsRow
+	err := row.Scan(&i.Total, &i.InCurrency)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product,
+  name
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+  $1, $2, $3, $4, $5
+) RETURNING id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Product  string `json:"product"`
+	Name     string `json:"name"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Product,
+		arg.Name,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +100,11 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
 	)
 	return i, err
 }
@@ -74,7 +120,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -87,12 +133,48 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
+	)
+	return i, err
+}
+
+const getAccountByOwner = `-- name: GetAccountByOwner :one
+SELECT id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name FROM accounts
+WHERE owner = $1 AND currency = $2
+ORDER BY id
+LIMIT 1
+`
+
+type GetAccountByOwnerParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+// The owner's oldest account in the currency.
+func (q *Queries) GetAccountByOwner(ctx context.Context, arg GetAccountByOwnerParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByOwner, arg.Owner, arg.Currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -106,12 +188,17 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -139,6 +226,61 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Tier,
+			&i.Product,
+			&i.OverdraftLimit,
+			&i.OverdraftRateBps,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipientAccounts = `-- name: ListRecipientAccounts :many
+SELECT id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name FROM accounts
+WHERE owner = $1 AND currency = $2
+  AND ($3::varchar IS NULL OR name = $3)
+ORDER BY id
+LIMIT 2
+`
+
+type ListRecipientAccountsParams struct {
+	Owner    string         `json:"owner"`
+	Currency string         `json:"currency"`
+	Name     sql.NullString `json:"name"`
+}
+
+// The owner's accounts in the currency, only the one called name when it's
+// given. Two rows are enough to tell the recipient is ambiguous.
+func (q *Queries) ListRecipientAccounts(ctx context.Context, arg ListRecipientAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipientAccounts, arg.Owner, arg.Currency, arg.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.Tier,
+			&i.Product,
+			&i.OverdraftLimit,
+			&i.OverdraftRateBps,
+			&i.Name,
 		); err != nil {
 			return nil, err
 		}
@@ -157,7 +299,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name
 `
 
 type UpdateAccountParams struct {
@@ -174,6 +316,73 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
+	)
+	return i, err
+}
+
+const updateAccountOverdraft = `-- name: UpdateAccountOverdraft :one
+UPDATE accounts
+SET overdraft_limit = $2,
+  overdraft_rate_bps = $3
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name
+`
+
+type UpdateAccountOverdraftParams struct {
+	ID               int64 `json:"id"`
+	OverdraftLimit   int64 `json:"overdraft_limit"`
+	OverdraftRateBps int32 `json:"overdraft_rate_bps"`
+}
+
+func (q *Queries) UpdateAccountOverdraft(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraft, arg.ID, arg.OverdraftLimit, arg.OverdraftRateBps)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
+	)
+	return i, err
+}
+
+const updateAccountTier = `-- name: UpdateAccountTier :one
+UPDATE accounts
+SET tier = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, tier, product, overdraft_limit, overdraft_rate_bps, name
+`
+
+type UpdateAccountTierParams struct {
+	ID   int64  `json:"id"`
+	Tier string `json:"tier"`
+}
+
+func (q *Queries) UpdateAccountTier(ctx context.Context, arg UpdateAccountTierParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountTier, arg.ID, arg.Tier)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Tier,
+		&i.Product,
+		&i.OverdraftLimit,
+		&i.OverdraftRateBps,
+		&i.Name,
 	)
 	return i, err
 }
@@ -17,6 +17,8 @@ func createRandomAccount(t *testing.T) Account {
 		Owner:    user.Username,
 		Balance:  util.RandomMoney(),
 		Currency: util.RandomCurrency(),
+		Name:     "main",
+		Product:  ProductChecking,
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -26,6 +28,7 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.Name, account.Name)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -33,10 +36,69 @@ func createRandomAccount(t *testing.T) Account {
 	return account
 }
 
+func createRandomAccountWithBalance(t *testing.T, balance int64) Account {
+	account := createRandomAccount(t)
+
+	account, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: balance,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
 func TestCreateAccount(t *testing.T) {
 	createRandomAccount(t)
 }
 
+func TestGetAccountByOwner(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2, err := testQueries.GetAccountByOwner(context.Background(), GetAccountByOwnerParams{
+		Owner:    account1.Owner,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+
+	currency := util.RandomCurrency()
+	for currency == account1.Currency {
+		currency = util.RandomCurrency()
+	}
+	_, err = testQueries.GetAccountByOwner(context.Background(), GetAccountByOwnerParams{
+		Owner:    account1.Owner,
+		Currency: currency,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestListRecipientAccounts(t *testing.T) {
+	main := createRandomAccount(t)
+	savings, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    main.Owner,
+		Currency: main.Currency,
+		Name:     "savings",
+		Product:  ProductChecking,
+	})
+	require.NoError(t, err)
+
+	arg := ListRecipientAccountsParams{Owner: main.Owner, Currency: main.Currency}
+	accounts, err := testQueries.ListRecipientAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+
+	arg.Name = sql.NullString{String: savings.Name, Valid: true}
+	accounts, err = testQueries.ListRecipientAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, savings.ID, accounts[0].ID)
+
+	arg.Name = sql.NullString{String: "holidays", Valid: true}
+	accounts, err = testQueries.ListRecipientAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
+
 func TestGetAccount(t *testing.T) {
 	account1 := createRandomAccount(t)
 	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
@@ -100,3 +162,38 @@ func TestListAccounts(t *testing.T) {
 		require.NotEmpty(t, account)
 	}
 }
+
+func TestCreateAccountTxMultiplePerCurrency(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	arg := CreateAccountParams{
+		Owner:    user.Username,
+		Currency: "USD",
+		Name:     "main",
+		Product:  ProductChecking,
+	}
+	_, err := store.CreateAccountTX(context.Background(), arg)
+	require.NoError(t, err)
+
+	arg.Name = "bills"
+	_, err = store.CreateAccountTX(context.Background(), arg)
+	require.ErrorIs(t, err, ErrCurrencyAccountExists)
+
+	_, err = store.UpdateUserSettings(context.Background(), UpdateUserSettingsParams{
+		Username:         user.Username,
+		MultipleAccounts: sql.NullBool{Bool: true, Valid: true},
+		MaxAccounts:      sql.NullInt32{Int32: 2, Valid: true},
+	})
+	require.NoError(t, err)
+
+	second, err := store.CreateAccountTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, "bills", second.Name)
+
+	arg.Name = "travel"
+	_, err = store.CreateAccountTX(context.Background(), arg)
+	var capErr *AccountCapError
+	require.ErrorAs(t, err, &capErr)
+	require.Equal(t, int32(2), capErr.MaxAccounts)
+}
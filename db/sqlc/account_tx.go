@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCurrencyAccountExists is returned when a user who hasn't opted in to
+// multiple accounts per currency opens a second account in a currency.
+var ErrCurrencyAccountExists = errors.New("already has an account in this currency, opt in to multiple accounts per currency first")
+
+// AccountCapError is returned when a user already owns as many accounts as
+// they may.
+type AccountCapError struct {
+	Owner       string
+	MaxAccounts int32
+}
+
+func (e *AccountCapError) Error() string {
+	return fmt.Sprintf("%s already owns the maximum of %d accounts", e.Owner, e.MaxAccounts)
+}
+
+// checkNewAccount checks that the owner may open another account in the
+// currency. The owner's row is locked so that concurrent account openings
+// can't both pass the check.
+func checkNewAccount(ctx context.Context, q *Queries, arg CreateAccountParams) error {
+	user, err := q.GetUserForUpdate(ctx, arg.Owner)
+	if err != nil {
+		return err
+	}
+
+	count, err := q.CountOwnerAccounts(ctx, CountOwnerAccountsParams{
+		Currency: arg.Currency,
+		Owner:    arg.Owner,
+	})
+	if err != nil {
+		return err
+	}
+
+	if count.Total >= int64(user.MaxAccounts) {
+		return &AccountCapError{Owner: user.Username, MaxAccounts: user.MaxAccounts}
+	}
+	if count.InCurrency > 0 && !user.MultipleAccounts {
+		return ErrCurrencyAccountExists
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	ApprovalStatusExpired  = "expired"
+)
+
+var (
+	ErrApprovalNotPending = errors.New("transfer approval is not pending")
+	ErrApprovalExpired    = errors.New("transfer approval has expired")
+	ErrSelfApproval       = errors.New("transfer can't be approved by its requester")
+)
+
+// Expired reports whether a pending approval has passed its expiry time.
+// Expired approvals can no longer be decided and are marked expired by
+// ExpireTransferApprovals.
+func (approval TransferApproval) Expired(now time.Time) bool {
+	return approval.Status == ApprovalStatusPending && !now.Before(approval.ExpiresAt)
+}
+
+type RequestApprovalTxParams struct {
+	TransferTxParams
+	RequestedBy string    `json:"requested_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// RequestApprovalTX records a pending transfer and the approval it waits for.
+// No money moves until the approval is granted.
+func (store *SQLStore) RequestApprovalTX(ctx context.Context, arg RequestApprovalTxParams) (TransferApproval, error) {
+	var approval TransferApproval
+	err := store.execTX(ctx, func(q *Queries) error {
+		transfer, err := insertTransfer(ctx, q, CreateTransferParams{
+			FromAccountID:     arg.FromAccountID,
+			ToAccountID:       arg.ToAccountID,
+			Amount:            arg.Amount,
+			Description:       arg.Description,
+			ExternalReference: arg.ExternalReference,
+			Status:            TransferStatusPending,
+		})
+		if err != nil {
+			return err
+		}
+
+		approval, err = q.CreateTransferApproval(ctx, CreateTransferApprovalParams{
+			FromAccountID:     arg.FromAccountID,
+			ToAccountID:       arg.ToAccountID,
+			Amount:            arg.Amount,
+			Description:       arg.Description,
+			ExternalReference: arg.ExternalReference,
+			RequestedBy:       arg.RequestedBy,
+			ExpiresAt:         arg.ExpiresAt,
+			TransferID:        sql.NullInt64{Int64: transfer.ID, Valid: true},
+		})
+		return err
+	})
+	return approval, err
+}
+
+type DecideTransferTxParams struct {
+	ApprovalID int64  `json:"approval_id"`
+	DecidedBy  string `json:"decided_by"`
+	Approve    bool   `json:"approve"`
+}
+
+type DecideTransferTxResult struct {
+	Approval TransferApproval `json:"approval"`
+	// Transfer is only set when the approval was granted.
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// DecideTransferTX approves or rejects a pending transfer. Approving settles
+// the transfer in the same transaction, so an approval that fails the balance
+// or limit checks stays pending. Rejecting fails the transfer.
+func (store *SQLStore) DecideTransferTX(ctx context.Context, arg DecideTransferTxParams) (DecideTransferTxResult, error) {
+	var result DecideTransferTxResult
+	err := store.execTX(ctx, func(q *Queries) error {
+		approval, err := q.GetTransferApprovalForUpdate(ctx, arg.ApprovalID)
+		if err != nil {
+			return err
+		}
+
+		if approval.Status != ApprovalStatusPending {
+			return ErrApprovalNotPending
+		}
+
+		if approval.Expired(time.Now()) {
+			return ErrApprovalExpired
+		}
+
+		if approval.RequestedBy == arg.DecidedBy {
+			return ErrSelfApproval
+		}
+
+		decision := DecideTransferApprovalParams{
+			ID:         approval.ID,
+			Status:     ApprovalStatusRejected,
+			DecidedBy:  sql.NullString{String: arg.DecidedBy, Valid: true},
+			TransferID: approval.TransferID,
+		}
+
+		pending, err := q.GetTransferForUpdate(ctx, approval.TransferID.Int64)
+		if err != nil {
+			return err
+		}
+
+		if arg.Approve {
+			processing, err := setTransferStatus(ctx, q, pending, TransferStatusProcessing, "approved by "+arg.DecidedBy)
+			if err != nil {
+				return err
+			}
+
+			transfer, err := settleTransfer(ctx, q, processing)
+			if err != nil {
+				return err
+			}
+
+			result.Transfer = &transfer
+			decision.Status = ApprovalStatusApproved
+		} else {
+			_, err = setTransferStatus(ctx, q, pending, TransferStatusFailed, "rejected by "+arg.DecidedBy)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Approval, err = q.DecideTransferApproval(ctx, decision)
+		return err
+	})
+	return result, err
+}
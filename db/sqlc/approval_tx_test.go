@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomApproval(t *testing.T, from, to Account, amount int64, expiresAt time.Time) TransferApproval {
+	store := NewStore(testDB)
+
+	approval, err := store.RequestApprovalTX(context.Background(), RequestApprovalTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+		},
+		RequestedBy: from.Owner,
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ApprovalStatusPending, approval.Status)
+	require.False(t, approval.DecidedBy.Valid)
+	require.True(t, approval.TransferID.Valid)
+
+	transfer, err := testQueries.GetTransfer(context.Background(), approval.TransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusPending, transfer.Status)
+
+	return approval
+}
+
+func TestDecideTransferTXApprove(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+	approval := createRandomApproval(t, account1, account2, 60, time.Now().Add(time.Hour))
+
+	result, err := store.DecideTransferTX(context.Background(), DecideTransferTxParams{
+		ApprovalID: approval.ID,
+		DecidedBy:  banker.Username,
+		Approve:    true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ApprovalStatusApproved, result.Approval.Status)
+	require.Equal(t, banker.Username, result.Approval.DecidedBy.String)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Approval.TransferID.Int64)
+	require.Equal(t, int64(40), result.Transfer.FromAccount.Balance)
+	require.Equal(t, TransferStatusCompleted, result.Transfer.Transfer.Status)
+
+	_, err = store.DecideTransferTX(context.Background(), DecideTransferTxParams{
+		ApprovalID: approval.ID,
+		DecidedBy:  banker.Username,
+		Approve:    true,
+	})
+	require.ErrorIs(t, err, ErrApprovalNotPending)
+}
+
+func TestDecideTransferTXReject(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+	approval := createRandomApproval(t, account1, account2, 60, time.Now().Add(time.Hour))
+
+	result, err := store.DecideTransferTX(context.Background(), DecideTransferTxParams{
+		ApprovalID: approval.ID,
+		DecidedBy:  banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ApprovalStatusRejected, result.Approval.Status)
+	require.Nil(t, result.Transfer)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account.Balance)
+
+	transfer, err := testQueries.GetTransfer(context.Background(), approval.TransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusFailed, transfer.Status)
+	require.Equal(t, "rejected by "+banker.Username, transfer.FailureReason)
+}
+
+func TestDecideTransferTXSelfApproval(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+	approval := createRandomApproval(t, account1, account2, 60, time.Now().Add(time.Hour))
+
+	_, err := store.DecideTransferTX(context.Background(), DecideTransferTxParams{
+		ApprovalID: approval.ID,
+		DecidedBy:  account1.Owner,
+		Approve:    true,
+	})
+	require.ErrorIs(t, err, ErrSelfApproval)
+}
+
+func TestExpireTransferApprovals(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+	approval := createRandomApproval(t, account1, account2, 60, time.Now().Add(-time.Minute))
+
+	_, err := store.DecideTransferTX(context.Background(), DecideTransferTxParams{
+		ApprovalID: approval.ID,
+		DecidedBy:  banker.Username,
+		Approve:    true,
+	})
+	require.ErrorIs(t, err, ErrApprovalExpired)
+
+	expired, err := testQueries.ExpireTransferApprovals(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, expired, int64(1))
+
+	approval, err = testQueries.GetTransferApproval(context.Background(), approval.ID)
+	require.NoError(t, err)
+	require.Equal(t, ApprovalStatusExpired, approval.Status)
+	require.True(t, approval.DecidedAt.Valid)
+
+	transfer, err := testQueries.GetTransfer(context.Background(), approval.TransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusFailed, transfer.Status)
+	require.Equal(t, "approval expired", transfer.FailureReason)
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrBatchAborted is reported for transfers that were rolled back because
+// another transfer in the same atomic batch failed.
+var ErrBatchAborted = errors.New("batch aborted by another failed transfer")
+
+const defaultBatchChunkSize = 100
+
+type BatchTransferTxParams struct {
+	Transfers []TransferTxParams `json:"transfers"`
+	// Atomic executes every transfer in a single transaction which fails as a
+	// whole, only the transfer that failed it is recorded as failed.
+	// Otherwise transfers succeed or fail individually and are committed in
+	// chunks of ChunkSize.
+	Atomic    bool `json:"atomic"`
+	ChunkSize int  `json:"chunk_size"`
+}
+
+type BatchTransferItemResult struct {
+	Result TransferTxResult `json:"result"`
+	Err    error            `json:"-"`
+}
+
+type BatchTransferTxResult struct {
+	// Items holds one result per transfer, in request order.
+	Items []BatchTransferItemResult `json:"items"`
+}
+
+func (store *SQLStore) BatchTransferTX(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	result := BatchTransferTxResult{
+		Items: make([]BatchTransferItemResult, len(arg.Transfers)),
+	}
+
+	if arg.Atomic {
+		failed := -1
+		err := store.execTX(ctx, func(q *Queries) error {
+			failed = -1
+			for i, transferArg := range arg.Transfers {
+				var err error
+				result.Items[i].Result, err = transfer(ctx, q, transferArg)
+				if err != nil {
+					failed = i
+					return err
+				}
+			}
+			return nil
+		})
+
+		if err != nil {
+			for i := range result.Items {
+				result.Items[i] = BatchTransferItemResult{Err: ErrBatchAborted}
+			}
+			if failed >= 0 {
+				if isTransferFailure(err) {
+					err = store.recordFailure(ctx, arg.Transfers[failed], err)
+				}
+				result.Items[failed].Err = err
+			} else {
+				// The commit itself failed, nothing was applied.
+				for i := range result.Items {
+					result.Items[i].Err = err
+				}
+			}
+		}
+		return result, nil
+	}
+
+	chunkSize := arg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultBatchChunkSize
+	}
+
+	for start := 0; start < len(arg.Transfers); start += chunkSize {
+		end := start + chunkSize
+		if end > len(arg.Transfers) {
+			end = len(arg.Transfers)
+		}
+
+		err := store.execTX(ctx, func(q *Queries) error {
+			for i := start; i < end; i++ {
+				item := &result.Items[i]
+				*item = BatchTransferItemResult{}
+				err := savepoint(ctx, q, func() error {
+					var err error
+					item.Result, err = transfer(ctx, q, arg.Transfers[i])
+					return err
+				})
+				if isTransferFailure(err) {
+					err = recordBatchFailure(ctx, q, arg.Transfers[i], err)
+				}
+
+				// A conflict with another transaction aborts the whole chunk
+				// so that execTX can run it again.
+				var spErr *savepointError
+				if errors.As(err, &spErr) || isRetryable(err) {
+					return err
+				}
+
+				if err != nil {
+					*item = BatchTransferItemResult{Err: err}
+				}
+			}
+			return nil
+		})
+
+		if err != nil {
+			// The chunk was rolled back, so none of its transfers happened.
+			for i := start; i < end; i++ {
+				result.Items[i] = BatchTransferItemResult{Err: err}
+			}
+		}
+
+		if ctx.Err() != nil {
+			for i := end; i < len(arg.Transfers); i++ {
+				result.Items[i].Err = ctx.Err()
+			}
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// recordBatchFailure records a batch transfer refused by cause as failed, in
+// the transaction of its chunk once the transfer was rolled back to its
+// savepoint. Errors that abort the chunk are returned as they are.
+func recordBatchFailure(ctx context.Context, q *Queries, arg TransferTxParams, cause error) error {
+	var failed Transfer
+	err := savepoint(ctx, q, func() error {
+		var err error
+		failed, err = recordFailedTransfer(ctx, q, arg, cause.Error())
+		return err
+	})
+
+	var spErr *savepointError
+	if errors.As(err, &spErr) || isRetryable(err) {
+		return err
+	}
+	if err != nil {
+		return cause
+	}
+	return &TransferFailedError{Transfer: failed, Err: cause}
+}
+
+// savepointError is returned when a savepoint itself can't be created,
+// released or rolled back, which leaves the transaction unusable.
+type savepointError struct {
+	err error
+}
+
+func (e *savepointError) Error() string {
+	return fmt.Sprintf("savepoint: %v", e.err)
+}
+
+func (e *savepointError) Unwrap() error {
+	return e.err
+}
+
+// savepoint runs fn inside a savepoint, so a failing fn only rolls back its
+// own changes and the surrounding transaction can carry on.
+func savepoint(ctx context.Context, q *Queries, fn func() error) error {
+	if _, err := q.db.ExecContext(ctx, "SAVEPOINT batch_item"); err != nil {
+		return &savepointError{err}
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_item"); rbErr != nil {
+			return &savepointError{fmt.Errorf("tx error %v, rb err: %v", err, rbErr)}
+		}
+		return err
+	}
+
+	if _, err := q.db.ExecContext(ctx, "RELEASE SAVEPOINT batch_item"); err != nil {
+		return &savepointError{err}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferTxAtomic(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	result, err := store.BatchTransferTX(context.Background(), BatchTransferTxParams{
+		Transfers: []TransferTxParams{
+			{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 60},
+			{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 60},
+		},
+		Atomic: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+	require.ErrorIs(t, result.Items[0].Err, ErrBatchAborted)
+	require.ErrorIs(t, result.Items[1].Err, ErrInsufficientFunds)
+	requireFailedTransfer(t, store, result.Items[1].Err)
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
+
+// requireFailedTransfer checks that err reports a transfer recorded as
+// failed.
+func requireFailedTransfer(t *testing.T, store *SQLStore, err error) {
+	var failedErr *TransferFailedError
+	require.ErrorAs(t, err, &failedErr)
+
+	transfer, err := store.GetTransfer(context.Background(), failedErr.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusFailed, transfer.Status)
+	require.Equal(t, failedErr.Err.Error(), transfer.FailureReason)
+}
+
+func TestBatchTransferTxBestEffort(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	amounts := []int64{30, 80, 30, 30, 30}
+	transfers := make([]TransferTxParams, len(amounts))
+	for i, amount := range amounts {
+		transfers[i] = TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		}
+	}
+
+	result, err := store.BatchTransferTX(context.Background(), BatchTransferTxParams{
+		Transfers: transfers,
+		ChunkSize: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Items, len(amounts))
+
+	require.NoError(t, result.Items[0].Err)
+	require.ErrorIs(t, result.Items[1].Err, ErrInsufficientFunds)
+	require.NoError(t, result.Items[2].Err)
+	require.NoError(t, result.Items[3].Err)
+	require.ErrorIs(t, result.Items[4].Err, ErrInsufficientFunds)
+	requireFailedTransfer(t, store, result.Items[1].Err)
+	requireFailedTransfer(t, store, result.Items[4].Err)
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-90, updatedAccount1.Balance)
+
+	updatedAccount2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance+90, updatedAccount2.Balance)
+}
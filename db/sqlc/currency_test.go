@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+
+	codes := make([]string, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency.Code
+	}
+	require.Subset(t, codes, []string{"CAD", "EUR", "USD"})
+}
+
+func TestUpdateCurrency(t *testing.T) {
+	currency, err := testQueries.CreateCurrency(context.Background(), CreateCurrencyParams{Code: "CHF", Enabled: true})
+	if err != nil {
+		// Left behind by an earlier run.
+		currency, err = testQueries.GetCurrency(context.Background(), "CHF")
+	}
+	require.NoError(t, err)
+
+	disabled, err := testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:    currency.Code,
+		Enabled: sql.NullBool{Bool: false, Valid: true},
+	})
+	require.NoError(t, err)
+	require.False(t, disabled.Enabled)
+	require.False(t, disabled.UpdatedAt.Before(currency.UpdatedAt))
+
+	enabled, err := testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:    currency.Code,
+		Enabled: sql.NullBool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, enabled.Enabled)
+
+	// Fields left out keep their value.
+	threshold, err := testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:              currency.Code,
+		ApprovalThreshold: sql.NullInt64{Int64: 5000, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, threshold.Enabled)
+	require.Equal(t, int64(5000), threshold.ApprovalThreshold)
+
+	_, err = testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:    "XXX",
+		Enabled: sql.NullBool{Bool: true, Valid: true},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestAccountCurrencyInCatalog(t *testing.T) {
+	_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Currency: "XXX",
+		Name:     "XXX",
+		Product:  ProductChecking,
+	})
+	require.Error(t, err)
+}
+
+func TestCreateCurrencyTX(t *testing.T) {
+	store := NewStore(testDB)
+
+	_, err := store.CreateCurrencyTX(context.Background(), CreateCurrencyParams{Code: "JPY", Enabled: true})
+	if err != nil {
+		// Left behind by an earlier run.
+		_, err = store.GetCurrency(context.Background(), "JPY")
+	}
+	require.NoError(t, err)
+
+	for _, system := range systemAccountOwners {
+		systemAccount, err := store.GetSystemAccount(context.Background(), GetSystemAccountParams{
+			Purpose:  system.purpose,
+			Currency: "JPY",
+		})
+		require.NoError(t, err)
+
+		account, err := store.GetAccount(context.Background(), systemAccount.AccountID)
+		require.NoError(t, err)
+		require.Equal(t, system.owner, account.Owner)
+		require.Equal(t, "JPY", account.Currency)
+	}
+
+	// The default limits are in major units, the yen has no minor unit.
+	limits, err := store.GetCurrencyLimits(context.Background(), "JPY")
+	require.NoError(t, err)
+	require.Equal(t, sql.NullInt64{Int64: 10000, Valid: true}, limits.PerTransaction)
+	require.Equal(t, sql.NullInt64{Int64: 25000, Valid: true}, limits.Daily)
+	require.Equal(t, sql.NullInt64{Int64: 100000, Valid: true}, limits.Monthly)
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// systemAccountOwners lists the users holding the system accounts of each
+// purpose, they hold one account per currency.
+var systemAccountOwners = []struct {
+	purpose string
+	owner   string
+}{
+	{SystemAccountFeeRevenue, "bankfees"},
+	{SystemAccountInterestExpense, "bankinterest"},
+	{SystemAccountOverdraftInterest, "bankoverdraft"},
+}
+
+// CreateCurrencyTX adds a currency to the catalog together with its default
+// transfer limits and its system accounts, so fees and interest can be booked
+// in it from the start.
+func (store *SQLStore) CreateCurrencyTX(ctx context.Context, arg CreateCurrencyParams) (Currency, error) {
+	var currency Currency
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		currency, err = q.CreateCurrency(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if err := createDefaultCurrencyLimits(ctx, q, currency.Code); err != nil {
+			return err
+		}
+
+		for _, system := range systemAccountOwners {
+			account, err := q.CreateAccount(ctx, CreateAccountParams{
+				Owner:    system.owner,
+				Currency: currency.Code,
+				Name:     currency.Code,
+				Product:  ProductChecking,
+			})
+			if err != nil {
+				return fmt.Errorf("create %s account: %w", system.purpose, err)
+			}
+			if err := addPrimaryOwner(ctx, q, account); err != nil {
+				return err
+			}
+
+			_, err = q.CreateSystemAccount(ctx, CreateSystemAccountParams{
+				Purpose:   system.purpose,
+				Currency:  currency.Code,
+				AccountID: account.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return currency, err
+}
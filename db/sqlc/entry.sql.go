@@ -7,36 +7,61 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id,
+  description,
+  external_reference,
+  kind,
+  journal_id
 ) VALUES (
-  $1, $2
-) RETURNING id, account_id, amount, created_at
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, account_id, amount, created_at, transfer_id, description, external_reference, kind, journal_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID         int64         `json:"account_id"`
+	Amount            int64         `json:"amount"`
+	TransferID        sql.NullInt64 `json:"transfer_id"`
+	Description       string        `json:"description"`
+	ExternalReference string        `json:"external_reference"`
+	Kind              string        `json:"kind"`
+	JournalID         sql.NullInt64 `json:"journal_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.Description,
+		arg.ExternalReference,
+		arg.Kind,
+		arg.JournalID,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Kind,
+		&i.JournalID,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, description, external_reference, kind, journal_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,12 +73,21 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Kind,
+		&i.JournalID,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT
+  id, account_id, amount, created_at, transfer_id, description,
+  external_reference, kind, journal_id,
+  (SUM(amount) OVER (ORDER BY id))::bigint AS running_balance
+FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -66,20 +100,41 @@ type ListEntriesParams struct {
 	Offset    int32 `json:"offset"`
 }
 
-func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+type ListEntriesRow struct {
+	ID                int64         `json:"id"`
+	AccountID         int64         `json:"account_id"`
+	Amount            int64         `json:"amount"`
+	CreatedAt         time.Time     `json:"created_at"`
+	TransferID        sql.NullInt64 `json:"transfer_id"`
+	Description       string        `json:"description"`
+	ExternalReference string        `json:"external_reference"`
+	Kind              string        `json:"kind"`
+	JournalID         sql.NullInt64 `json:"journal_id"`
+	RunningBalance    int64         `json:"running_balance"`
+}
+
+// The running balance is taken over every entry of the account, the window
+// is evaluated before the page is cut.
+func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]ListEntriesRow, error) {
 	rows, err := q.db.QueryContext(ctx, listEntries, arg.AccountID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Entry{}
+	items := []ListEntriesRow{}
 	for rows.Next() {
-		var i Entry
+		var i ListEntriesRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.Description,
+			&i.ExternalReference,
+			&i.Kind,
+			&i.JournalID,
+			&i.RunningBalance,
 		); err != nil {
 			return nil, err
 		}
@@ -13,6 +13,7 @@ func createRandomEntry(t *testing.T, account Account) Entry {
 	arg := CreateEntryParams{
 		AccountID: account.ID,
 		Amount:    util.RandomMoney(),
+		Kind:      EntryKindTransfer,
 	}
 
 	entry, err := testQueries.CreateEntry(context.Background(), arg)
@@ -62,8 +63,11 @@ func TestListEntries(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, entries, 5)
 
-	for _, entry := range entries {
+	for i, entry := range entries {
 		require.NotEmpty(t, entry)
 		require.Equal(t, arg.AccountID, entry.AccountID)
+		if i > 0 {
+			require.Equal(t, entries[i-1].RunningBalance+entry.Amount, entry.RunningBalance)
+		}
 	}
 }
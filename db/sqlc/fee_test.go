@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeRuleFee(t *testing.T) {
+	testcase := []struct {
+		name   string
+		rule   FeeRule
+		amount int64
+		fee    int64
+	}{
+		{
+			name:   "Flat",
+			rule:   FeeRule{Flat: 25},
+			amount: 1000,
+			fee:    25,
+		},
+		{
+			name:   "Percentage",
+			rule:   FeeRule{PercentBps: 150},
+			amount: 1000,
+			fee:    15,
+		},
+		{
+			name:   "RoundsHalfUp",
+			rule:   FeeRule{PercentBps: 50},
+			amount: 100,
+			fee:    1,
+		},
+		{
+			name:   "Minimum",
+			rule:   FeeRule{PercentBps: 100, MinFee: sql.NullInt64{Int64: 5, Valid: true}},
+			amount: 100,
+			fee:    5,
+		},
+		{
+			name:   "Maximum",
+			rule:   FeeRule{Flat: 10, PercentBps: 100, MaxFee: sql.NullInt64{Int64: 50, Valid: true}},
+			amount: 100000,
+			fee:    50,
+		},
+		{
+			name:   "LargeAmount",
+			rule:   FeeRule{PercentBps: 100},
+			amount: math.MaxInt64 / 10,
+			fee:    (math.MaxInt64/10)/100 + 1,
+		},
+		{
+			name:   "LargeAmountMaximum",
+			rule:   FeeRule{PercentBps: 10000, MaxFee: sql.NullInt64{Int64: 500, Valid: true}},
+			amount: math.MaxInt64,
+			fee:    500,
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			fee, err := tc.rule.Fee(tc.amount)
+			require.NoError(t, err)
+			require.Equal(t, tc.fee, fee)
+		})
+	}
+
+	_, err := FeeRule{Flat: 1, PercentBps: 10000}.Fee(math.MaxInt64)
+	require.ErrorIs(t, err, ErrFeeOverflow)
+}
+
+func TestTransferTxFee(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	tier := util.RandomString(10)
+	account1, err := testQueries.UpdateAccountTier(context.Background(), UpdateAccountTierParams{
+		ID:   account1.ID,
+		Tier: tier,
+	})
+	require.NoError(t, err)
+
+	rule, err := testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{
+		Currency:   account1.Currency,
+		Tier:       sql.NullString{String: tier, Valid: true},
+		Flat:       2,
+		PercentBps: 100,
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteFeeRule(context.Background(), rule.ID)
+
+	revenue, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountFeeRevenue,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	before, err := testQueries.GetAccount(context.Background(), revenue.AccountID)
+	require.NoError(t, err)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        500,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(7), result.Fee)
+	require.NotNil(t, result.FeeEntry)
+	require.Equal(t, int64(-7), result.FeeEntry.Amount)
+	require.Equal(t, EntryKindFee, result.FeeEntry.Kind)
+	require.Equal(t, result.Transfer.ID, result.FeeEntry.TransferID.Int64)
+	require.Equal(t, result.Journal.ID, result.FeeEntry.JournalID.Int64)
+	require.Equal(t, int64(1000-500-7), result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+500, result.ToAccount.Balance)
+
+	after, err := testQueries.GetAccount(context.Background(), revenue.AccountID)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, after.Balance, before.Balance+7)
+}
+
+func TestTransferTxFeeInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	tier := util.RandomString(10)
+	_, err := testQueries.UpdateAccountTier(context.Background(), UpdateAccountTierParams{
+		ID:   account1.ID,
+		Tier: tier,
+	})
+	require.NoError(t, err)
+
+	rule, err := testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{
+		Currency: account1.Currency,
+		Tier:     sql.NullString{String: tier, Valid: true},
+		Flat:     1,
+	})
+	require.NoError(t, err)
+	defer testQueries.DeleteFeeRule(context.Background(), rule.ID)
+
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestListAndDeleteFeeRules(t *testing.T) {
+	tier := util.RandomString(10)
+	rule, err := testQueries.CreateFeeRule(context.Background(), CreateFeeRuleParams{
+		Currency: "CAD",
+		Tier:     sql.NullString{String: tier, Valid: true},
+		Flat:     3,
+	})
+	require.NoError(t, err)
+
+	rules, err := testQueries.ListFeeRules(context.Background(), sql.NullString{String: "CAD", Valid: true})
+	require.NoError(t, err)
+	require.Contains(t, rules, rule)
+	for _, listed := range rules {
+		require.Equal(t, "CAD", listed.Currency)
+	}
+
+	rules, err = testQueries.ListFeeRules(context.Background(), sql.NullString{})
+	require.NoError(t, err)
+	require.Contains(t, rules, rule)
+
+	deleted, err := testQueries.DeleteFeeRule(context.Background(), rule.ID)
+	require.NoError(t, err)
+	require.Equal(t, rule.ID, deleted.ID)
+
+	_, err = testQueries.DeleteFeeRule(context.Background(), rule.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
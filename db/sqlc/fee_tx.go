@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	EntryKindTransfer = "transfer"
+	EntryKindFee      = "fee"
+	// EntryKindImport marks entries imported from the old core.
+	EntryKindImport   = "import"
+	EntryKindInterest = "interest"
+)
+
+const SystemAccountFeeRevenue = "fee_revenue"
+
+// ErrFeeOverflow is returned when a fee rule charges more on an amount than
+// a balance can hold.
+var ErrFeeOverflow = errors.New("fee is too large")
+
+// Fee returns the fee the rule charges on amount: the flat part plus the
+// percentage, rounded half up, clamped to the rule's minimum and maximum.
+// The fee is worked out on big integers since amount times the rate can
+// overflow even when the fee itself doesn't.
+func (rule FeeRule) Fee(amount int64) (int64, error) {
+	fee := new(big.Int).Mul(big.NewInt(amount), big.NewInt(int64(rule.PercentBps)))
+	fee.Add(fee, big.NewInt(5000))
+	fee.Quo(fee, big.NewInt(10000))
+	fee.Add(fee, big.NewInt(rule.Flat))
+
+	if rule.MinFee.Valid && fee.Cmp(big.NewInt(rule.MinFee.Int64)) < 0 {
+		fee.SetInt64(rule.MinFee.Int64)
+	}
+	if rule.MaxFee.Valid && fee.Cmp(big.NewInt(rule.MaxFee.Int64)) > 0 {
+		fee.SetInt64(rule.MaxFee.Int64)
+	}
+	if !fee.IsInt64() {
+		return 0, ErrFeeOverflow
+	}
+	return fee.Int64(), nil
+}
+
+// feeLegs returns the journal legs charging the fee for a transfer to the
+// sender and crediting the fee revenue account of its currency. The rule for
+// the sender's tier wins over the currency wide rule, transfers without a
+// rule are free and have no fee legs.
+func feeLegs(ctx context.Context, q *Queries, from Account, transfer Transfer) (fee int64, legs []JournalLeg, err error) {
+	rule, err := q.GetFeeRule(ctx, GetFeeRuleParams{
+		Currency: from.Currency,
+		Tier:     sql.NullString{String: from.Tier, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil, nil
+		}
+		return
+	}
+
+	fee, err = rule.Fee(transfer.Amount)
+	if err != nil || fee <= 0 {
+		return 0, nil, err
+	}
+
+	revenue, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountFeeRevenue,
+		Currency: from.Currency,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("no %s account in %s", SystemAccountFeeRevenue, from.Currency)
+		}
+		return
+	}
+
+	charged, err := from.inCurrency(fee).Neg()
+	if err != nil {
+		return
+	}
+	legs = []JournalLeg{
+		{AccountID: from.ID, Amount: charged.Amount(), Kind: EntryKindFee, Description: "transfer fee"},
+		{AccountID: revenue.AccountID, Amount: fee, Kind: EntryKindFee, Description: "transfer fee"},
+	}
+	return fee, legs, nil
+}
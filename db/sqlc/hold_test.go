@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHold(t *testing.T, account1, account2 Account) Hold {
+	arg := CreateHoldParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      util.RandomInt(1, 100),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	hold, err := testQueries.CreateHold(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, hold)
+
+	require.Equal(t, arg.AccountID, hold.AccountID)
+	require.Equal(t, arg.ToAccountID, hold.ToAccountID)
+	require.Equal(t, arg.Amount, hold.Amount)
+	require.Equal(t, HoldStatusActive, hold.Status)
+	require.Zero(t, hold.CapturedAmount)
+	require.WithinDuration(t, arg.ExpiresAt, hold.ExpiresAt, time.Second)
+
+	require.NotZero(t, hold.ID)
+	require.NotZero(t, hold.CreatedAt)
+
+	return hold
+}
+
+func TestCreateHold(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	createRandomHold(t, account1, account2)
+}
+
+func TestGetHold(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	hold1 := createRandomHold(t, account1, account2)
+
+	hold2, err := testQueries.GetHold(context.Background(), hold1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, hold2)
+
+	require.Equal(t, hold1.ID, hold2.ID)
+	require.Equal(t, hold1.AccountID, hold2.AccountID)
+	require.Equal(t, hold1.Amount, hold2.Amount)
+	require.Equal(t, hold1.Status, hold2.Status)
+	require.WithinDuration(t, hold1.CreatedAt, hold2.CreatedAt, time.Second)
+}
+
+func TestListHolds(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	for i := 0; i < 10; i++ {
+		createRandomHold(t, account1, account2)
+	}
+
+	arg := ListHoldsParams{
+		AccountID: account1.ID,
+		Limit:     5,
+		Offset:    5,
+	}
+
+	holds, err := testQueries.ListHolds(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, holds, 5)
+
+	for _, hold := range holds {
+		require.NotEmpty(t, hold)
+		require.Equal(t, account1.ID, hold.AccountID)
+	}
+}
+
+func TestGetHeldAmount(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	var total int64
+	for i := 0; i < 3; i++ {
+		total += createRandomHold(t, account1, account2).Amount
+	}
+
+	released := createRandomHold(t, account1, account2)
+	_, err := testQueries.UpdateHoldStatus(context.Background(), UpdateHoldStatusParams{
+		ID:     released.ID,
+		Status: HoldStatusReleased,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateHold(context.Background(), CreateHoldParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      100,
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	held, err := testQueries.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, total, held)
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aryan-more/simple_bank/money"
+)
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+)
+
+var (
+	ErrHoldNotActive      = errors.New("hold is not active")
+	ErrHoldExpired        = errors.New("hold has expired")
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds held amount")
+)
+
+// Expired reports whether an active hold has passed its expiry time. Expired
+// holds no longer reserve funds and can only be released.
+func (hold Hold) Expired(now time.Time) bool {
+	return hold.Status == HoldStatusActive && !now.Before(hold.ExpiresAt)
+}
+
+type PlaceHoldTxParams struct {
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// inCurrency returns an amount in the account's currency. The code is all
+// the arithmetic needs to keep amounts of different currencies apart.
+func (account Account) inCurrency(amount int64) money.Money {
+	return money.New(amount, money.Currency{Code: account.Currency})
+}
+
+// AvailableBalance returns the account's balance less the funds held on it.
+func (account Account) AvailableBalance(held int64) (int64, error) {
+	available, err := account.inCurrency(account.Balance).Sub(account.inCurrency(held))
+	return available.Amount(), err
+}
+
+// AvailableCredit returns what can still be debited from the account given
+// the funds held on it: its available balance plus its overdraft limit.
+func (account Account) AvailableCredit(held int64) (int64, error) {
+	available, err := account.AvailableBalance(held)
+	if err != nil {
+		return 0, err
+	}
+	credit, err := account.inCurrency(available).Add(account.inCurrency(account.OverdraftLimit))
+	return credit.Amount(), err
+}
+
+// PlaceHoldTX reserves funds on an account. The account row is locked so the
+// available balance check can't race with transfers or other holds.
+func (store *SQLStore) PlaceHoldTX(ctx context.Context, arg PlaceHoldTxParams) (Hold, error) {
+	var hold Hold
+	err := store.execTX(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		held, err := q.GetHeldAmount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		credit, err := account.AvailableCredit(held)
+		if err != nil {
+			return err
+		}
+		if credit < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID:   arg.AccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount:      arg.Amount,
+			ExpiresAt:   arg.ExpiresAt,
+		})
+		return err
+	})
+	return hold, err
+}
+
+type CaptureHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount to capture, zero captures the full hold.
+	Amount int64 `json:"amount"`
+}
+
+type CaptureHoldTxResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CaptureHoldTX settles a hold by transferring the captured amount to the
+// hold's destination account. Any uncaptured remainder is released.
+func (store *SQLStore) CaptureHoldTX(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+	err := store.execTX(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != HoldStatusActive {
+			return ErrHoldNotActive
+		}
+
+		if hold.Expired(time.Now()) {
+			return ErrHoldExpired
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+
+		if amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		// Mark the hold captured first so it no longer counts against the
+		// available balance checked by the transfer.
+		result.Hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:             hold.ID,
+			Status:         HoldStatusCaptured,
+			CapturedAmount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        amount,
+		})
+		return err
+	})
+	return result, err
+}
+
+// ReleaseHoldTX cancels an active hold, making its funds available again.
+func (store *SQLStore) ReleaseHoldTX(ctx context.Context, holdID int64) (Hold, error) {
+	var hold Hold
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		hold, err = q.GetHoldForUpdate(ctx, holdID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != HoldStatusActive {
+			return ErrHoldNotActive
+		}
+
+		hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:             hold.ID,
+			Status:         HoldStatusReleased,
+			CapturedAmount: 0,
+		})
+		return err
+	})
+	return hold, err
+}
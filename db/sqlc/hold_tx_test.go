@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	hold, err := store.PlaceHoldTX(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      60,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusActive, hold.Status)
+
+	// Only 40 is still available, both a second hold and a transfer
+	// exceeding it must fail.
+	_, err = store.PlaceHoldTX(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      41,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        41,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        40,
+	})
+	require.NoError(t, err)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	hold, err := store.PlaceHoldTX(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      60,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTX(context.Background(), CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: 61,
+	})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	result, err := store.CaptureHoldTX(context.Background(), CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: 25,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.Equal(t, int64(25), result.Hold.CapturedAmount)
+	require.Equal(t, int64(25), result.Transfer.Transfer.Amount)
+	require.Equal(t, account1.Balance-25, result.Transfer.FromAccount.Balance)
+	require.Equal(t, account2.Balance+25, result.Transfer.ToAccount.Balance)
+
+	// The uncaptured remainder is no longer held.
+	held, err := store.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+
+	_, err = store.CaptureHoldTX(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestCaptureExpiredHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	hold, err := store.CreateHold(context.Background(), CreateHoldParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      10,
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTX(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldExpired)
+
+	released, err := store.ReleaseHoldTX(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusReleased, released.Status)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	hold, err := store.PlaceHoldTX(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      100,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	released, err := store.ReleaseHoldTX(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusReleased, released.Status)
+
+	_, err = store.ReleaseHoldTX(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	held, err := store.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+}
+
+func TestAvailableCredit(t *testing.T) {
+	account := Account{Currency: "USD", Balance: -30, OverdraftLimit: 100}
+
+	available, err := account.AvailableBalance(10)
+	require.NoError(t, err)
+	require.Equal(t, int64(-40), available)
+
+	credit, err := account.AvailableCredit(10)
+	require.NoError(t, err)
+	require.Equal(t, int64(60), credit)
+
+	account.Balance = math.MaxInt64
+	_, err = account.AvailableCredit(0)
+	require.ErrorIs(t, err, money.ErrOverflow)
+
+	account.Balance = math.MinInt64
+	_, err = account.AvailableBalance(1)
+	require.ErrorIs(t, err, money.ErrOverflow)
+}
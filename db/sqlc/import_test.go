@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomImportAccount(t *testing.T) ImportAccount {
+	user := createRandomUser(t)
+	createdAt := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	return ImportAccount{
+		LegacyID:  "legacy-" + util.RandomString(12),
+		Owner:     user.Username,
+		Currency:  "USD",
+		Balance:   150,
+		CreatedAt: createdAt,
+		Entries: []ImportEntry{
+			{Amount: 200, CreatedAt: createdAt.Add(time.Hour), Description: "opening deposit"},
+			{Amount: -50, CreatedAt: createdAt.Add(48 * time.Hour), Description: "card payment", ExternalReference: "old-77"},
+		},
+	}
+}
+
+func TestImportAccountsTx(t *testing.T) {
+	store := NewStore(testDB)
+	arg := ImportAccountsTxParams{Accounts: []ImportAccount{randomImportAccount(t), randomImportAccount(t)}}
+
+	result, err := store.ImportAccountsTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Imported, 2)
+	require.Equal(t, 4, result.Entries)
+	require.Empty(t, result.Skipped)
+
+	legacy := result.Imported[0]
+	require.Equal(t, arg.Accounts[0].LegacyID, legacy.LegacyID)
+
+	account, err := store.GetAccount(context.Background(), legacy.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, arg.Accounts[0].Owner, account.Owner)
+	require.Equal(t, int64(150), account.Balance)
+	require.True(t, account.CreatedAt.Equal(arg.Accounts[0].CreatedAt))
+
+	entries, err := store.ListEntries(context.Background(), ListEntriesParams{AccountID: account.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, EntryKindImport, entries[1].Kind)
+	require.Equal(t, "old-77", entries[1].ExternalReference)
+	require.True(t, entries[1].CreatedAt.Equal(arg.Accounts[0].Entries[1].CreatedAt))
+	require.Equal(t, account.Balance, entries[1].RunningBalance)
+	require.False(t, entries[1].TransferID.Valid)
+
+	// Running the same import again imports nothing.
+	result, err = store.ImportAccountsTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, result.Imported)
+	require.Equal(t, []string{arg.Accounts[0].LegacyID, arg.Accounts[1].LegacyID}, result.Skipped)
+}
+
+func TestImportAccountsTxUnbalanced(t *testing.T) {
+	store := NewStore(testDB)
+	good := randomImportAccount(t)
+	bad := randomImportAccount(t)
+	bad.Balance = 100
+
+	_, err := store.ImportAccountsTX(context.Background(), ImportAccountsTxParams{Accounts: []ImportAccount{good, bad}})
+	var unbalanced *ImportUnbalancedError
+	require.ErrorAs(t, err, &unbalanced)
+	require.Equal(t, bad.LegacyID, unbalanced.LegacyID)
+	require.Equal(t, int64(150), unbalanced.Total)
+
+	// The whole chunk was rolled back.
+	_, err = store.GetLegacyAccount(context.Background(), good.LegacyID)
+	require.Error(t, err)
+}
+
+func TestImportAccountsTxAccountCap(t *testing.T) {
+	store := NewStore(testDB)
+	usd := randomImportAccount(t)
+	eur := usd
+	eur.LegacyID = "legacy-" + util.RandomString(12)
+	eur.Currency = "EUR"
+
+	_, err := store.UpdateUserSettings(context.Background(), UpdateUserSettingsParams{
+		Username:    usd.Owner,
+		MaxAccounts: sql.NullInt32{Int32: 1, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = store.ImportAccountsTX(context.Background(), ImportAccountsTxParams{Accounts: []ImportAccount{usd, eur}})
+	var capErr *AccountCapError
+	require.ErrorAs(t, err, &capErr)
+	require.Equal(t, usd.Owner, capErr.Owner)
+
+	_, err = store.GetLegacyAccount(context.Background(), usd.LegacyID)
+	require.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ImportUnbalancedError is returned for an imported account whose balance
+// isn't the sum of its entries.
+type ImportUnbalancedError struct {
+	LegacyID string `json:"legacy_id"`
+	Balance  int64  `json:"balance"`
+	Total    int64  `json:"total"`
+}
+
+func (e *ImportUnbalancedError) Error() string {
+	return fmt.Sprintf("account %s has balance %d but its entries add up to %d", e.LegacyID, e.Balance, e.Total)
+}
+
+// ImportEntry is an entry of an imported account.
+type ImportEntry struct {
+	Amount            int64     `json:"amount"`
+	CreatedAt         time.Time `json:"created_at"`
+	Description       string    `json:"description"`
+	ExternalReference string    `json:"external_reference"`
+}
+
+// ImportAccount is an account of the old core with its whole history, in the
+// order it was booked.
+type ImportAccount struct {
+	LegacyID  string        `json:"legacy_id"`
+	Owner     string        `json:"owner"`
+	Currency  string        `json:"currency"`
+	Balance   int64         `json:"balance"`
+	CreatedAt time.Time     `json:"created_at"`
+	Entries   []ImportEntry `json:"entries"`
+}
+
+type ImportAccountsTxParams struct {
+	Accounts []ImportAccount `json:"accounts"`
+}
+
+type ImportAccountsTxResult struct {
+	Imported []LegacyAccount `json:"imported"`
+	Entries  int             `json:"entries"`
+	// Skipped lists the legacy ids of accounts imported by an earlier run.
+	Skipped []string `json:"skipped"`
+}
+
+// ImportAccountsTX imports accounts and their entries in a single
+// transaction. Accounts that were already imported are skipped, so an import
+// that failed part way can simply be run again. Imported entries don't emit
+// entry.created events, only the accounts emit account.created.
+func (store *SQLStore) ImportAccountsTX(ctx context.Context, arg ImportAccountsTxParams) (ImportAccountsTxResult, error) {
+	var result ImportAccountsTxResult
+	err := store.execTX(ctx, func(q *Queries) error {
+		result = ImportAccountsTxResult{}
+		for _, account := range arg.Accounts {
+			_, err := q.GetLegacyAccount(ctx, account.LegacyID)
+			if err == nil {
+				result.Skipped = append(result.Skipped, account.LegacyID)
+				continue
+			}
+			if err != sql.ErrNoRows {
+				return err
+			}
+
+			legacy, err := importAccount(ctx, q, account)
+			if err != nil {
+				return err
+			}
+			result.Imported = append(result.Imported, legacy)
+			result.Entries += len(account.Entries)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func importAccount(ctx context.Context, q *Queries, arg ImportAccount) (LegacyAccount, error) {
+	var total int64
+	for _, entry := range arg.Entries {
+		total += entry.Amount
+	}
+	if total != arg.Balance {
+		return LegacyAccount{}, &ImportUnbalancedError{LegacyID: arg.LegacyID, Balance: arg.Balance, Total: total}
+	}
+
+	// Imported accounts count towards the owner's cap like any other.
+	if err := checkNewAccount(ctx, q, CreateAccountParams{Owner: arg.Owner, Currency: arg.Currency}); err != nil {
+		return LegacyAccount{}, err
+	}
+
+	account, err := q.CreateImportedAccount(ctx, CreateImportedAccountParams{
+		Owner:     arg.Owner,
+		Balance:   arg.Balance,
+		Currency:  arg.Currency,
+		CreatedAt: arg.CreatedAt,
+		Name:      arg.Currency,
+	})
+	if err != nil {
+		return LegacyAccount{}, err
+	}
+
+	if err := addPrimaryOwner(ctx, q, account); err != nil {
+		return LegacyAccount{}, err
+	}
+
+	for _, entry := range arg.Entries {
+		err := q.CreateImportedEntry(ctx, CreateImportedEntryParams{
+			AccountID:         account.ID,
+			Amount:            entry.Amount,
+			CreatedAt:         entry.CreatedAt,
+			Description:       entry.Description,
+			ExternalReference: entry.ExternalReference,
+		})
+		if err != nil {
+			return LegacyAccount{}, err
+		}
+	}
+
+	err = addOutboxEvent(ctx, q, AggregateAccount, strconv.FormatInt(account.ID, 10), EventAccountCreated, account)
+	if err != nil {
+		return LegacyAccount{}, err
+	}
+
+	return q.CreateLegacyAccount(ctx, CreateLegacyAccountParams{
+		LegacyID:  arg.LegacyID,
+		AccountID: account.ID,
+	})
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccrueAndPostInterest(t *testing.T) {
+	ctx := context.Background()
+
+	// Accrual days are global, run in a transaction that is rolled back so
+	// the day accrued here doesn't become the last accrued one.
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	q := New(tx)
+
+	source := createCurrencyAccount(t, "USD", 100000)
+	account, err := q.CreateAccount(ctx, CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Currency: "USD",
+		Name:     "USD",
+		Product:  "savings",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "savings", account.Product)
+
+	_, err = postJournal(ctx, q, PostJournalParams{
+		Legs: []JournalLeg{
+			{AccountID: source.ID, Amount: -100000},
+			{AccountID: account.ID, Amount: 100000},
+		},
+	})
+	require.NoError(t, err)
+
+	asOf := time.Now().Add(time.Second)
+	_, err = q.CreateBalanceSnapshots(ctx, asOf)
+	require.NoError(t, err)
+
+	n, err := accrueDay(ctx, q, asOf)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, n, int32(1))
+
+	accruals, err := q.ListInterestAccruals(ctx, ListInterestAccrualsParams{AccountID: account.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+	// 1.50% of 1000.00 over an act/365 day.
+	require.Equal(t, int64(4109589), accruals[0].AmountMicros)
+	require.Equal(t, int64(100000), accruals[0].Balance)
+	require.False(t, accruals[0].PostedAt.Valid)
+
+	months, err := q.ListUnpostedInterest(ctx, asOf.AddDate(0, 2, 0))
+	require.NoError(t, err)
+	var month ListUnpostedInterestRow
+	for _, m := range months {
+		if m.AccountID == account.ID {
+			month = m
+		}
+	}
+	require.Equal(t, int64(4109589), month.AmountMicros)
+
+	journal, err := postInterest(ctx, q, month)
+	require.NoError(t, err)
+	require.NotNil(t, journal)
+
+	account, err = q.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100004), account.Balance)
+
+	accruals, err = q.ListInterestAccruals(ctx, ListInterestAccrualsParams{AccountID: account.ID, Limit: 5})
+	require.NoError(t, err)
+	require.True(t, accruals[0].PostedAt.Valid)
+	require.Equal(t, journal.ID, accruals[0].JournalID.Int64)
+}
+
+func TestAccrueAndPostOverdraftInterest(t *testing.T) {
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	q := New(tx)
+
+	target := createCurrencyAccount(t, "USD", 0)
+	account, err := q.CreateAccount(ctx, CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Currency: "USD",
+		Name:     "USD",
+		Product:  ProductChecking,
+	})
+	require.NoError(t, err)
+	account, err = q.UpdateAccountOverdraft(ctx, UpdateAccountOverdraftParams{
+		ID:               account.ID,
+		OverdraftLimit:   200000,
+		OverdraftRateBps: 1825,
+	})
+	require.NoError(t, err)
+
+	_, err = postJournal(ctx, q, PostJournalParams{
+		Legs: []JournalLeg{
+			{AccountID: account.ID, Amount: -100000},
+			{AccountID: target.ID, Amount: 100000},
+		},
+	})
+	require.NoError(t, err)
+
+	asOf := time.Now().Add(time.Second)
+	_, err = q.CreateBalanceSnapshots(ctx, asOf)
+	require.NoError(t, err)
+
+	_, err = accrueDay(ctx, q, asOf)
+	require.NoError(t, err)
+
+	accruals, err := q.ListInterestAccruals(ctx, ListInterestAccrualsParams{AccountID: account.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+	// 18.25% of 1000.00 overdrawn over an act/365 day is 0.50.
+	require.Equal(t, int64(-50*1_000_000), accruals[0].AmountMicros)
+	require.Equal(t, int32(1825), accruals[0].AnnualRateBps)
+
+	months, err := q.ListUnpostedInterest(ctx, asOf.AddDate(0, 2, 0))
+	require.NoError(t, err)
+	var month ListUnpostedInterestRow
+	for _, m := range months {
+		if m.AccountID == account.ID {
+			month = m
+		}
+	}
+
+	journal, err := postInterest(ctx, q, month)
+	require.NoError(t, err)
+	require.NotNil(t, journal)
+
+	account, err = q.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(-100050), account.Balance)
+}
+
+func TestPostInterestCreditAndOverdraft(t *testing.T) {
+	ctx := context.Background()
+
+	tx, err := testDB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	q := New(tx)
+
+	account, err := q.CreateAccount(ctx, CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Currency: "USD",
+		Name:     "USD",
+		Product:  "savings",
+	})
+	require.NoError(t, err)
+
+	// In credit over the first day of the month, overdrawn over the second.
+	first := time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC)
+	for i, micros := range []int64{3_000_000, -2_000_000} {
+		err = q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:     account.ID,
+			AsOf:          first.AddDate(0, 0, i),
+			Balance:       micros,
+			AnnualRateBps: 150,
+			AmountMicros:  micros,
+		})
+		require.NoError(t, err)
+	}
+
+	months, err := q.ListUnpostedInterest(ctx, first.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	var posted []ListUnpostedInterestRow
+	for _, m := range months {
+		if m.AccountID == account.ID {
+			posted = append(posted, m)
+		}
+	}
+	require.Len(t, posted, 2)
+	require.False(t, posted[0].Overdraft)
+	require.Equal(t, int64(3_000_000), posted[0].AmountMicros)
+	require.True(t, posted[1].Overdraft)
+	require.Equal(t, int64(-2_000_000), posted[1].AmountMicros)
+
+	for _, m := range posted {
+		purpose := SystemAccountInterestExpense
+		if m.Overdraft {
+			purpose = SystemAccountOverdraftInterest
+		}
+		system, err := q.GetSystemAccount(ctx, GetSystemAccountParams{Purpose: purpose, Currency: "USD"})
+		require.NoError(t, err)
+		before, err := q.GetAccount(ctx, system.AccountID)
+		require.NoError(t, err)
+
+		journal, err := postInterest(ctx, q, m)
+		require.NoError(t, err)
+		require.NotNil(t, journal)
+
+		after, err := q.GetAccount(ctx, system.AccountID)
+		require.NoError(t, err)
+		require.Equal(t, before.Balance-m.AmountMicros/1_000_000, after.Balance)
+	}
+
+	account, err = q.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), account.Balance)
+
+	months, err = q.ListUnpostedInterest(ctx, first.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	for _, m := range months {
+		require.NotEqual(t, account.ID, m.AccountID)
+	}
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryan-more/simple_bank/interest"
+)
+
+// ProductChecking is the product accounts are opened with unless they ask
+// for another.
+const ProductChecking = "checking"
+
+const (
+	SystemAccountInterestExpense = "interest_expense"
+	// SystemAccountOverdraftInterest receives the interest charged on
+	// overdrawn accounts.
+	SystemAccountOverdraftInterest = "overdraft_interest"
+)
+
+type AccrueInterestTxResult struct {
+	// Days is the number of days accrued.
+	Days     int   `json:"days"`
+	Accruals int64 `json:"accruals"`
+}
+
+// AccrueInterestTX accrues a day of interest for every savings account in
+// credit and every overdrawn account charged overdraft interest, on each day
+// since the last accrued one, up to the last day with balance
+// snapshots. The first run only accrues the last snapshotted day, interest
+// isn't paid for the time before the job ran.
+func (store *SQLStore) AccrueInterestTX(ctx context.Context) (AccrueInterestTxResult, error) {
+	var result AccrueInterestTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		result = AccrueInterestTxResult{}
+
+		until, err := q.GetLatestSnapshotTime(ctx)
+		if err != nil || !until.Valid {
+			return err
+		}
+
+		day := until.Time
+		latest, err := q.GetLatestAccrualDay(ctx)
+		if err != nil {
+			return err
+		}
+		if latest.Valid {
+			day = latest.Time.Add(24 * time.Hour)
+		}
+
+		for ; !day.After(until.Time); day = day.Add(24 * time.Hour) {
+			n, err := accrueDay(ctx, q, day)
+			if err != nil {
+				return fmt.Errorf("accrue interest for %s: %w", day.Add(-24*time.Hour).Format("2006-01-02"), err)
+			}
+			result.Days++
+			result.Accruals += int64(n)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// accrueDay accrues the interest of the day ending at asOf, on the balances
+// snapshotted then.
+func accrueDay(ctx context.Context, q *Queries, asOf time.Time) (int32, error) {
+	balances, err := q.ListInterestBalances(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+
+	// The day the interest is for started a day before the snapshot.
+	day := asOf.Add(-24 * time.Hour)
+	var accruals int32
+	for _, balance := range balances {
+		micros, err := interest.Daily(balance.Balance, balance.AnnualRateBps, interest.DayCount(balance.DayCount), day)
+		if err != nil {
+			return 0, fmt.Errorf("account %d: %w", balance.AccountID, err)
+		}
+		if micros == 0 {
+			continue
+		}
+
+		err = q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:     balance.AccountID,
+			AsOf:          asOf,
+			Balance:       balance.Balance,
+			AnnualRateBps: balance.AnnualRateBps,
+			AmountMicros:  micros,
+		})
+		if err != nil {
+			return 0, err
+		}
+		accruals++
+	}
+
+	return accruals, q.CreateInterestAccrualDay(ctx, CreateInterestAccrualDayParams{
+		AsOf:     asOf,
+		Accruals: accruals,
+	})
+}
+
+type PostInterestTxResult struct {
+	// Postings is the number of account months posted. Journals holds the
+	// journals of those whose interest didn't round to nothing.
+	Postings int       `json:"postings"`
+	Journals []Journal `json:"journals"`
+}
+
+// PostInterestTX posts the interest accrued over every month that has been
+// accrued to its end. The interest of a month is rounded by the account's
+// product. Interest earned is paid from the interest expense account of its
+// currency, overdraft interest is charged to the overdraft interest account.
+// An account both in credit and overdrawn over a month gets both postings.
+func (store *SQLStore) PostInterestTX(ctx context.Context) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		result = PostInterestTxResult{}
+
+		latest, err := q.GetLatestAccrualDay(ctx)
+		if err != nil || !latest.Valid {
+			return err
+		}
+		// Days belong to the month they start in, so the months before the
+		// one the latest accrued day ends in are complete.
+		before := time.Date(latest.Time.Year(), latest.Time.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+		months, err := q.ListUnpostedInterest(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		for _, month := range months {
+			journal, err := postInterest(ctx, q, month)
+			if err != nil {
+				return fmt.Errorf("post interest of account %d for %s: %w", month.AccountID, month.Month.UTC().Format("2006-01"), err)
+			}
+			result.Postings++
+			if journal != nil {
+				result.Journals = append(result.Journals, *journal)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// postInterest posts the interest an account earned, or the overdraft
+// interest it was charged, over a month and marks those accruals posted. The
+// journal is nil when the interest rounded to nothing.
+func postInterest(ctx context.Context, q *Queries, month ListUnpostedInterestRow) (*Journal, error) {
+	var journal *Journal
+	var journalID sql.NullInt64
+	start := month.Month.UTC()
+
+	amount, err := interest.Rounding(month.Rounding).Round(month.AmountMicros)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount != 0 {
+		purpose, description := SystemAccountInterestExpense, "interest for "
+		if month.Overdraft {
+			purpose, description = SystemAccountOverdraftInterest, "overdraft interest for "
+		}
+		counterpart, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  purpose,
+			Currency: month.Currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		posted, err := postJournal(ctx, q, PostJournalParams{
+			Description: description + start.Format("January 2006"),
+			Legs: []JournalLeg{
+				{AccountID: counterpart.AccountID, Amount: -amount, Kind: EntryKindInterest},
+				{AccountID: month.AccountID, Amount: amount, Kind: EntryKindInterest},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		journal = &posted.Journal
+		journalID = sql.NullInt64{Int64: posted.Journal.ID, Valid: true}
+	}
+
+	_, err = q.MarkInterestPosted(ctx, MarkInterestPostedParams{
+		JournalID: journalID,
+		AccountID: month.AccountID,
+		Month:     month.Month,
+		Overdraft: month.Overdraft,
+	})
+	return journal, err
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createCurrencyAccount(t *testing.T, currency string, balance int64) Account {
+	user := createRandomUser(t)
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  balance,
+		Currency: currency,
+		Name:     currency,
+		Product:  ProductChecking,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func TestPostJournalTxSplit(t *testing.T) {
+	store := NewStore(testDB)
+
+	payer := createCurrencyAccount(t, "USD", 100)
+	payee1 := createCurrencyAccount(t, "USD", 0)
+	payee2 := createCurrencyAccount(t, "USD", 0)
+
+	result, err := store.PostJournalTX(context.Background(), PostJournalParams{
+		Description:       "split bill",
+		ExternalReference: "split-1",
+		Legs: []JournalLeg{
+			{AccountID: payer.ID, Amount: -30},
+			{AccountID: payee1.ID, Amount: 20},
+			{AccountID: payee2.ID, Amount: 10, Description: "tip"},
+		},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, result.Journal.ID)
+	require.False(t, result.Journal.TransferID.Valid)
+	require.Equal(t, "split bill", result.Journal.Description)
+
+	require.Len(t, result.Entries, 3)
+	require.Equal(t, int64(-30), result.Entries[0].Amount)
+	require.Equal(t, "split bill", result.Entries[0].Description)
+	require.Equal(t, "tip", result.Entries[2].Description)
+	for _, entry := range result.Entries {
+		require.Equal(t, result.Journal.ID, entry.JournalID.Int64)
+		require.Equal(t, EntryKindTransfer, entry.Kind)
+	}
+
+	require.Equal(t, int64(70), result.Accounts[payer.ID].Balance)
+	require.Equal(t, int64(20), result.Accounts[payee1.ID].Balance)
+	require.Equal(t, int64(10), result.Accounts[payee2.ID].Balance)
+
+	entries, err := testQueries.ListJournalEntries(context.Background(), sql.NullInt64{Int64: result.Journal.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+}
+
+func TestPostJournalTxMultiCurrency(t *testing.T) {
+	store := NewStore(testDB)
+
+	usdFrom := createCurrencyAccount(t, "USD", 100)
+	usdTo := createCurrencyAccount(t, "USD", 0)
+	eurFrom := createCurrencyAccount(t, "EUR", 100)
+	eurTo := createCurrencyAccount(t, "EUR", 0)
+
+	result, err := store.PostJournalTX(context.Background(), PostJournalParams{
+		Description: "fx",
+		Legs: []JournalLeg{
+			{AccountID: usdFrom.ID, Amount: -50},
+			{AccountID: usdTo.ID, Amount: 50},
+			{AccountID: eurFrom.ID, Amount: -45},
+			{AccountID: eurTo.ID, Amount: 45},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 4)
+	require.Equal(t, int64(45), result.Accounts[eurTo.ID].Balance)
+}
+
+func TestPostJournalTxUnbalanced(t *testing.T) {
+	store := NewStore(testDB)
+
+	usd := createCurrencyAccount(t, "USD", 100)
+	eur := createCurrencyAccount(t, "EUR", 0)
+
+	_, err := store.PostJournalTX(context.Background(), PostJournalParams{
+		Legs: []JournalLeg{
+			{AccountID: usd.ID, Amount: -50},
+			{AccountID: eur.ID, Amount: 50},
+		},
+	})
+	var unbalanced *UnbalancedJournalError
+	require.ErrorAs(t, err, &unbalanced)
+	require.Equal(t, "EUR", unbalanced.Currency)
+	require.Equal(t, int64(50), unbalanced.Total)
+
+	account, err := testQueries.GetAccount(context.Background(), usd.ID)
+	require.NoError(t, err)
+	require.Equal(t, usd.Balance, account.Balance)
+}
+
+func TestPostJournalTxEmpty(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createCurrencyAccount(t, "USD", 100)
+
+	_, err := store.PostJournalTX(context.Background(), PostJournalParams{
+		Legs: []JournalLeg{{AccountID: account.ID, Amount: 10}},
+	})
+	require.ErrorIs(t, err, ErrEmptyJournal)
+
+	_, err = store.PostJournalTX(context.Background(), PostJournalParams{
+		Legs: []JournalLeg{
+			{AccountID: account.ID, Amount: 0},
+			{AccountID: account.ID, Amount: 0},
+		},
+	})
+	require.ErrorIs(t, err, ErrEmptyJournal)
+}
+
+func TestTransferTxJournal(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Description:   "rent",
+	})
+	require.NoError(t, err)
+
+	require.NotZero(t, result.Journal.ID)
+	require.Equal(t, result.Transfer.ID, result.Journal.TransferID.Int64)
+	require.Equal(t, "rent", result.Journal.Description)
+	require.Equal(t, result.Journal.ID, result.FromEntry.JournalID.Int64)
+	require.Equal(t, result.Journal.ID, result.ToEntry.JournalID.Int64)
+
+	entries, err := testQueries.ListJournalEntries(context.Background(), sql.NullInt64{Int64: result.Journal.ID, Valid: true})
+	require.NoError(t, err)
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Amount
+	}
+	require.Zero(t, total)
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aryan-more/simple_bank/money"
+)
+
+// ErrEmptyJournal is returned for journals with fewer than two legs or a leg
+// that doesn't move any money.
+var ErrEmptyJournal = errors.New("journal needs at least two non-zero legs")
+
+// UnbalancedJournalError is returned when the legs of a journal don't sum to
+// zero in one of its currencies.
+type UnbalancedJournalError struct {
+	Currency string `json:"currency"`
+	Total    int64  `json:"total"`
+}
+
+func (e *UnbalancedJournalError) Error() string {
+	return fmt.Sprintf("journal legs in %s sum to %d instead of zero", e.Currency, e.Total)
+}
+
+// JournalLeg is a single posting of a journal. Kind defaults to
+// EntryKindTransfer and Description to the journal's description.
+type JournalLeg struct {
+	AccountID   int64  `json:"account_id"`
+	Amount      int64  `json:"amount"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+}
+
+type PostJournalParams struct {
+	Description       string        `json:"description"`
+	ExternalReference string        `json:"external_reference"`
+	TransferID        sql.NullInt64 `json:"transfer_id"`
+	Legs              []JournalLeg  `json:"legs"`
+}
+
+type PostJournalResult struct {
+	Journal Journal `json:"journal"`
+	// Entries are in the order of the legs they were posted for.
+	Entries []Entry `json:"entries"`
+	// Accounts holds every account touched by the journal after its legs
+	// were applied.
+	Accounts map[int64]Account `json:"accounts"`
+}
+
+// PostJournalTX posts a journal of any number of legs atomically.
+func (store *SQLStore) PostJournalTX(ctx context.Context, arg PostJournalParams) (PostJournalResult, error) {
+	var result PostJournalResult
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result, err = postJournal(ctx, q, arg)
+		return err
+	})
+	return result, err
+}
+
+// postJournal applies the legs of a journal inside an existing transaction.
+// The legs of every currency must sum to zero. Balances are updated in
+// ascending account order so concurrent journals can't deadlock.
+func postJournal(ctx context.Context, q *Queries, arg PostJournalParams) (PostJournalResult, error) {
+	result := PostJournalResult{Accounts: make(map[int64]Account)}
+
+	if len(arg.Legs) < 2 {
+		return result, ErrEmptyJournal
+	}
+
+	// The legs of an account share its currency, which isn't known before
+	// the account is read, so the deltas are summed without one.
+	deltas := make(map[int64]money.Money)
+	for _, leg := range arg.Legs {
+		if leg.Amount == 0 {
+			return result, ErrEmptyJournal
+		}
+		delta, err := deltas[leg.AccountID].Add(money.New(leg.Amount, money.Currency{}))
+		if err != nil {
+			return result, err
+		}
+		deltas[leg.AccountID] = delta
+	}
+
+	accountIDs := make([]int64, 0, len(deltas))
+	for id := range deltas {
+		accountIDs = append(accountIDs, id)
+	}
+	sort.Slice(accountIDs, func(i, j int) bool { return accountIDs[i] < accountIDs[j] })
+
+	for _, id := range accountIDs {
+		account, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			Amount: deltas[id].Amount(),
+			ID:     id,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Accounts[id] = account
+	}
+
+	if err := checkBalanced(arg.Legs, result.Accounts); err != nil {
+		return result, err
+	}
+
+	var err error
+	result.Journal, err = q.CreateJournal(ctx, CreateJournalParams{
+		TransferID:        arg.TransferID,
+		Description:       arg.Description,
+		ExternalReference: arg.ExternalReference,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	journalID := sql.NullInt64{Int64: result.Journal.ID, Valid: true}
+	result.Entries = make([]Entry, len(arg.Legs))
+	for i, leg := range arg.Legs {
+		kind := leg.Kind
+		if kind == "" {
+			kind = EntryKindTransfer
+		}
+		description := leg.Description
+		if description == "" {
+			description = arg.Description
+		}
+
+		result.Entries[i], err = postEntry(ctx, q, CreateEntryParams{
+			AccountID:         leg.AccountID,
+			Amount:            leg.Amount,
+			TransferID:        arg.TransferID,
+			Description:       description,
+			ExternalReference: arg.ExternalReference,
+			Kind:              kind,
+			JournalID:         journalID,
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// checkBalanced reports the first currency, in alphabetical order, whose legs
+// don't sum to zero.
+func checkBalanced(legs []JournalLeg, accounts map[int64]Account) error {
+	totals := make(map[string]money.Money)
+	for _, leg := range legs {
+		account := accounts[leg.AccountID]
+		total, ok := totals[account.Currency]
+		if !ok {
+			total = account.inCurrency(0)
+		}
+		total, err := total.Add(account.inCurrency(leg.Amount))
+		if err != nil {
+			return err
+		}
+		totals[account.Currency] = total
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		if !totals[currency].IsZero() {
+			return &UnbalancedJournalError{Currency: currency, Total: totals[currency].Amount()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertAccountLimits(t *testing.T) {
+	account := createRandomAccount(t)
+
+	arg := UpsertAccountLimitsParams{
+		AccountID: account.ID,
+		Daily:     sql.NullInt64{Int64: 500, Valid: true},
+	}
+
+	limits1, err := testQueries.UpsertAccountLimits(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.AccountID, limits1.AccountID)
+	require.Equal(t, arg.Daily, limits1.Daily)
+	require.False(t, limits1.PerTransaction.Valid)
+
+	arg.Daily = sql.NullInt64{Int64: 300, Valid: true}
+	limits2, err := testQueries.UpsertAccountLimits(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Daily, limits2.Daily)
+
+	limits3, err := testQueries.GetAccountLimits(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, limits2.Daily, limits3.Daily)
+}
+
+func TestGetEffectiveLimits(t *testing.T) {
+	account := createRandomAccount(t)
+
+	defaults, err := testQueries.GetCurrencyLimits(context.Background(), account.Currency)
+	require.NoError(t, err)
+
+	_, err = testQueries.UpsertAccountLimits(context.Background(), UpsertAccountLimitsParams{
+		AccountID: account.ID,
+		Daily:     sql.NullInt64{Int64: 500, Valid: true},
+	})
+	require.NoError(t, err)
+
+	limits, err := testQueries.GetEffectiveLimits(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), limits.Daily.Int64)
+	require.Equal(t, defaults.PerTransaction, limits.PerTransaction)
+	require.Equal(t, defaults.Monthly, limits.Monthly)
+}
+
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	_, err := store.UpsertAccountLimits(context.Background(), UpsertAccountLimitsParams{
+		AccountID:      account1.ID,
+		PerTransaction: sql.NullInt64{Int64: 100, Valid: true},
+		Daily:          sql.NullInt64{Int64: 150, Valid: true},
+	})
+	require.NoError(t, err)
+
+	transfer := func(amount int64) error {
+		_, err := store.TransferTX(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	var limitErr *LimitExceededError
+
+	err = transfer(101)
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitPerTransaction, limitErr.Limit)
+
+	require.NoError(t, transfer(100))
+
+	err = transfer(51)
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitDaily, limitErr.Limit)
+	require.Equal(t, int64(151), limitErr.Total)
+
+	require.NoError(t, transfer(50))
+
+	totals, err := store.GetOutgoingTotals(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(150), totals.Daily)
+	require.Equal(t, int64(150), totals.Monthly)
+}
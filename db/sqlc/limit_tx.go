@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryan-more/simple_bank/money"
+)
+
+const (
+	LimitPerTransaction = "per_transaction"
+	LimitDaily          = "daily"
+	LimitMonthly        = "monthly"
+)
+
+// defaultCurrencyLimits are the limits a currency starts with when it's
+// added to the catalog, in major units so they mean the same in every
+// currency. They match the limits the first currencies were seeded with.
+var defaultCurrencyLimits = struct {
+	perTransaction, daily, monthly string
+}{"10000", "25000", "100000"}
+
+// createDefaultCurrencyLimits gives a new currency the default limits, so
+// transfers in it aren't unlimited until someone sets them.
+func createDefaultCurrencyLimits(ctx context.Context, q *Queries, code string) error {
+	currency, err := money.Lookup(code)
+	if err != nil {
+		return err
+	}
+
+	limit := func(value string) (sql.NullInt64, error) {
+		amount, err := money.Parse(value, currency)
+		if err != nil {
+			return sql.NullInt64{}, err
+		}
+		return sql.NullInt64{Int64: amount.Amount(), Valid: true}, nil
+	}
+
+	arg := CreateCurrencyLimitsParams{Currency: code}
+	if arg.PerTransaction, err = limit(defaultCurrencyLimits.perTransaction); err != nil {
+		return err
+	}
+	if arg.Daily, err = limit(defaultCurrencyLimits.daily); err != nil {
+		return err
+	}
+	if arg.Monthly, err = limit(defaultCurrencyLimits.monthly); err != nil {
+		return err
+	}
+
+	_, err = q.CreateCurrencyLimits(ctx, arg)
+	return err
+}
+
+// LimitExceededError is returned when a debit breaches one of the sender's
+// transfer limits.
+type LimitExceededError struct {
+	Limit string `json:"limit"`
+	Max   int64  `json:"max"`
+	// Total is the amount the debit would have brought the limit to.
+	Total int64 `json:"total"`
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s transfer limit of %d exceeded: total would be %d", e.Limit, e.Max, e.Total)
+}
+
+// checkLimits enforces the effective limits of an account after a debit of
+// amount has been posted in the current transaction. The daily limit is a
+// rolling 24 hour window, the monthly limit covers the calendar month.
+func checkLimits(ctx context.Context, q *Queries, accountID int64, amount int64) error {
+	limits, err := q.GetEffectiveLimits(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if limits.PerTransaction.Valid && amount > limits.PerTransaction.Int64 {
+		return &LimitExceededError{
+			Limit: LimitPerTransaction,
+			Max:   limits.PerTransaction.Int64,
+			Total: amount,
+		}
+	}
+
+	if !limits.Daily.Valid && !limits.Monthly.Valid {
+		return nil
+	}
+
+	totals, err := q.GetOutgoingTotals(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if limits.Daily.Valid && totals.Daily > limits.Daily.Int64 {
+		return &LimitExceededError{
+			Limit: LimitDaily,
+			Max:   limits.Daily.Int64,
+			Total: totals.Daily,
+		}
+	}
+
+	if limits.Monthly.Valid && totals.Monthly > limits.Monthly.Int64 {
+		return &LimitExceededError{
+			Limit: LimitMonthly,
+			Max:   limits.Monthly.Int64,
+			Total: totals.Monthly,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Roles of account members, each allowed everything the ones after it are.
+const (
+	// MemberRoleOwner can also add and remove members.
+	MemberRoleOwner = "owner"
+	// MemberRoleSigner can also move money out of the account.
+	MemberRoleSigner = "signer"
+	// MemberRoleViewer can see the account and its history.
+	MemberRoleViewer = "viewer"
+)
+
+var memberRoleRanks = map[string]int{
+	MemberRoleViewer: 1,
+	MemberRoleSigner: 2,
+	MemberRoleOwner:  3,
+}
+
+// Allows reports whether the member's role includes role. Pending
+// invitations allow nothing until they are accepted.
+func (member AccountMember) Allows(role string) bool {
+	rank := memberRoleRanks[member.Role]
+	return member.AcceptedAt.Valid && rank > 0 && rank >= memberRoleRanks[role]
+}
+
+// addPrimaryOwner adds the owner of a new account as its first member, who
+// has nothing to accept.
+func addPrimaryOwner(ctx context.Context, q *Queries, account Account) error {
+	_, err := q.CreateAccountMember(ctx, CreateAccountMemberParams{
+		AccountID:  account.ID,
+		Username:   account.Owner,
+		Role:       MemberRoleOwner,
+		AddedBy:    account.Owner,
+		AcceptedAt: sql.NullTime{Time: account.CreatedAt, Valid: true},
+	})
+	return err
+}
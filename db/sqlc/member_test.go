@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountMembers(t *testing.T) {
+	store := NewStore(testDB)
+
+	owner := createRandomUser(t)
+	account, err := store.CreateAccountTX(context.Background(), CreateAccountParams{
+		Owner:    owner.Username,
+		Currency: "USD",
+		Name:     "USD",
+		Product:  ProductChecking,
+	})
+	require.NoError(t, err)
+
+	member, err := store.GetAccountMember(context.Background(), GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  owner.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, MemberRoleOwner, member.Role)
+
+	viewer := createRandomUser(t)
+	member, err = store.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  viewer.Username,
+		Role:      MemberRoleViewer,
+		AddedBy:   owner.Username,
+	})
+	require.NoError(t, err)
+	require.False(t, member.AcceptedAt.Valid)
+	require.False(t, member.Allows(MemberRoleViewer))
+
+	members, err := store.ListAccountMembers(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	accounts, err := store.ListMemberAccounts(context.Background(), ListMemberAccountsParams{
+		Username: viewer.Username,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+
+	invites, err := store.ListMemberInvites(context.Background(), viewer.Username)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	require.Equal(t, account.ID, invites[0].AccountID)
+
+	member, err = store.AcceptAccountMember(context.Background(), AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  viewer.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, member.AcceptedAt.Valid)
+	require.True(t, member.Allows(MemberRoleViewer))
+	require.False(t, member.Allows(MemberRoleSigner))
+
+	_, err = store.AcceptAccountMember(context.Background(), AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  viewer.Username,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	accounts, err = store.ListMemberAccounts(context.Background(), ListMemberAccountsParams{
+		Username: viewer.Username,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].ID)
+
+	removed, err := store.DeleteAccountMember(context.Background(), DeleteAccountMemberParams{
+		AccountID: account.ID,
+		Username:  viewer.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), removed)
+
+	accounts, err = store.ListMemberAccounts(context.Background(), ListMemberAccountsParams{
+		Username: viewer.Username,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
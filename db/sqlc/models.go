@@ -5,25 +5,211 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
+	ID int64 `json:"id"`
+	// primary owner, always an owner member who can't be removed
 	Owner     string    `json:"owner"`
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// pricing tier used to pick fee rules
+	Tier    string `json:"tier"`
+	Product string `json:"product"`
+	// how far below zero debits may take the available balance
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// annual interest charged on overdrawn balances in basis points, 0 charges none
+	OverdraftRateBps int32 `json:"overdraft_rate_bps"`
+	// chosen by the owner, defaults to the currency code
+	Name string `json:"name"`
+}
+
+type AccountLimit struct {
+	AccountID int64 `json:"account_id"`
+	// null falls back to the currency limit
+	PerTransaction sql.NullInt64 `json:"per_transaction"`
+	Daily          sql.NullInt64 `json:"daily"`
+	Monthly        sql.NullInt64 `json:"monthly"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+type AccountMember struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	// owner, signer or viewer: owners manage members, signers move money, viewers only read
+	Role      string    `json:"role"`
+	AddedBy   string    `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+	// null while the invitation is pending, the role only applies once accepted
+	AcceptedAt sql.NullTime `json:"accepted_at"`
+}
+
+type BalanceSnapshot struct {
+	AccountID int64 `json:"account_id"`
+	// midnight UTC, the balance covers every entry created before it
+	AsOf      time.Time `json:"as_of"`
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Currency struct {
+	Code string `json:"code"`
+	// new accounts can only be opened in enabled currencies, accounts in a disabled one keep working
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// transfers above it in minor units wait for a second person, 0 disables dual approval
+	ApprovalThreshold int64 `json:"approval_threshold"`
+	// most a new payee can receive in minor units until its cooling-off period ends
+	PayeeCoolingOffAmount int64 `json:"payee_cooling_off_amount"`
+}
+
+type CurrencyLimit struct {
+	Currency string `json:"currency"`
+	// null means unlimited
+	PerTransaction sql.NullInt64 `json:"per_transaction"`
+	Daily          sql.NullInt64 `json:"daily"`
+	Monthly        sql.NullInt64 `json:"monthly"`
 }
 
 type Entry struct {
 	ID        int64 `json:"id"`
 	AccountID int64 `json:"account_id"`
 	// can be negative or positive
-	Amount    int64     `json:"amount"`
+	Amount            int64         `json:"amount"`
+	CreatedAt         time.Time     `json:"created_at"`
+	TransferID        sql.NullInt64 `json:"transfer_id"`
+	Description       string        `json:"description"`
+	ExternalReference string        `json:"external_reference"`
+	// transfer, fee, import or interest
+	Kind string `json:"kind"`
+	// the entries of a journal sum to zero per currency
+	JournalID sql.NullInt64 `json:"journal_id"`
+}
+
+type FeeRule struct {
+	ID       int64  `json:"id"`
+	Currency string `json:"currency"`
+	// null applies to every tier without its own rule
+	Tier sql.NullString `json:"tier"`
+	Flat int64          `json:"flat"`
+	// percentage of the amount in basis points
+	PercentBps int32 `json:"percent_bps"`
+	// null means no minimum
+	MinFee sql.NullInt64 `json:"min_fee"`
+	// null means no maximum
+	MaxFee    sql.NullInt64 `json:"max_fee"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Hold struct {
+	ID          int64 `json:"id"`
+	AccountID   int64 `json:"account_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	// must be positive
+	Amount         int64 `json:"amount"`
+	CapturedAmount int64 `json:"captured_amount"`
+	// active, captured or released
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type InterestAccrual struct {
+	AccountID int64 `json:"account_id"`
+	// midnight UTC ending the day the interest accrued over
+	AsOf time.Time `json:"as_of"`
+	// end-of-day balance, taken from the balance snapshot at as_of
+	Balance       int64 `json:"balance"`
+	AnnualRateBps int32 `json:"annual_rate_bps"`
+	// unrounded interest in millionths of a minor unit, negative when charged on an overdraft
+	AmountMicros int64 `json:"amount_micros"`
+	// journal that posted the interest of the month, null when it rounded to nothing
+	JournalID sql.NullInt64 `json:"journal_id"`
+	// null until the month is posted
+	PostedAt  sql.NullTime `json:"posted_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type InterestAccrualDay struct {
+	AsOf      time.Time `json:"as_of"`
+	Accruals  int32     `json:"accruals"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Journal struct {
+	ID int64 `json:"id"`
+	// transfer the journal settles, null for other postings
+	TransferID        sql.NullInt64 `json:"transfer_id"`
+	Description       string        `json:"description"`
+	ExternalReference string        `json:"external_reference"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
+
+type LegacyAccount struct {
+	LegacyID   string    `json:"legacy_id"`
+	AccountID  int64     `json:"account_id"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+type Outbox struct {
+	ID            int64  `json:"id"`
+	AggregateType string `json:"aggregate_type"`
+	// events of one aggregate are published in id order
+	AggregateID string          `json:"aggregate_id"`
+	EventType   string          `json:"event_type"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	// null until a sink accepted the event
+	PublishedAt sql.NullTime `json:"published_at"`
+	Attempts    int32        `json:"attempts"`
+	LastError   string       `json:"last_error"`
+}
+
+type Payee struct {
+	ID        int64  `json:"id"`
+	Owner     string `json:"owner"`
+	Nickname  string `json:"nickname"`
+	AccountID int64  `json:"account_id"`
+	Currency  string `json:"currency"`
+	// start of the cooling-off period for large transfers
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Product struct {
+	Code string `json:"code"`
+	// checking or savings, only savings accounts earn interest
+	Type string `json:"type"`
+	// annual interest rate in basis points
+	AnnualRateBps int32 `json:"annual_rate_bps"`
+	// act/365, act/360, act/act or 30/360
+	DayCount string `json:"day_count"`
+	// half_up, half_even or down, applied when accrued interest is posted
+	Rounding  string    `json:"rounding"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type ReconciliationRun struct {
+	ID              int64     `json:"id"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DriftedAccounts int32     `json:"drifted_accounts"`
+	TransferIssues  int32     `json:"transfer_issues"`
+	// every drifted account and broken transfer found by the run
+	Report json.RawMessage `json:"report"`
+}
+
+type SystemAccount struct {
+	Purpose   string `json:"purpose"`
+	Currency  string `json:"currency"`
+	AccountID int64  `json:"account_id"`
+}
+
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
@@ -31,6 +217,43 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// free text memo shown to both parties
+	Description string `json:"description"`
+	// sender supplied reference, e.g. an invoice number
+	ExternalReference string `json:"external_reference"`
+	// pending, processing, completed, failed or reversed
+	Status string `json:"status"`
+	// set when the transfer failed
+	FailureReason string    `json:"failure_reason"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type TransferApproval struct {
+	ID                int64  `json:"id"`
+	FromAccountID     int64  `json:"from_account_id"`
+	ToAccountID       int64  `json:"to_account_id"`
+	Amount            int64  `json:"amount"`
+	Description       string `json:"description"`
+	ExternalReference string `json:"external_reference"`
+	RequestedBy       string `json:"requested_by"`
+	// pending, approved, rejected or expired
+	Status    string         `json:"status"`
+	DecidedBy sql.NullString `json:"decided_by"`
+	// set once an approved transfer is executed
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	DecidedAt  sql.NullTime  `json:"decided_at"`
+}
+
+type TransferStatusHistory struct {
+	ID         int64 `json:"id"`
+	TransferID int64 `json:"transfer_id"`
+	// null for the status a transfer was created with
+	FromStatus sql.NullString `json:"from_status"`
+	ToStatus   string         `json:"to_status"`
+	Reason     string         `json:"reason"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
 type User struct {
@@ -40,4 +263,37 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	// depositor or banker
+	Role string `json:"role"`
+	// opted in to more than one account per currency
+	MultipleAccounts bool `json:"multiple_accounts"`
+	// most accounts the user may own
+	MaxAccounts int32 `json:"max_accounts"`
+}
+
+type WebhookDelivery struct {
+	ID             int64 `json:"id"`
+	SubscriptionID int64 `json:"subscription_id"`
+	// outbox event delivered, null for test events
+	EventID   sql.NullInt64   `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	// pending, succeeded or failed
+	Status        string    `json:"status"`
+	Attempts      int32     `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	// HTTP status of the last attempt, null if there was no response
+	ResponseStatus sql.NullInt32 `json:"response_status"`
+	LastError      string        `json:"last_error"`
+	CreatedAt      time.Time     `json:"created_at"`
+	DeliveredAt    sql.NullTime  `json:"delivered_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	Owner      string    `json:"owner"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret"`
+	CreatedAt  time.Time `json:"created_at"`
 }
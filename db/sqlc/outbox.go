@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Aggregate types of outbox events. Events of the same aggregate are
+// published in the order they were written.
+const (
+	AggregateAccount  = "account"
+	AggregateTransfer = "transfer"
+	AggregateUser     = "user"
+)
+
+// Outbox event types. Transfers emit "transfer." followed by every status
+// they enter, e.g. "transfer.completed".
+const (
+	EventAccountCreated = "account.created"
+	EventEntryCreated   = "entry.created"
+	EventUserCreated    = "user.created"
+)
+
+// TransferEvent returns the event type emitted when a transfer enters status.
+func TransferEvent(status string) string {
+	return AggregateTransfer + "." + status
+}
+
+// UserEventPayload is published for user events. It leaves out the password
+// hash.
+type UserEventPayload struct {
+	Username  string    `json:"username"`
+	FullName  string    `json:"full_name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// addOutboxEvent records an event in the outbox. Called inside a store
+// transaction, the event is published if and only if the transaction commits.
+func addOutboxEvent(ctx context.Context, q *Queries, aggregateType string, aggregateID string, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	})
+	return err
+}
+
+func addTransferEvent(ctx context.Context, q *Queries, transfer Transfer) error {
+	return addOutboxEvent(ctx, q, AggregateTransfer, strconv.FormatInt(transfer.ID, 10), TransferEvent(transfer.Status), transfer)
+}
+
+// postEntry creates an entry and publishes it as an entry.created event of
+// the entry's account.
+func postEntry(ctx context.Context, q *Queries, arg CreateEntryParams) (Entry, error) {
+	entry, err := q.CreateEntry(ctx, arg)
+	if err != nil {
+		return entry, err
+	}
+
+	err = addOutboxEvent(ctx, q, AggregateAccount, strconv.FormatInt(entry.AccountID, 10), EventEntryCreated, entry)
+	return entry, err
+}
+
+// CreateAccountTX creates an account, with its owner as the first member, and
+// its account.created event. The owner's account cap and, unless they opted
+// in to more, one account per currency are enforced.
+func (store *SQLStore) CreateAccountTX(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var account Account
+	err := store.execTX(ctx, func(q *Queries) error {
+		if err := checkNewAccount(ctx, q, arg); err != nil {
+			return err
+		}
+
+		var err error
+		account, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if err := addPrimaryOwner(ctx, q, account); err != nil {
+			return err
+		}
+
+		return addOutboxEvent(ctx, q, AggregateAccount, strconv.FormatInt(account.ID, 10), EventAccountCreated, account)
+	})
+	return account, err
+}
+
+// CreateUserTX creates a user and its user.created event.
+func (store *SQLStore) CreateUserTX(ctx context.Context, arg CreateUserParams) (User, error) {
+	var user User
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return addOutboxEvent(ctx, q, AggregateUser, user.Username, EventUserCreated, UserEventPayload{
+			Username:  user.Username,
+			FullName:  user.FullName,
+			Email:     user.Email,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+		})
+	})
+	return user, err
+}
+
+type PublishOutboxTxParams struct {
+	Limit int32
+	// Publish hands one event to a sink. Events it fails are retried by a
+	// later call, and so are events it published if the transaction doesn't
+	// commit, so sinks must cope with duplicates.
+	Publish func(ctx context.Context, event Outbox) error
+}
+
+type PublishOutboxTxResult struct {
+	// Locked is false when another relay is publishing and nothing was done.
+	Locked    bool `json:"locked"`
+	Published int  `json:"published"`
+	Failed    int  `json:"failed"`
+	// Skipped events were held back because an earlier event of the same
+	// aggregate failed.
+	Skipped int `json:"skipped"`
+}
+
+// PublishOutboxTX publishes up to Limit unpublished events in the order they
+// were written. Only one relay publishes at a time, and once an event fails
+// the later events of its aggregate wait for it, so every aggregate's events
+// reach the sink in order.
+func (store *SQLStore) PublishOutboxTX(ctx context.Context, arg PublishOutboxTxParams) (PublishOutboxTxResult, error) {
+	var result PublishOutboxTxResult
+	err := store.execTX(ctx, func(q *Queries) error {
+		result = PublishOutboxTxResult{}
+
+		var err error
+		result.Locked, err = q.TryLockOutbox(ctx)
+		if err != nil || !result.Locked {
+			return err
+		}
+
+		events, err := q.ListUnpublishedOutboxEvents(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+
+		blocked := make(map[string]bool)
+		for _, event := range events {
+			aggregate := event.AggregateType + "/" + event.AggregateID
+			if blocked[aggregate] {
+				result.Skipped++
+				continue
+			}
+
+			if err := arg.Publish(ctx, event); err != nil {
+				blocked[aggregate] = true
+				result.Failed++
+
+				err = q.MarkOutboxEventFailed(ctx, MarkOutboxEventFailedParams{
+					ID:        event.ID,
+					LastError: err.Error(),
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := q.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+				return err
+			}
+			result.Published++
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func listAggregateEvents(t *testing.T, aggregateType string, aggregateID string) []Outbox {
+	events, err := testQueries.ListAggregateOutboxEvents(context.Background(), ListAggregateOutboxEventsParams{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+	})
+	require.NoError(t, err)
+	return events
+}
+
+func TestCreateAccountTX(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	account, err := store.CreateAccountTX(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.RandomCurrency(),
+		Name:     "main",
+		Product:  ProductChecking,
+	})
+	require.NoError(t, err)
+
+	events := listAggregateEvents(t, AggregateAccount, strconv.FormatInt(account.ID, 10))
+	require.Len(t, events, 1)
+	require.Equal(t, EventAccountCreated, events[0].EventType)
+	require.False(t, events[0].PublishedAt.Valid)
+
+	var payload Account
+	require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	require.Equal(t, account.ID, payload.ID)
+	require.Equal(t, account.Owner, payload.Owner)
+}
+
+func TestCreateUserTX(t *testing.T) {
+	store := NewStore(testDB)
+	username := util.RandomOwner()
+
+	user, err := store.CreateUserTX(context.Background(), CreateUserParams{
+		Username:       username,
+		HashedPassword: "secret hash",
+		FullName:       username,
+		Email:          util.RandomEmail(username),
+	})
+	require.NoError(t, err)
+
+	events := listAggregateEvents(t, AggregateUser, user.Username)
+	require.Len(t, events, 1)
+	require.Equal(t, EventUserCreated, events[0].EventType)
+	require.NotContains(t, string(events[0].Payload), "secret hash")
+}
+
+func TestTransferTXEvents(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	events := listAggregateEvents(t, AggregateTransfer, strconv.FormatInt(result.Transfer.ID, 10))
+	require.Len(t, events, 2)
+	require.Equal(t, TransferEvent(TransferStatusProcessing), events[0].EventType)
+	require.Equal(t, TransferEvent(TransferStatusCompleted), events[1].EventType)
+
+	var payload Transfer
+	require.NoError(t, json.Unmarshal(events[1].Payload, &payload))
+	require.Equal(t, TransferStatusCompleted, payload.Status)
+}
+
+func TestTransferTXFailedWritesOnlyStatusEvents(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccountWithBalance(t, 0)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// The refused transfer is recorded as failed, its entries are not.
+	events := listAggregateEvents(t, AggregateTransfer, strconv.FormatInt(result.Transfer.ID, 10))
+	require.Len(t, events, 2)
+	require.Equal(t, TransferEvent(TransferStatusProcessing), events[0].EventType)
+	require.Equal(t, TransferEvent(TransferStatusFailed), events[1].EventType)
+
+	for _, event := range listAggregateEvents(t, AggregateAccount, strconv.FormatInt(account1.ID, 10)) {
+		require.NotEqual(t, EventEntryCreated, event.EventType)
+	}
+}
+
+// publishUntil keeps publishing batches until done reports true.
+func publishUntil(t *testing.T, store *SQLStore, publish func(context.Context, Outbox) error, done func() bool) {
+	for i := 0; i < 100 && !done(); i++ {
+		_, err := store.PublishOutboxTX(context.Background(), PublishOutboxTxParams{
+			Limit:   1000,
+			Publish: publish,
+		})
+		require.NoError(t, err)
+	}
+	require.True(t, done())
+}
+
+func TestPublishOutboxTX(t *testing.T) {
+	store := NewStore(testDB)
+	aggregateID := util.RandomString(12)
+
+	for i := 0; i < 3; i++ {
+		err := addOutboxEvent(context.Background(), testQueries, AggregateAccount, aggregateID, "account.test", i)
+		require.NoError(t, err)
+	}
+
+	var published []string
+	publish := func(ctx context.Context, event Outbox) error {
+		if event.AggregateID == aggregateID && event.AggregateType == AggregateAccount {
+			published = append(published, string(event.Payload))
+		}
+		return nil
+	}
+
+	publishUntil(t, store, publish, func() bool {
+		for _, event := range listAggregateEvents(t, AggregateAccount, aggregateID) {
+			if !event.PublishedAt.Valid {
+				return false
+			}
+		}
+		return true
+	})
+
+	require.Equal(t, []string{"0", "1", "2"}, published)
+}
+
+func TestPublishOutboxTXKeepsAggregateOrder(t *testing.T) {
+	store := NewStore(testDB)
+	aggregateID := util.RandomString(12)
+
+	for i := 0; i < 2; i++ {
+		err := addOutboxEvent(context.Background(), testQueries, AggregateAccount, aggregateID, "account.test", i)
+		require.NoError(t, err)
+	}
+	events := listAggregateEvents(t, AggregateAccount, aggregateID)
+	require.Len(t, events, 2)
+
+	publish := func(ctx context.Context, event Outbox) error {
+		if event.ID == events[0].ID {
+			return errors.New("sink unavailable")
+		}
+		return nil
+	}
+
+	publishUntil(t, store, publish, func() bool {
+		first, err := testQueries.GetOutboxEvent(context.Background(), events[0].ID)
+		require.NoError(t, err)
+		return first.Attempts > 0
+	})
+
+	first, err := testQueries.GetOutboxEvent(context.Background(), events[0].ID)
+	require.NoError(t, err)
+	require.False(t, first.PublishedAt.Valid)
+	require.Equal(t, "sink unavailable", first.LastError)
+
+	second, err := testQueries.GetOutboxEvent(context.Background(), events[1].ID)
+	require.NoError(t, err)
+	require.False(t, second.PublishedAt.Valid)
+	require.Zero(t, second.Attempts)
+}
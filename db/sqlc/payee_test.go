@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPayee(t *testing.T, owner User, account Account) Payee {
+	arg := CreatePayeeParams{
+		Owner:     owner.Username,
+		Nickname:  util.RandomOwner(),
+		AccountID: account.ID,
+		Currency:  account.Currency,
+	}
+
+	payee, err := testQueries.CreatePayee(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, payee)
+
+	require.Equal(t, arg.Owner, payee.Owner)
+	require.Equal(t, arg.Nickname, payee.Nickname)
+	require.Equal(t, arg.AccountID, payee.AccountID)
+	require.Equal(t, arg.Currency, payee.Currency)
+	require.NotZero(t, payee.ID)
+	require.NotZero(t, payee.CreatedAt)
+
+	return payee
+}
+
+func TestCreatePayee(t *testing.T) {
+	createRandomPayee(t, createRandomUser(t), createRandomAccount(t))
+}
+
+func TestCreatePayeeDuplicateAccount(t *testing.T) {
+	owner := createRandomUser(t)
+	account := createRandomAccount(t)
+	createRandomPayee(t, owner, account)
+
+	_, err := testQueries.CreatePayee(context.Background(), CreatePayeeParams{
+		Owner:     owner.Username,
+		Nickname:  util.RandomOwner(),
+		AccountID: account.ID,
+		Currency:  account.Currency,
+	})
+	require.Error(t, err)
+}
+
+func TestListPayees(t *testing.T) {
+	owner := createRandomUser(t)
+	for i := 0; i < 5; i++ {
+		createRandomPayee(t, owner, createRandomAccount(t))
+	}
+
+	payees, err := testQueries.ListPayees(context.Background(), ListPayeesParams{
+		Owner:  owner.Username,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, payees, 5)
+
+	for i, payee := range payees {
+		require.Equal(t, owner.Username, payee.Owner)
+		if i > 0 {
+			require.Less(t, payees[i-1].Nickname, payee.Nickname)
+		}
+	}
+}
+
+func TestUpdateAndDeletePayee(t *testing.T) {
+	payee1 := createRandomPayee(t, createRandomUser(t), createRandomAccount(t))
+
+	nickname := util.RandomOwner()
+	payee2, err := testQueries.UpdatePayeeNickname(context.Background(), UpdatePayeeNicknameParams{
+		ID:       payee1.ID,
+		Nickname: nickname,
+	})
+	require.NoError(t, err)
+	require.Equal(t, nickname, payee2.Nickname)
+	require.Equal(t, payee1.AccountID, payee2.AccountID)
+
+	err = testQueries.DeletePayee(context.Background(), payee1.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetPayee(context.Background(), payee1.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -6,24 +6,149 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AcceptAccountMember(ctx context.Context, arg AcceptAccountMemberParams) (AccountMember, error)
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
+	CountOwnerAccounts(ctx context.Context, arg CountOwnerAccountsParams) (CountOwnerAccountsRow, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error)
+	// Snapshots every account that existed at as_of, starting from its previous
+	// snapshot so only the entries of the days since have to be summed.
+	CreateBalanceSnapshots(ctx context.Context, asOf time.Time) (int64, error)
+	CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error)
+	CreateCurrencyLimits(ctx context.Context, arg CreateCurrencyLimitsParams) (CurrencyLimit, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateFeeRule(ctx context.Context, arg CreateFeeRuleParams) (FeeRule, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateImportedAccount(ctx context.Context, arg CreateImportedAccountParams) (Account, error)
+	// Imported entries keep the time they were booked in the old core. They
+	// belong to no transfer or journal.
+	CreateImportedEntry(ctx context.Context, arg CreateImportedEntryParams) error
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) error
+	CreateInterestAccrualDay(ctx context.Context, arg CreateInterestAccrualDayParams) error
+	CreateJournal(ctx context.Context, arg CreateJournalParams) (Journal, error)
+	CreateLegacyAccount(ctx context.Context, arg CreateLegacyAccountParams) (LegacyAccount, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (Outbox, error)
+	CreatePayee(ctx context.Context, arg CreatePayeeParams) (Payee, error)
+	CreateReconciliationRun(ctx context.Context, arg CreateReconciliationRunParams) (ReconciliationRun, error)
+	CreateSystemAccount(ctx context.Context, arg CreateSystemAccountParams) (SystemAccount, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferApproval(ctx context.Context, arg CreateTransferApprovalParams) (TransferApproval, error)
+	CreateTransferStatusHistory(ctx context.Context, arg CreateTransferStatusHistoryParams) (TransferStatusHistory, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DecideTransferApproval(ctx context.Context, arg DecideTransferApprovalParams) (TransferApproval, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) (int64, error)
+	DeleteFeeRule(ctx context.Context, id int64) (FeeRule, error)
+	DeletePayee(ctx context.Context, id int64) error
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	ExpireTransferApprovals(ctx context.Context) (int64, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	// The owner's oldest account in the currency.
+	GetAccountByOwner(ctx context.Context, arg GetAccountByOwnerParams) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountLimits(ctx context.Context, accountID int64) (AccountLimit, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	// The balance of an account after every entry created up to and including
+	// at: its last snapshot before at plus the entries since.
+	GetBalanceAt(ctx context.Context, arg GetBalanceAtParams) (GetBalanceAtRow, error)
+	GetBalanceSnapshot(ctx context.Context, arg GetBalanceSnapshotParams) (BalanceSnapshot, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	GetCurrencyLimits(ctx context.Context, currency string) (CurrencyLimit, error)
+	GetEffectiveLimits(ctx context.Context, id int64) (GetEffectiveLimitsRow, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFeeRule(ctx context.Context, arg GetFeeRuleParams) (FeeRule, error)
+	GetFirstAccountTime(ctx context.Context) (sql.NullTime, error)
+	GetHeldAmount(ctx context.Context, accountID int64) (int64, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetJournal(ctx context.Context, id int64) (Journal, error)
+	GetLatestAccrualDay(ctx context.Context) (sql.NullTime, error)
+	GetLatestReconciliationRun(ctx context.Context) (ReconciliationRun, error)
+	GetLatestSnapshotTime(ctx context.Context) (sql.NullTime, error)
+	GetLegacyAccount(ctx context.Context, legacyID string) (LegacyAccount, error)
+	GetOutboxEvent(ctx context.Context, id int64) (Outbox, error)
+	GetOutgoingTotals(ctx context.Context, accountID int64) (GetOutgoingTotalsRow, error)
+	GetPayee(ctx context.Context, id int64) (Payee, error)
+	GetProduct(ctx context.Context, code string) (Product, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (SystemAccount, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferApproval(ctx context.Context, id int64) (TransferApproval, error)
+	GetTransferApprovalForUpdate(ctx context.Context, id int64) (TransferApproval, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByUsernameOrEmail(ctx context.Context, identifier string) (User, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
-	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListAggregateOutboxEvents(ctx context.Context, arg ListAggregateOutboxEventsParams) ([]Outbox, error)
+	ListBalanceDrift(ctx context.Context) ([]ListBalanceDriftRow, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	// The running balance is taken over every entry of the account, the window
+	// is evaluated before the page is cut.
+	ListEntries(ctx context.Context, arg ListEntriesParams) ([]ListEntriesRow, error)
+	ListEventWebhookSubscriptions(ctx context.Context, arg ListEventWebhookSubscriptionsParams) ([]WebhookSubscription, error)
+	// All fee rules, only those of the currency when it's given. The rule for
+	// every tier comes first in each currency.
+	ListFeeRules(ctx context.Context, currency sql.NullString) ([]FeeRule, error)
+	ListHolds(ctx context.Context, arg ListHoldsParams) ([]Hold, error)
+	ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error)
+	// The end-of-day balances accruing interest over the day ending at as_of,
+	// with the rate they accrue at: savings accounts in credit earn the rate of
+	// their product, overdrawn accounts are charged their overdraft rate.
+	ListInterestBalances(ctx context.Context, asOf time.Time) ([]ListInterestBalancesRow, error)
+	ListJournalEntries(ctx context.Context, journalID sql.NullInt64) ([]Entry, error)
+	// The accounts a user is a member of, in any role, leaving out pending
+	// invitations.
+	ListMemberAccounts(ctx context.Context, arg ListMemberAccountsParams) ([]Account, error)
+	// The invitations a user hasn't accepted or declined yet.
+	ListMemberInvites(ctx context.Context, username string) ([]AccountMember, error)
+	ListPayees(ctx context.Context, arg ListPayeesParams) ([]Payee, error)
+	ListProducts(ctx context.Context) ([]Product, error)
+	// The owner's accounts in the currency, only the one called name when it's
+	// given. Two rows are enough to tell the recipient is ambiguous.
+	ListRecipientAccounts(ctx context.Context, arg ListRecipientAccountsParams) ([]Account, error)
+	ListReconciliationRuns(ctx context.Context, arg ListReconciliationRunsParams) ([]ReconciliationRun, error)
+	// Pages through the entries of an account in a period by entry id. The
+	// counterparty of a transfer entry is the other side of its transfer, fee
+	// and other entries have none.
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListTransferApprovals(ctx context.Context, arg ListTransferApprovalsParams) ([]TransferApproval, error)
+	ListTransferDiscrepancies(ctx context.Context) ([]ListTransferDiscrepanciesRow, error)
+	ListTransferStatusHistory(ctx context.Context, transferID int64) ([]TransferStatusHistory, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	// The interest accrued and not posted yet per account and month, for the
+	// days ending at or before before. A day belongs to the month it started in.
+	// Interest earned and overdraft interest charged are summed apart, they are
+	// posted to different system accounts.
+	ListUnpostedInterest(ctx context.Context, before time.Time) ([]ListUnpostedInterestRow, error)
+	ListUnpublishedOutboxEvents(ctx context.Context, limit int32) ([]Outbox, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, arg ListWebhookSubscriptionsParams) ([]WebhookSubscription, error)
+	MarkInterestPosted(ctx context.Context, arg MarkInterestPostedParams) (int64, error)
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) (WebhookDelivery, error)
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error)
+	TryLockOutbox(ctx context.Context) (bool, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountOverdraft(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error)
+	UpdateAccountTier(ctx context.Context, arg UpdateAccountTierParams) (Account, error)
+	UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error)
+	UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error)
+	UpdatePayeeNickname(ctx context.Context, arg UpdatePayeeNicknameParams) (Payee, error)
+	UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error)
+	UpdateUserSettings(ctx context.Context, arg UpdateUserSettingsParams) (User, error)
+	UpsertAccountLimits(ctx context.Context, arg UpsertAccountLimitsParams) (AccountLimit, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Ledger problems reported for a transfer.
+const (
+	TransferIssueMissingEntries    = "missing_entries"
+	TransferIssueWrongEntryCount   = "wrong_entry_count"
+	TransferIssueMismatchedEntries = "mismatched_entries"
+	TransferIssueUnexpectedEntries = "unexpected_entries"
+	TransferIssueUnbalanced        = "unbalanced"
+	TransferIssueUnbalancedFee     = "unbalanced_fee"
+)
+
+// TransferIssue is a transfer whose entries don't add up.
+type TransferIssue struct {
+	ListTransferDiscrepanciesRow
+	Issues []string `json:"issues"`
+}
+
+// findIssues lists what is wrong with a transfer's entries. Completed
+// transfers need one debit of the sender and one credit of the recipient for
+// the amount, transfers that never settled must have no entries, and the
+// legs of the transfer and of its fee must each sum to zero.
+func (row ListTransferDiscrepanciesRow) findIssues() []string {
+	var issues []string
+	switch row.Status {
+	case TransferStatusCompleted:
+		switch {
+		case row.TransferEntries == 0:
+			issues = append(issues, TransferIssueMissingEntries)
+		case row.TransferEntries != 2:
+			issues = append(issues, TransferIssueWrongEntryCount)
+		case row.MatchingEntries != 2:
+			issues = append(issues, TransferIssueMismatchedEntries)
+		}
+	case TransferStatusPending, TransferStatusProcessing, TransferStatusFailed:
+		if row.TransferEntries+row.FeeEntries > 0 {
+			issues = append(issues, TransferIssueUnexpectedEntries)
+		}
+	}
+
+	if row.TransferTotal != 0 {
+		issues = append(issues, TransferIssueUnbalanced)
+	}
+	if row.FeeTotal != 0 || (row.FeeEntries != 0 && row.FeeEntries != 2) {
+		issues = append(issues, TransferIssueUnbalancedFee)
+	}
+	return issues
+}
+
+// ReconcileReport is what a reconciliation run found.
+type ReconcileReport struct {
+	Accounts  []ListBalanceDriftRow `json:"accounts"`
+	Transfers []TransferIssue       `json:"transfers"`
+}
+
+// Clean reports whether the ledger had no problems.
+func (report ReconcileReport) Clean() bool {
+	return len(report.Accounts) == 0 && len(report.Transfers) == 0
+}
+
+// ReconcileTX checks that every account's balance equals the sum of its
+// entries and that every transfer's entries balance, then records the run.
+// The ledger is read from a single snapshot so concurrent transfers can't
+// show up as drift.
+func (store *SQLStore) ReconcileTX(ctx context.Context) (ReconciliationRun, ReconcileReport, error) {
+	startedAt := time.Now()
+
+	report, err := store.scanLedger(ctx)
+	if err != nil {
+		return ReconciliationRun{}, report, err
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return ReconciliationRun{}, report, err
+	}
+
+	run, err := store.CreateReconciliationRun(ctx, CreateReconciliationRunParams{
+		StartedAt:       startedAt,
+		DriftedAccounts: int32(len(report.Accounts)),
+		TransferIssues:  int32(len(report.Transfers)),
+		Report:          data,
+	})
+	return run, report, err
+}
+
+func (store *SQLStore) scanLedger(ctx context.Context) (ReconcileReport, error) {
+	report := ReconcileReport{
+		Accounts:  []ListBalanceDriftRow{},
+		Transfers: []TransferIssue{},
+	}
+
+	tx, err := store.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback()
+	q := New(tx)
+
+	report.Accounts, err = q.ListBalanceDrift(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	rows, err := q.ListTransferDiscrepancies(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, row := range rows {
+		report.Transfers = append(report.Transfers, TransferIssue{
+			ListTransferDiscrepanciesRow: row,
+			Issues:                       row.findIssues(),
+		})
+	}
+
+	return report, tx.Commit()
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindIssues(t *testing.T) {
+	testCases := []struct {
+		name   string
+		row    ListTransferDiscrepanciesRow
+		issues []string
+	}{
+		{
+			name:   "MissingEntries",
+			row:    ListTransferDiscrepanciesRow{Status: TransferStatusCompleted},
+			issues: []string{TransferIssueMissingEntries},
+		},
+		{
+			name:   "WrongEntryCount",
+			row:    ListTransferDiscrepanciesRow{Status: TransferStatusCompleted, TransferEntries: 3, MatchingEntries: 2, TransferTotal: 10},
+			issues: []string{TransferIssueWrongEntryCount, TransferIssueUnbalanced},
+		},
+		{
+			name:   "MismatchedEntries",
+			row:    ListTransferDiscrepanciesRow{Status: TransferStatusCompleted, TransferEntries: 2, MatchingEntries: 1},
+			issues: []string{TransferIssueMismatchedEntries},
+		},
+		{
+			name:   "UnexpectedEntries",
+			row:    ListTransferDiscrepanciesRow{Status: TransferStatusFailed, TransferEntries: 2},
+			issues: []string{TransferIssueUnexpectedEntries},
+		},
+		{
+			name:   "UnbalancedFee",
+			row:    ListTransferDiscrepanciesRow{Status: TransferStatusCompleted, TransferEntries: 2, MatchingEntries: 2, FeeEntries: 1, FeeTotal: -5},
+			issues: []string{TransferIssueUnbalancedFee},
+		},
+		{
+			name: "Balanced",
+			row:  ListTransferDiscrepanciesRow{Status: TransferStatusCompleted, TransferEntries: 2, MatchingEntries: 2, FeeEntries: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.issues, tc.row.findIssues())
+		})
+	}
+}
+
+func TestReconcileTX(t *testing.T) {
+	store := NewStore(testDB)
+
+	// Random accounts are created with a balance but no entries.
+	drifted := createRandomAccount(t)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccountWithBalance(t, 100)
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	orphaned := createRandomTransfer(t, account1, account2)
+
+	run, report, err := store.ReconcileTX(context.Background())
+	require.NoError(t, err)
+	require.NotZero(t, run.ID)
+	require.False(t, report.Clean())
+	require.Equal(t, int32(len(report.Accounts)), run.DriftedAccounts)
+	require.Equal(t, int32(len(report.Transfers)), run.TransferIssues)
+
+	accounts := make(map[int64]ListBalanceDriftRow)
+	for _, row := range report.Accounts {
+		accounts[row.AccountID] = row
+	}
+	require.Contains(t, accounts, drifted.ID)
+	require.Equal(t, drifted.Balance, accounts[drifted.ID].Drift)
+
+	transfers := make(map[int64]TransferIssue)
+	for _, issue := range report.Transfers {
+		transfers[issue.TransferID] = issue
+	}
+	require.NotContains(t, transfers, result.Transfer.ID)
+	require.Contains(t, transfers, orphaned.ID)
+	require.Equal(t, []string{TransferIssueMissingEntries}, transfers[orphaned.ID].Issues)
+
+	latest, err := store.GetLatestReconciliationRun(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, run.ID, latest.ID)
+}
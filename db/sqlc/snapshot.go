@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// snapshotDelay is how long after midnight a day is snapshotted. Entries are
+// stamped when their transaction starts, so transactions still running at
+// midnight get this long to commit before the day is closed.
+const snapshotDelay = 5 * time.Minute
+
+// SnapshotDay returns the midnight UTC starting the day t falls on.
+func SnapshotDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+type SnapshotBalancesTxResult struct {
+	// Days is the number of midnights snapshotted.
+	Days      int   `json:"days"`
+	Snapshots int64 `json:"snapshots"`
+}
+
+// SnapshotBalancesTX snapshots the balance of every account at each midnight
+// since the last snapshot, up to the last midnight before now. Days that
+// were missed, e.g. while the job wasn't running, are caught up in order.
+func (store *SQLStore) SnapshotBalancesTX(ctx context.Context, now time.Time) (SnapshotBalancesTxResult, error) {
+	var result SnapshotBalancesTxResult
+	until := SnapshotDay(now.Add(-snapshotDelay))
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		result = SnapshotBalancesTxResult{}
+
+		latest, err := q.GetLatestSnapshotTime(ctx)
+		if err != nil {
+			return err
+		}
+
+		var day time.Time
+		if latest.Valid {
+			day = latest.Time.Add(24 * time.Hour)
+		} else {
+			first, err := q.GetFirstAccountTime(ctx)
+			if err != nil {
+				return err
+			}
+			if !first.Valid {
+				return nil
+			}
+			day = SnapshotDay(first.Time).Add(24 * time.Hour)
+		}
+
+		for ; !day.After(until); day = day.Add(24 * time.Hour) {
+			n, err := q.CreateBalanceSnapshots(ctx, day)
+			if err != nil {
+				return err
+			}
+			result.Days++
+			result.Snapshots += n
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotDay(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	day := SnapshotDay(time.Date(2024, 4, 1, 1, 30, 0, 0, local))
+	require.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), day)
+}
+
+func TestGetBalanceAt(t *testing.T) {
+	store := NewStore(testDB)
+
+	source := createCurrencyAccount(t, "USD", 100)
+	account := createCurrencyAccount(t, "USD", 0)
+
+	first, err := store.PostJournalTX(context.Background(), PostJournalParams{
+		Legs: []JournalLeg{
+			{AccountID: source.ID, Amount: -50},
+			{AccountID: account.ID, Amount: 50},
+		},
+	})
+	require.NoError(t, err)
+
+	asOf := first.Entries[1].CreatedAt.Add(time.Microsecond)
+	_, err = testQueries.CreateBalanceSnapshots(context.Background(), asOf)
+	require.NoError(t, err)
+
+	snapshot, err := testQueries.GetBalanceSnapshot(context.Background(), GetBalanceSnapshotParams{
+		AccountID: account.ID,
+		AsOf:      asOf,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), snapshot.Balance)
+
+	second, err := store.PostJournalTX(context.Background(), PostJournalParams{
+		Legs: []JournalLeg{
+			{AccountID: source.ID, Amount: -20},
+			{AccountID: account.ID, Amount: 20},
+		},
+	})
+	require.NoError(t, err)
+
+	balance, err := testQueries.GetBalanceAt(context.Background(), GetBalanceAtParams{
+		AccountID: account.ID,
+		At:        second.Entries[1].CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), balance.Balance)
+	require.True(t, balance.SnapshotAt.Valid)
+	require.WithinDuration(t, asOf, balance.SnapshotAt.Time, time.Microsecond)
+
+	balance, err = testQueries.GetBalanceAt(context.Background(), GetBalanceAtParams{
+		AccountID: account.ID,
+		At:        first.Entries[1].CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), balance.Balance)
+	require.False(t, balance.SnapshotAt.Valid)
+
+	balance, err = testQueries.GetBalanceAt(context.Background(), GetBalanceAtParams{
+		AccountID: account.ID,
+		At:        account.CreatedAt.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Zero(t, balance.Balance)
+}
+
+func TestSnapshotBalancesTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	createRandomAccount(t)
+	now := time.Now()
+
+	_, err := store.SnapshotBalancesTX(context.Background(), now)
+	require.NoError(t, err)
+
+	latest, err := testQueries.GetLatestSnapshotTime(context.Background())
+	require.NoError(t, err)
+	if latest.Valid {
+		require.False(t, latest.Time.After(now))
+	}
+
+	// Running it again has nothing left to do.
+	result, err := store.SnapshotBalancesTX(context.Background(), now)
+	require.NoError(t, err)
+	require.Zero(t, result.Days)
+	require.Zero(t, result.Snapshots)
+}
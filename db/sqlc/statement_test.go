@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListStatementEntries(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	var transfers []TransferTxResult
+	for i := 0; i < 3; i++ {
+		result, err := store.TransferTX(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+			Description:   "rent",
+		})
+		require.NoError(t, err)
+		transfers = append(transfers, result)
+	}
+
+	arg := ListStatementEntriesParams{
+		AccountID: account2.ID,
+		FromTime:  transfers[0].ToEntry.CreatedAt,
+		ToTime:    time.Now().Add(time.Minute),
+		PageSize:  2,
+	}
+	entries, err := testQueries.ListStatementEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, transfers[0].ToEntry.ID, entries[0].ID)
+	require.Equal(t, account1.ID, entries[0].CounterpartyAccountID.Int64)
+	require.Equal(t, account1.Owner, entries[0].CounterpartyOwner.String)
+	require.Equal(t, "rent", entries[0].Description)
+
+	arg.AfterID = entries[1].ID
+	entries, err = testQueries.ListStatementEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, transfers[2].ToEntry.ID, entries[0].ID)
+
+	arg.ToTime = transfers[0].ToEntry.CreatedAt.Add(-time.Second)
+	arg.FromTime = arg.ToTime.Add(-time.Hour)
+	arg.AfterID = 0
+	entries, err = testQueries.ListStatementEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestReadTXSnapshot(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	err := store.ReadTX(context.Background(), func(q Querier) error {
+		before, err := q.GetBalanceAt(context.Background(), GetBalanceAtParams{AccountID: account2.ID, At: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+
+		// Committed outside the snapshot, so not seen inside it.
+		_, err = store.TransferTX(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		require.NoError(t, err)
+
+		after, err := q.GetBalanceAt(context.Background(), GetBalanceAtParams{AccountID: account2.ID, At: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		require.Equal(t, before.Balance, after.Balance)
+
+		// The snapshot is read-only.
+		_, err = q.CreateEntry(context.Background(), CreateEntryParams{AccountID: account2.ID, Amount: 1, Kind: EntryKindTransfer})
+		return err
+	})
+	require.Error(t, err)
+}
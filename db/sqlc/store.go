@@ -3,28 +3,87 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 )
 
+// ErrInsufficientFunds is returned when a debit would take an account's
+// available balance (ledger balance minus active holds) below zero, or below
+// its overdraft limit when it has one.
+var ErrInsufficientFunds = errors.New("insufficient available balance")
+
 type Store interface {
 	TransferTX(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	BatchTransferTX(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error)
+	PlaceHoldTX(ctx context.Context, arg PlaceHoldTxParams) (Hold, error)
+	CaptureHoldTX(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	ReleaseHoldTX(ctx context.Context, holdID int64) (Hold, error)
+	RequestApprovalTX(ctx context.Context, arg RequestApprovalTxParams) (TransferApproval, error)
+	DecideTransferTX(ctx context.Context, arg DecideTransferTxParams) (DecideTransferTxResult, error)
+	CreateAccountTX(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateUserTX(ctx context.Context, arg CreateUserParams) (User, error)
+	PublishOutboxTX(ctx context.Context, arg PublishOutboxTxParams) (PublishOutboxTxResult, error)
+	ReconcileTX(ctx context.Context) (ReconciliationRun, ReconcileReport, error)
+	PostJournalTX(ctx context.Context, arg PostJournalParams) (PostJournalResult, error)
+	SnapshotBalancesTX(ctx context.Context, now time.Time) (SnapshotBalancesTxResult, error)
+	ImportAccountsTX(ctx context.Context, arg ImportAccountsTxParams) (ImportAccountsTxResult, error)
+	AccrueInterestTX(ctx context.Context) (AccrueInterestTxResult, error)
+	PostInterestTX(ctx context.Context) (PostInterestTxResult, error)
+	CreateCurrencyTX(ctx context.Context, arg CreateCurrencyParams) (Currency, error)
+	ReadTX(ctx context.Context, fn func(q Querier) error) error
 	Querier
 }
 
 type SQLStore struct {
 	*Queries
 	db *sql.DB
+
+	txOptions    *sql.TxOptions
+	maxRetries   int
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
 }
 
-func NewStore(db *sql.DB) *SQLStore {
-	return &SQLStore{
-		db:      db,
-		Queries: New(db),
+func NewStore(db *sql.DB, opts ...StoreOption) *SQLStore {
+	store := &SQLStore{
+		db:           db,
+		Queries:      New(db),
+		maxRetries:   defaultTxMaxRetries,
+		retryBackoff: defaultTxRetryBackoff,
+		maxBackoff:   defaultTxMaxBackoff,
 	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
 }
 
+// execTX runs fn in a transaction. Transactions aborted by a serialization
+// failure or deadlock are run again from the start, so fn must not leave
+// anything behind that a second run would trip over.
 func (store *SQLStore) execTX(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+	for attempt := 0; ; attempt++ {
+		err := store.execTXOnce(ctx, fn)
+		if !isRetryable(err) {
+			return err
+		}
+		countConflict(err)
+
+		if attempt >= store.maxRetries {
+			txMetrics.Add(metricRetriesExhausted, 1)
+			return err
+		}
+
+		if waitErr := store.waitRetry(ctx, attempt); waitErr != nil {
+			return err
+		}
+		txMetrics.Add(metricRetries, 1)
+	}
+}
+
+func (store *SQLStore) execTXOnce(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, store.txOptions)
 	if err != nil {
 		return err
 	}
@@ -42,10 +101,28 @@ func (store *SQLStore) execTX(ctx context.Context, fn func(*Queries) error) erro
 	return tx.Commit()
 }
 
+// ReadTX runs fn in a read-only REPEATABLE READ transaction, so every query
+// it makes sees the same snapshot. Read-only snapshots never conflict with
+// writers, there is nothing to retry.
+func (store *SQLStore) ReadTX(ctx context.Context, fn func(q Querier) error) error {
+	tx, err := store.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(New(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 type TransferTxParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID     int64  `json:"from_account_id"`
+	ToAccountID       int64  `json:"to_account_id"`
+	Amount            int64  `json:"amount"`
+	Description       string `json:"description"`
+	ExternalReference string `json:"external_reference"`
 }
 
 type TransferTxResult struct {
@@ -54,83 +131,131 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// Fee charged to the sender on top of the amount, FeeEntry is the
+	// sender's fee entry and is only set when a fee was charged.
+	Fee      int64  `json:"fee"`
+	FeeEntry *Entry `json:"fee_entry,omitempty"`
+	// Journal groups every entry the transfer posted.
+	Journal Journal `json:"journal"`
 }
 
 var txKey = struct{}{}
 
+// TransferTX moves money between two accounts. A transfer refused for lack
+// of funds or by a limit is rolled back and then recorded as failed in a
+// transaction of its own, see TransferFailedError.
 func (store *SQLStore) TransferTX(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 	err := store.execTX(ctx, func(q *Queries) error {
 		var err error
-		result.Transfer, err = q.CreateTransfer(
-			ctx, CreateTransferParams{
-				FromAccountID: arg.FromAccountID,
-				ToAccountID:   arg.ToAccountID,
-				Amount:        arg.Amount,
-			},
-		)
-
-		if err != nil {
-			return err
+		result, err = transfer(ctx, q, arg)
+		return err
+	})
+	if err != nil && isTransferFailure(err) {
+		err = store.recordFailure(ctx, arg, err)
+		var failed *TransferFailedError
+		if errors.As(err, &failed) {
+			result = TransferTxResult{Transfer: failed.Transfer}
 		}
+	}
+	return result, err
+}
 
-		result.FromEntry, err = q.CreateEntry(ctx,
-			CreateEntryParams{
-				AccountID: arg.FromAccountID,
-				Amount:    -arg.Amount,
-			},
-		)
-		if err != nil {
-			return err
-		}
+// recordFailure records a transfer refused by cause as failed, once the
+// transaction that tried it has been rolled back. If it can't be recorded the
+// transfer is only reported as refused.
+func (store *SQLStore) recordFailure(ctx context.Context, arg TransferTxParams, cause error) error {
+	var failed Transfer
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		failed, err = recordFailedTransfer(ctx, q, arg, cause.Error())
+		return err
+	})
+	if err != nil {
+		return cause
+	}
+	return &TransferFailedError{Transfer: failed, Err: cause}
+}
 
-		result.ToEntry, err = q.CreateEntry(ctx,
-			CreateEntryParams{
-				AccountID: arg.ToAccountID,
-				Amount:    arg.Amount,
-			},
-		)
+// transfer moves money between two accounts inside an existing transaction,
+// so that other transactions (e.g. hold captures) can reuse it.
+func transfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	t, err := insertTransfer(ctx, q, CreateTransferParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount,
+		Description:       arg.Description,
+		ExternalReference: arg.ExternalReference,
+		Status:            TransferStatusProcessing,
+	})
+	if err != nil {
+		return TransferTxResult{}, err
+	}
 
-		if err != nil {
-			return err
-		}
+	return settleTransfer(ctx, q, t)
+}
 
-		if arg.FromAccountID < arg.ToAccountID {
+// settleTransfer posts the journal of a processing transfer, moving the
+// amount and any fee in one go, and marks the transfer completed.
+func settleTransfer(ctx context.Context, q *Queries, t Transfer) (TransferTxResult, error) {
+	result := TransferTxResult{Transfer: t}
 
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, arg.ToAccountID, -arg.Amount, arg.Amount)
-		} else {
+	from, err := q.GetAccount(ctx, t.FromAccountID)
+	if err != nil {
+		return result, err
+	}
 
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.FromAccountID, arg.Amount, -arg.Amount)
+	fee, fees, err := feeLegs(ctx, q, from, t)
+	if err != nil {
+		return result, err
+	}
 
-		}
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-	return result, err
-}
+	// Both transfer legs carry the transfer's memo so each party sees it in
+	// their own account history.
+	legs := append([]JournalLeg{
+		{AccountID: t.FromAccountID, Amount: -t.Amount},
+		{AccountID: t.ToAccountID, Amount: t.Amount},
+	}, fees...)
 
-func addMoney(ctx context.Context, q *Queries, account1ID, account2ID, amount1, amount2 int64) (account1 Account, account2 Account, err error) {
+	posted, err := postJournal(ctx, q, PostJournalParams{
+		Description:       t.Description,
+		ExternalReference: t.ExternalReference,
+		TransferID:        sql.NullInt64{Int64: t.ID, Valid: true},
+		Legs:              legs,
+	})
+	if err != nil {
+		return result, err
+	}
 
-	account1, err = q.AddAccountBalance(ctx,
-		AddAccountBalanceParams{
-			Amount: amount1,
-			ID:     account1ID,
-		},
-	)
+	result.Journal = posted.Journal
+	result.FromEntry = posted.Entries[0]
+	result.ToEntry = posted.Entries[1]
+	result.FromAccount = posted.Accounts[t.FromAccountID]
+	result.ToAccount = posted.Accounts[t.ToAccountID]
+	if fee > 0 {
+		result.Fee = fee
+		result.FeeEntry = &posted.Entries[2]
+	}
 
+	// The balance update above already holds the row lock on the sender, so
+	// the active holds read here can't change until we commit.
+	held, err := q.GetHeldAmount(ctx, t.FromAccountID)
 	if err != nil {
-		return
+		return result, err
 	}
 
-	account2, err = q.AddAccountBalance(ctx,
-		AddAccountBalanceParams{
-			Amount: amount2,
-			ID:     account2ID,
-		},
-	)
+	credit, err := result.FromAccount.AvailableCredit(held)
+	if err != nil {
+		return result, err
+	}
+	if credit < 0 {
+		return result, ErrInsufficientFunds
+	}
 
-	return
+	if err := checkLimits(ctx, q, t.FromAccountID, t.Amount); err != nil {
+		return result, err
+	}
 
+	result.Transfer, err = setTransferStatus(ctx, q, t, TransferStatusCompleted, "")
+	return result, err
 }
@@ -7,26 +7,40 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  description,
+  external_reference,
+  status
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, created_at, description, external_reference, status, failure_reason, updated_at
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID     int64  `json:"from_account_id"`
+	ToAccountID       int64  `json:"to_account_id"`
+	Amount            int64  `json:"amount"`
+	Description       string `json:"description"`
+	ExternalReference string `json:"external_reference"`
+	Status            string `json:"status"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Description,
+		arg.ExternalReference,
+		arg.Status,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +48,54 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Status,
+		&i.FailureReason,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createTransferStatusHistory = `-- name: CreateTransferStatusHistory :one
+INSERT INTO transfer_status_history (
+  transfer_id,
+  from_status,
+  to_status,
+  reason
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, transfer_id, from_status, to_status, reason, created_at
+`
+
+type CreateTransferStatusHistoryParams struct {
+	TransferID int64          `json:"transfer_id"`
+	FromStatus sql.NullString `json:"from_status"`
+	ToStatus   string         `json:"to_status"`
+	Reason     string         `json:"reason"`
+}
+
+func (q *Queries) CreateTransferStatusHistory(ctx context.Context, arg CreateTransferStatusHistoryParams) (TransferStatusHistory, error) {
+	row := q.db.QueryRowContext(ctx, createTransferStatusHistory,
+		arg.TransferID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Reason,
+	)
+	var i TransferStatusHistory
+	err := row.Scan(
+		&i.ID,
+		&i.TransferID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.Reason,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, description, external_reference, status, failure_reason, updated_at FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +108,77 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Status,
+		&i.FailureReason,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, description, external_reference, status, failure_reason, updated_at FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Status,
+		&i.FailureReason,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const listTransferStatusHistory = `-- name: ListTransferStatusHistory :many
+SELECT id, transfer_id, from_status, to_status, reason, created_at FROM transfer_status_history
+WHERE transfer_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListTransferStatusHistory(ctx context.Context, transferID int64) ([]TransferStatusHistory, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferStatusHistory, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferStatusHistory{}
+	for rows.Next() {
+		var i TransferStatusHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransferID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, description, external_reference, status, failure_reason, updated_at FROM transfers
 WHERE 
     from_account_id = $1 OR
     to_account_id = $2
@@ -93,6 +214,70 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Description,
+			&i.ExternalReference,
+			&i.Status,
+			&i.FailureReason,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfers = `-- name: SearchTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, description, external_reference, status, failure_reason, updated_at FROM transfers
+WHERE (from_account_id = $1 OR to_account_id = $1)
+  AND (
+    $2::text = ''
+    OR description ILIKE '%' || $2::text || '%'
+    OR external_reference ILIKE '%' || $2::text || '%'
+  )
+ORDER BY id DESC
+LIMIT $3
+OFFSET $4
+`
+
+type SearchTransfersParams struct {
+	AccountID int64  `json:"account_id"`
+	Query     string `json:"query"`
+	Limit     int32  `json:"limit"`
+	Offset    int32  `json:"offset"`
+}
+
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfers,
+		arg.AccountID,
+		arg.Query,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Description,
+			&i.ExternalReference,
+			&i.Status,
+			&i.FailureReason,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -106,3 +291,36 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const updateTransferStatus = `-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = $1,
+  failure_reason = $2,
+  updated_at = now()
+WHERE id = $3
+RETURNING id, from_account_id, to_account_id, amount, created_at, description, external_reference, status, failure_reason, updated_at
+`
+
+type UpdateTransferStatusParams struct {
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason"`
+	ID            int64  `json:"id"`
+}
+
+func (q *Queries) UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, updateTransferStatus, arg.Status, arg.FailureReason, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Description,
+		&i.ExternalReference,
+		&i.Status,
+		&i.FailureReason,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
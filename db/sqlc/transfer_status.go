@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const (
+	TransferStatusPending    = "pending"
+	TransferStatusProcessing = "processing"
+	TransferStatusCompleted  = "completed"
+	TransferStatusFailed     = "failed"
+	TransferStatusReversed   = "reversed"
+)
+
+// transferTransitions lists the statuses a transfer may move to from each
+// status. Failed and reversed transfers are final.
+var transferTransitions = map[string][]string{
+	TransferStatusPending:    {TransferStatusProcessing, TransferStatusFailed},
+	TransferStatusProcessing: {TransferStatusCompleted, TransferStatusFailed},
+	TransferStatusCompleted:  {TransferStatusReversed},
+}
+
+// CanTransition reports whether a transfer in status from may move to status to.
+func CanTransition(from, to string) bool {
+	for _, next := range transferTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// InvalidTransitionError is returned when a status change isn't allowed by
+// the transfer state machine.
+type InvalidTransitionError struct {
+	TransferID int64  `json:"transfer_id"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("transfer [%d] can't move from %s to %s", e.TransferID, e.From, e.To)
+}
+
+// TransferFailedError is returned for a transfer that was refused while it
+// was being settled. The settlement is rolled back, but the transfer is kept
+// in the failed status with Err as its failure reason.
+type TransferFailedError struct {
+	Transfer Transfer `json:"transfer"`
+	Err      error    `json:"-"`
+}
+
+func (e *TransferFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransferFailedError) Unwrap() error {
+	return e.Err
+}
+
+// isTransferFailure reports whether err refused a transfer for a reason
+// worth keeping, as opposed to bad input or a database error.
+func isTransferFailure(err error) bool {
+	return errors.Is(err, ErrInsufficientFunds) || errors.As(err, new(*LimitExceededError))
+}
+
+// recordFailedTransfer stores a transfer whose settlement was rolled back as
+// failed, with reason as its failure reason.
+func recordFailedTransfer(ctx context.Context, q *Queries, arg TransferTxParams, reason string) (Transfer, error) {
+	t, err := insertTransfer(ctx, q, CreateTransferParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount,
+		Description:       arg.Description,
+		ExternalReference: arg.ExternalReference,
+		Status:            TransferStatusProcessing,
+	})
+	if err != nil {
+		return t, err
+	}
+	return setTransferStatus(ctx, q, t, TransferStatusFailed, reason)
+}
+
+// insertTransfer inserts a transfer in its initial status and records it as
+// the first entry of the transfer's status history and as an outbox event.
+func insertTransfer(ctx context.Context, q *Queries, arg CreateTransferParams) (Transfer, error) {
+	transfer, err := q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return transfer, err
+	}
+
+	_, err = q.CreateTransferStatusHistory(ctx, CreateTransferStatusHistoryParams{
+		TransferID: transfer.ID,
+		ToStatus:   transfer.Status,
+	})
+	if err != nil {
+		return transfer, err
+	}
+
+	return transfer, addTransferEvent(ctx, q, transfer)
+}
+
+// setTransferStatus moves a transfer to a new status and records the
+// transition in the history and the outbox. The reason is stored as the
+// failure reason for failed transfers.
+func setTransferStatus(ctx context.Context, q *Queries, transfer Transfer, status string, reason string) (Transfer, error) {
+	if !CanTransition(transfer.Status, status) {
+		return transfer, &InvalidTransitionError{
+			TransferID: transfer.ID,
+			From:       transfer.Status,
+			To:         status,
+		}
+	}
+
+	failureReason := ""
+	if status == TransferStatusFailed {
+		failureReason = reason
+	}
+
+	updated, err := q.UpdateTransferStatus(ctx, UpdateTransferStatusParams{
+		ID:            transfer.ID,
+		Status:        status,
+		FailureReason: failureReason,
+	})
+	if err != nil {
+		return transfer, err
+	}
+
+	_, err = q.CreateTransferStatusHistory(ctx, CreateTransferStatusHistoryParams{
+		TransferID: transfer.ID,
+		FromStatus: sql.NullString{String: transfer.Status, Valid: true},
+		ToStatus:   status,
+		Reason:     reason,
+	})
+	if err != nil {
+		return updated, err
+	}
+
+	return updated, addTransferEvent(ctx, q, updated)
+}
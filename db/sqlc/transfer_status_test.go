@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransition(t *testing.T) {
+	require.True(t, CanTransition(TransferStatusPending, TransferStatusProcessing))
+	require.True(t, CanTransition(TransferStatusPending, TransferStatusFailed))
+	require.True(t, CanTransition(TransferStatusProcessing, TransferStatusCompleted))
+	require.True(t, CanTransition(TransferStatusCompleted, TransferStatusReversed))
+
+	require.False(t, CanTransition(TransferStatusPending, TransferStatusCompleted))
+	require.False(t, CanTransition(TransferStatusCompleted, TransferStatusFailed))
+	require.False(t, CanTransition(TransferStatusFailed, TransferStatusProcessing))
+	require.False(t, CanTransition(TransferStatusReversed, TransferStatusCompleted))
+}
+
+func TestTransferTxStatusHistory(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusCompleted, result.Transfer.Status)
+
+	history, err := testQueries.ListTransferStatusHistory(context.Background(), result.Transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	require.False(t, history[0].FromStatus.Valid)
+	require.Equal(t, TransferStatusProcessing, history[0].ToStatus)
+	require.Equal(t, TransferStatusProcessing, history[1].FromStatus.String)
+	require.Equal(t, TransferStatusCompleted, history[1].ToStatus)
+}
+
+func TestSetTransferStatusInvalidTransition(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	_, err = setTransferStatus(context.Background(), testQueries, result.Transfer, TransferStatusFailed, "too late")
+	require.Error(t, err)
+
+	var transitionErr *InvalidTransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	require.Equal(t, TransferStatusCompleted, transitionErr.From)
+	require.Equal(t, TransferStatusFailed, transitionErr.To)
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,9 +12,12 @@ import (
 
 func createRandomTransfer(t *testing.T, account1, account2 Account) Transfer {
 	arg := CreateTransferParams{
-		FromAccountID: account1.ID,
-		ToAccountID:   account2.ID,
-		Amount:        util.RandomMoney(),
+		FromAccountID:     account1.ID,
+		ToAccountID:       account2.ID,
+		Amount:            util.RandomMoney(),
+		Description:       util.RandomString(12),
+		ExternalReference: util.RandomString(8),
+		Status:            TransferStatusCompleted,
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
@@ -23,6 +27,9 @@ func createRandomTransfer(t *testing.T, account1, account2 Account) Transfer {
 	require.Equal(t, arg.FromAccountID, transfer.FromAccountID)
 	require.Equal(t, arg.ToAccountID, transfer.ToAccountID)
 	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, arg.Description, transfer.Description)
+	require.Equal(t, arg.ExternalReference, transfer.ExternalReference)
+	require.Equal(t, arg.Status, transfer.Status)
 
 	require.NotZero(t, transfer.ID)
 	require.NotZero(t, transfer.CreatedAt)
@@ -77,3 +84,31 @@ func TestListTransfer(t *testing.T) {
 		require.True(t, transfer.FromAccountID == account1.ID || transfer.ToAccountID == account1.ID)
 	}
 }
+
+func TestSearchTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	var match Transfer
+	for i := 0; i < 5; i++ {
+		match = createRandomTransfer(t, account1, account2)
+		createRandomTransfer(t, account2, account1)
+	}
+
+	transfers, err := testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: account2.ID,
+		Limit:     20,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 10)
+	require.Greater(t, transfers[0].ID, transfers[1].ID)
+
+	transfers, err = testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: account1.ID,
+		Query:     strings.ToUpper(match.ExternalReference),
+		Limit:     20,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, match.ID, transfers[0].ID)
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	defaultTxMaxRetries   = 3
+	defaultTxRetryBackoff = 10 * time.Millisecond
+	defaultTxMaxBackoff   = 500 * time.Millisecond
+)
+
+// Postgres error codes after which the whole transaction can simply be run
+// again.
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
+// txMetrics counts transaction retries, published under "db_tx" by expvar.
+var txMetrics = expvar.NewMap("db_tx")
+
+const (
+	metricSerializationFailures = "serialization_failures"
+	metricDeadlocks             = "deadlocks"
+	metricRetries               = "retries"
+	metricRetriesExhausted      = "retries_exhausted"
+)
+
+// StoreOption configures how a SQLStore runs its transactions.
+type StoreOption func(*SQLStore)
+
+// WithIsolationLevel runs every store transaction at the given isolation
+// level instead of the database default.
+func WithIsolationLevel(level sql.IsolationLevel) StoreOption {
+	return func(store *SQLStore) {
+		store.txOptions = &sql.TxOptions{Isolation: level}
+	}
+}
+
+// WithMaxRetries sets how many times a transaction that failed with a
+// serialization failure or deadlock is run again. Zero disables retries.
+func WithMaxRetries(retries int) StoreOption {
+	return func(store *SQLStore) {
+		if retries < 0 {
+			retries = 0
+		}
+		store.maxRetries = retries
+	}
+}
+
+// WithRetryBackoff sets the base delay before the first retry, which doubles
+// with every attempt up to maxDelay.
+func WithRetryBackoff(base, maxDelay time.Duration) StoreOption {
+	return func(store *SQLStore) {
+		store.retryBackoff = base
+		store.maxBackoff = maxDelay
+	}
+}
+
+// ParseIsolationLevel accepts the names printed by sql.IsolationLevel in any
+// case, with spaces or underscores, e.g. "serializable" or "REPEATABLE_READ".
+// An empty name is the database default.
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", " "))
+	if name == "" {
+		return sql.LevelDefault, nil
+	}
+
+	for level := sql.LevelDefault; level <= sql.LevelLinearizable; level++ {
+		if strings.ToLower(level.String()) == name {
+			return level, nil
+		}
+	}
+	return sql.LevelDefault, fmt.Errorf("unknown isolation level %q", name)
+}
+
+// isRetryable reports whether err aborted the transaction because of a
+// conflict with a concurrent one, so running it again may succeed.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == serializationFailureCode || pqErr.Code == deadlockDetectedCode
+}
+
+func countConflict(err error) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return
+	}
+	switch pqErr.Code {
+	case serializationFailureCode:
+		txMetrics.Add(metricSerializationFailures, 1)
+	case deadlockDetectedCode:
+		txMetrics.Add(metricDeadlocks, 1)
+	}
+}
+
+// retryDelay returns the exponential backoff for the given attempt, starting
+// at zero, with half of it jittered so that conflicting transactions don't
+// retry in lockstep.
+func (store *SQLStore) retryDelay(attempt int) time.Duration {
+	delay := store.retryBackoff
+	for i := 0; i < attempt && delay < store.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > store.maxBackoff {
+		delay = store.maxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// waitRetry sleeps before the next attempt, giving up early when ctx is done.
+func (store *SQLStore) waitRetry(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(store.retryDelay(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
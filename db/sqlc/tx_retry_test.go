@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func txMetric(name string) int64 {
+	v, ok := txMetrics.Get(name).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
+
+func TestParseIsolationLevel(t *testing.T) {
+	for name, want := range map[string]sql.IsolationLevel{
+		"":                sql.LevelDefault,
+		"serializable":    sql.LevelSerializable,
+		"REPEATABLE_READ": sql.LevelRepeatableRead,
+		"Read Committed":  sql.LevelReadCommitted,
+	} {
+		level, err := ParseIsolationLevel(name)
+		require.NoError(t, err)
+		require.Equal(t, want, level)
+	}
+
+	_, err := ParseIsolationLevel("chaos")
+	require.Error(t, err)
+}
+
+func TestRetryDelay(t *testing.T) {
+	store := NewStore(testDB, WithRetryBackoff(10*time.Millisecond, 100*time.Millisecond))
+
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := store.retryDelay(attempt)
+		require.GreaterOrEqual(t, delay, 5*time.Millisecond)
+		require.LessOrEqual(t, delay, 100*time.Millisecond)
+	}
+}
+
+func TestExecTXRetry(t *testing.T) {
+	store := NewStore(testDB, WithMaxRetries(3), WithRetryBackoff(time.Millisecond, time.Millisecond))
+	retries := txMetric(metricRetries)
+	deadlocks := txMetric(metricDeadlocks)
+
+	attempts := 0
+	err := store.execTX(context.Background(), func(q *Queries) error {
+		attempts++
+		if attempts < 3 {
+			return &pq.Error{Code: deadlockDetectedCode}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+	require.Equal(t, retries+2, txMetric(metricRetries))
+	require.Equal(t, deadlocks+2, txMetric(metricDeadlocks))
+}
+
+func TestExecTXRetryExhausted(t *testing.T) {
+	store := NewStore(testDB, WithMaxRetries(2), WithRetryBackoff(time.Millisecond, time.Millisecond))
+	exhausted := txMetric(metricRetriesExhausted)
+
+	attempts := 0
+	err := store.execTX(context.Background(), func(q *Queries) error {
+		attempts++
+		return &pq.Error{Code: serializationFailureCode}
+	})
+	require.True(t, isRetryable(err))
+	require.Equal(t, 3, attempts)
+	require.Equal(t, exhausted+1, txMetric(metricRetriesExhausted))
+}
+
+func TestExecTXNoRetry(t *testing.T) {
+	store := NewStore(testDB)
+
+	attempts := 0
+	err := store.execTX(context.Background(), func(q *Queries) error {
+		attempts++
+		return sql.ErrNoRows
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Equal(t, 1, attempts)
+}
+
+// TestExecTXSerializationConflict makes two serializable transactions read
+// the same account before either of them writes it, so the second write
+// always fails with a serialization failure and has to be retried.
+func TestExecTXSerializationConflict(t *testing.T) {
+	store := NewStore(testDB,
+		WithIsolationLevel(sql.LevelSerializable),
+		WithMaxRetries(5),
+		WithRetryBackoff(time.Millisecond, 10*time.Millisecond),
+	)
+	account := createRandomAccount(t)
+	failures := txMetric(metricSerializationFailures)
+
+	n := 2
+	var read sync.WaitGroup
+	read.Add(n)
+
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			first := true
+			errs <- store.execTX(context.Background(), func(q *Queries) error {
+				if _, err := q.GetAccount(context.Background(), account.ID); err != nil {
+					return err
+				}
+
+				// Only the first attempts wait for each other, retries run
+				// on their own.
+				if first {
+					first = false
+					read.Done()
+					read.Wait()
+				}
+
+				_, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+					ID:     account.ID,
+					Amount: 10,
+				})
+				return err
+			})
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+	require.Greater(t, txMetric(metricSerializationFailures), failures)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+int64(n)*10, updated.Balance)
+}
+
+func TestTransferTxSerializable(t *testing.T) {
+	store := NewStore(testDB, WithIsolationLevel(sql.LevelSerializable), WithMaxRetries(10))
+
+	n := 10
+	amount := int64(10)
+	account1 := createRandomAccountWithBalance(t, int64(n)*amount)
+	account2 := createRandomAccountWithBalance(t, int64(n)*amount)
+
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		fromAccountID, toAccountID := account1.ID, account2.ID
+		if i%2 == 1 {
+			fromAccountID, toAccountID = account2.ID, account1.ID
+		}
+
+		go func() {
+			_, err := store.TransferTX(context.Background(), TransferTxParams{
+				FromAccountID: fromAccountID,
+				ToAccountID:   toAccountID,
+				Amount:        amount,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updated1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	updated2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updated1.Balance)
+	require.Equal(t, account2.Balance, updated2.Balance)
+}
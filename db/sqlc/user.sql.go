@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -17,7 +18,7 @@ INSERT INTO users (
     email
 ) VALUES (
     $1,$2,$3,$4
-) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, multiple_accounts, max_accounts
 `
 
 type CreateUserParams struct {
@@ -42,12 +43,15 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.MultipleAccounts,
+		&i.MaxAccounts,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users WHERE username = $1 LIMIT 1
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, multiple_accounts, max_accounts FROM users WHERE username = $1 LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
@@ -60,6 +64,86 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.MultipleAccounts,
+		&i.MaxAccounts,
+	)
+	return i, err
+}
+
+const getUserByUsernameOrEmail = `-- name: GetUserByUsernameOrEmail :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, multiple_accounts, max_accounts FROM users
+WHERE username = $1
+   OR lower(email) = lower($1)
+LIMIT 1
+`
+
+func (q *Queries) GetUserByUsernameOrEmail(ctx context.Context, identifier string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsernameOrEmail, identifier)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.MultipleAccounts,
+		&i.MaxAccounts,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, multiple_accounts, max_accounts FROM users WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserForUpdate, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.MultipleAccounts,
+		&i.MaxAccounts,
+	)
+	return i, err
+}
+
+const updateUserSettings = `-- name: UpdateUserSettings :one
+UPDATE users
+SET multiple_accounts = COALESCE($1, multiple_accounts),
+  max_accounts = COALESCE($2, max_accounts)
+WHERE username = $3
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, multiple_accounts, max_accounts
+`
+
+type UpdateUserSettingsParams struct {
+	MultipleAccounts sql.NullBool  `json:"multiple_accounts"`
+	MaxAccounts      sql.NullInt32 `json:"max_accounts"`
+	Username         string        `json:"username"`
+}
+
+func (q *Queries) UpdateUserSettings(ctx context.Context, arg UpdateUserSettingsParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserSettings, arg.MultipleAccounts, arg.MaxAccounts, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.MultipleAccounts,
+		&i.MaxAccounts,
 	)
 	return i, err
 }
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,6 +34,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, arg.FullName, user.FullName)
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
 	require.Equal(t, arg.Username, user.Username)
+	require.Equal(t, util.DepositorRole, user.Role)
 
 	require.True(t, user.PasswordChangedAt.IsZero())
 	require.NotZero(t, user.CreatedAt)
@@ -55,3 +58,18 @@ func TestGetUser(t *testing.T) {
 	require.WithinDuration(t, randomUser.PasswordChangedAt, fetchedUser.PasswordChangedAt, time.Second)
 
 }
+
+func TestGetUserByUsernameOrEmail(t *testing.T) {
+	user := createRandomUser(t)
+
+	byUsername, err := testQueries.GetUserByUsernameOrEmail(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, byUsername.Username)
+
+	byEmail, err := testQueries.GetUserByUsernameOrEmail(context.Background(), strings.ToUpper(user.Email))
+	require.NoError(t, err)
+	require.Equal(t, user.Username, byEmail.Username)
+
+	_, err = testQueries.GetUserByUsernameOrEmail(context.Background(), util.RandomEmail(util.RandomOwner()))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhook(t *testing.T, owner string, eventTypes ...string) WebhookSubscription {
+	arg := CreateWebhookSubscriptionParams{
+		Owner:      owner,
+		Url:        "https://example.com/" + util.RandomString(8),
+		EventTypes: eventTypes,
+		Secret:     util.RandomString(32),
+	}
+
+	subscription, err := testQueries.CreateWebhookSubscription(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, subscription.ID)
+	require.Equal(t, arg.Url, subscription.Url)
+	require.Equal(t, arg.EventTypes, subscription.EventTypes)
+	require.Equal(t, arg.Secret, subscription.Secret)
+
+	return subscription
+}
+
+func TestListEventWebhookSubscriptions(t *testing.T) {
+	user := createRandomUser(t)
+	entries := createRandomWebhook(t, user.Username, EventEntryCreated)
+	createRandomWebhook(t, user.Username, EventAccountCreated)
+
+	subscriptions, err := testQueries.ListEventWebhookSubscriptions(context.Background(), ListEventWebhookSubscriptionsParams{
+		Owner:     user.Username,
+		EventType: EventEntryCreated,
+	})
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, entries.ID, subscriptions[0].ID)
+}
+
+func TestWebhookDeliveryLifecycle(t *testing.T) {
+	user := createRandomUser(t)
+	subscription := createRandomWebhook(t, user.Username, EventUserCreated)
+
+	payload, err := json.Marshal(user.Username)
+	require.NoError(t, err)
+	event, err := testQueries.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		AggregateType: AggregateUser,
+		AggregateID:   user.Username,
+		EventType:     EventUserCreated,
+		Payload:       payload,
+	})
+	require.NoError(t, err)
+
+	arg := CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventID:        sql.NullInt64{Int64: event.ID, Valid: true},
+		EventType:      event.EventType,
+		Payload:        payload,
+	}
+	delivery, err := testQueries.CreateWebhookDelivery(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, "pending", delivery.Status)
+
+	// Queuing the same event again is a no-op.
+	_, err = testQueries.CreateWebhookDelivery(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	var claimed []ClaimWebhookDeliveriesRow
+	for i := 0; i < 100; i++ {
+		rows, err := testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+			LeaseUntil: time.Now().Add(time.Minute),
+			Limit:      1000,
+		})
+		require.NoError(t, err)
+		claimed = append(claimed, rows...)
+		if len(rows) == 0 {
+			break
+		}
+	}
+
+	var row ClaimWebhookDeliveriesRow
+	for _, r := range claimed {
+		if r.ID == delivery.ID {
+			row = r
+		}
+	}
+	require.Equal(t, subscription.Url, row.Url)
+	require.Equal(t, subscription.Secret, row.Secret)
+
+	leased, err := testQueries.GetWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.True(t, leased.NextAttemptAt.After(time.Now()))
+
+	delivered, err := testQueries.RecordWebhookAttempt(context.Background(), RecordWebhookAttemptParams{
+		ID:             delivery.ID,
+		Status:         "succeeded",
+		ResponseStatus: sql.NullInt32{Int32: 204, Valid: true},
+		NextAttemptAt:  time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), delivered.Attempts)
+	require.True(t, delivered.DeliveredAt.Valid)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "succeeded", deliveries[0].Status)
+}
+
+func TestCreateLeasedWebhookDelivery(t *testing.T) {
+	user := createRandomUser(t)
+	subscription := createRandomWebhook(t, user.Username, EventUserCreated)
+
+	leaseUntil := time.Now().Add(time.Minute)
+	delivery, err := testQueries.CreateWebhookDelivery(context.Background(), CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventType:      "webhook.test",
+		Payload:        json.RawMessage(`{}`),
+		NextAttemptAt:  sql.NullTime{Time: leaseUntil, Valid: true},
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, leaseUntil, delivery.NextAttemptAt, time.Second)
+
+	// The dispatcher doesn't claim a delivery before its lease runs out.
+	rows, err := testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(time.Minute),
+		Limit:      1000,
+	})
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NotEqual(t, delivery.ID, row.ID)
+	}
+}
+
+func TestTransferTXEntryEvents(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	for _, entry := range []Entry{result.FromEntry, result.ToEntry} {
+		events := listAggregateEvents(t, AggregateAccount, strconv.FormatInt(entry.AccountID, 10))
+		require.NotEmpty(t, events)
+
+		last := events[len(events)-1]
+		require.Equal(t, EventEntryCreated, last.EventType)
+
+		var got Entry
+		require.NoError(t, json.Unmarshal(last.Payload, &got))
+		require.Equal(t, entry.ID, got.ID)
+	}
+}
@@ -0,0 +1,360 @@
+// Package importer loads the accounts of the old core together with their
+// transaction history. Both files are checked in full before anything is
+// written, then accounts are imported in chunks, each chunk in its own
+// transaction. Accounts remember their legacy id, so a failed import resumes
+// where it stopped when it's run again.
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+)
+
+// DefaultChunkSize is the number of accounts imported per transaction.
+const DefaultChunkSize = 100
+
+const dateLayout = "2006-01-02"
+
+// Column names of the two files, each file starts with a header naming its
+// columns in any order.
+var (
+	accountColumns = []string{"legacy_id", "owner", "currency", "balance", "created_at"}
+	entryColumns   = []string{"account_legacy_id", "amount", "created_at", "description", "reference"}
+)
+
+const (
+	FileAccounts = "accounts"
+	FileEntries  = "entries"
+)
+
+type Options struct {
+	// DryRun checks the files against the database and reports what would
+	// be imported without writing anything.
+	DryRun    bool
+	ChunkSize int
+}
+
+// Problem is something wrong with the files that stops the import. Line is
+// the line of the file it was found on, zero for problems of an account as
+// a whole.
+type Problem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	LegacyID string `json:"legacy_id,omitempty"`
+	Message  string `json:"message"`
+}
+
+type Report struct {
+	DryRun   bool `json:"dry_run"`
+	Accounts int  `json:"accounts"`
+	Entries  int  `json:"entries"`
+	// Imported and ImportedEntries count what this run imported, or would
+	// import on a dry run.
+	Imported        int `json:"imported"`
+	ImportedEntries int `json:"imported_entries"`
+	// Skipped lists accounts imported by an earlier run.
+	Skipped  []string  `json:"skipped"`
+	Problems []Problem `json:"problems"`
+	// Error is why the import stopped part way, the accounts imported
+	// before it are kept.
+	Error string `json:"error,omitempty"`
+}
+
+// Clean reports whether the import went, or on a dry run would go, through
+// without problems.
+func (report Report) Clean() bool {
+	return len(report.Problems) == 0 && report.Error == ""
+}
+
+// Import reads the accounts and entries files and imports them. Nothing is
+// imported while the report lists problems. The report is always returned,
+// the error only when the import couldn't be carried out.
+func Import(ctx context.Context, store db.Store, accounts, entries io.Reader, opts Options) (Report, error) {
+	report := Report{DryRun: opts.DryRun}
+
+	parsed, problems, err := read(accounts, entries, time.Now())
+	if err != nil {
+		return report, err
+	}
+	report.Problems = problems
+	report.Accounts = len(parsed)
+	for _, account := range parsed {
+		report.Entries += len(account.Entries)
+	}
+
+	pending, problems, err := check(ctx, store, parsed, &report)
+	if err != nil {
+		return report, err
+	}
+	report.Problems = append(report.Problems, problems...)
+
+	if !opts.DryRun && len(report.Problems) > 0 {
+		return report, nil
+	}
+	if opts.DryRun {
+		report.Imported = len(pending)
+		for _, account := range pending {
+			report.ImportedEntries += len(account.Entries)
+		}
+		return report, nil
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	for start := 0; start < len(pending); start += chunkSize {
+		end := start + chunkSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		result, err := store.ImportAccountsTX(ctx, db.ImportAccountsTxParams{Accounts: pending[start:end]})
+		if err != nil {
+			report.Error = err.Error()
+			return report, fmt.Errorf("import accounts %s to %s: %w", pending[start].LegacyID, pending[end-1].LegacyID, err)
+		}
+		report.Imported += len(result.Imported)
+		report.ImportedEntries += result.Entries
+		report.Skipped = append(report.Skipped, result.Skipped...)
+	}
+
+	return report, nil
+}
+
+// check compares the accounts with the database, returning the ones still
+// to import. Accounts imported by an earlier run are added to the report as
+// skipped.
+func check(ctx context.Context, store db.Store, accounts []db.ImportAccount, report *Report) ([]db.ImportAccount, []Problem, error) {
+	var pending []db.ImportAccount
+	var problems []Problem
+	owners := make(map[string]bool)
+	currencies := make(map[string]bool)
+
+	for _, account := range accounts {
+		_, err := store.GetLegacyAccount(ctx, account.LegacyID)
+		if err == nil {
+			report.Skipped = append(report.Skipped, account.LegacyID)
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, nil, err
+		}
+
+		problem := func(format string, args ...any) {
+			problems = append(problems, Problem{File: FileAccounts, LegacyID: account.LegacyID, Message: fmt.Sprintf(format, args...)})
+		}
+
+		exists, ok := owners[account.Owner]
+		if !ok {
+			_, err := store.GetUser(ctx, account.Owner)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, nil, err
+			}
+			exists = err == nil
+			owners[account.Owner] = exists
+		}
+		if !exists {
+			problem("owner %s doesn't exist", account.Owner)
+		}
+
+		// Legacy accounts may be in a currency that is disabled by now, it
+		// only has to be in the catalog.
+		known, ok := currencies[account.Currency]
+		if !ok {
+			_, err := store.GetCurrency(ctx, account.Currency)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, nil, err
+			}
+			known = err == nil
+			currencies[account.Currency] = known
+		}
+		if !known {
+			problem("currency %s isn't in the currency catalog", account.Currency)
+		}
+
+		existing, err := store.GetAccountByOwner(ctx, db.GetAccountByOwnerParams{Owner: account.Owner, Currency: account.Currency})
+		if err == nil {
+			problem("owner %s already has %s account %d", account.Owner, account.Currency, existing.ID)
+		} else if err != sql.ErrNoRows {
+			return nil, nil, err
+		}
+
+		pending = append(pending, account)
+	}
+
+	return pending, problems, nil
+}
+
+// read parses both files. Entries are attached to their account in the
+// order they were booked.
+func read(accountsFile, entriesFile io.Reader, now time.Time) ([]db.ImportAccount, []Problem, error) {
+	var problems []Problem
+	var accounts []db.ImportAccount
+	index := make(map[string]int)
+	ownerCurrencies := make(map[string]string)
+
+	err := readCSV(FileAccounts, accountsFile, accountColumns, func(line int, row map[string]string) {
+		problem := func(format string, args ...any) {
+			problems = append(problems, Problem{File: FileAccounts, Line: line, LegacyID: row["legacy_id"], Message: fmt.Sprintf(format, args...)})
+		}
+
+		account := db.ImportAccount{
+			LegacyID: row["legacy_id"],
+			Owner:    row["owner"],
+			Currency: row["currency"],
+		}
+		switch {
+		case account.LegacyID == "":
+			problem("legacy_id is required")
+			return
+		case index[account.LegacyID] > 0:
+			problem("legacy_id is repeated")
+			return
+		}
+		if account.Owner == "" {
+			problem("owner is required")
+		}
+		if _, err := money.Lookup(account.Currency); err != nil {
+			problem("unsupported currency %q", account.Currency)
+		}
+		key := account.Owner + "/" + account.Currency
+		if other, ok := ownerCurrencies[key]; ok {
+			problem("owner %s already has %s account %s in the file", account.Owner, account.Currency, other)
+		}
+		ownerCurrencies[key] = account.LegacyID
+
+		var err error
+		if account.Balance, err = strconv.ParseInt(row["balance"], 10, 64); err != nil {
+			problem("invalid balance %q", row["balance"])
+		}
+		if account.CreatedAt, err = parseTime(row["created_at"]); err != nil {
+			problem("invalid created_at %q", row["created_at"])
+		} else if account.CreatedAt.After(now) {
+			problem("created_at is in the future")
+		}
+
+		accounts = append(accounts, account)
+		index[account.LegacyID] = len(accounts)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = readCSV(FileEntries, entriesFile, entryColumns, func(line int, row map[string]string) {
+		legacyID := row["account_legacy_id"]
+		problem := func(format string, args ...any) {
+			problems = append(problems, Problem{File: FileEntries, Line: line, LegacyID: legacyID, Message: fmt.Sprintf(format, args...)})
+		}
+
+		i := index[legacyID]
+		if i == 0 {
+			problem("unknown account %q", legacyID)
+			return
+		}
+		account := &accounts[i-1]
+
+		entry := db.ImportEntry{
+			Description:       row["description"],
+			ExternalReference: row["reference"],
+		}
+		var err error
+		if entry.Amount, err = strconv.ParseInt(row["amount"], 10, 64); err != nil || entry.Amount == 0 {
+			problem("invalid amount %q", row["amount"])
+			return
+		}
+		if entry.CreatedAt, err = parseTime(row["created_at"]); err != nil {
+			problem("invalid created_at %q", row["created_at"])
+			return
+		}
+		if entry.CreatedAt.Before(account.CreatedAt) {
+			problem("booked before its account was opened")
+		} else if entry.CreatedAt.After(now) {
+			problem("created_at is in the future")
+		}
+
+		account.Entries = append(account.Entries, entry)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range accounts {
+		account := &accounts[i]
+		sort.SliceStable(account.Entries, func(a, b int) bool {
+			return account.Entries[a].CreatedAt.Before(account.Entries[b].CreatedAt)
+		})
+
+		var total int64
+		for _, entry := range account.Entries {
+			total += entry.Amount
+		}
+		if total != account.Balance {
+			err := &db.ImportUnbalancedError{LegacyID: account.LegacyID, Balance: account.Balance, Total: total}
+			problems = append(problems, Problem{File: FileAccounts, LegacyID: account.LegacyID, Message: err.Error()})
+		}
+	}
+
+	return accounts, problems, nil
+}
+
+// readCSV calls fn with every row of a file, keyed by column name. Line
+// numbers count the header as line 1.
+func readCSV(name string, r io.Reader, columns []string, fn func(line int, row map[string]string)) error {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return fmt.Errorf("%s file is empty", name)
+	}
+	if err != nil {
+		return fmt.Errorf("%s file: %w", name, err)
+	}
+
+	positions := make(map[string]int)
+	for i, column := range header {
+		positions[strings.TrimSpace(column)] = i
+	}
+	for _, column := range columns {
+		if _, ok := positions[column]; !ok {
+			return fmt.Errorf("%s file has no %s column", name, column)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s file: %w", name, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := make(map[string]string, len(columns))
+		for _, column := range columns {
+			row[column] = strings.TrimSpace(record[positions[column]])
+		}
+		fn(line, row)
+	}
+}
+
+// parseTime accepts RFC 3339 timestamps and dates, a date is midnight UTC.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(dateLayout, value)
+}
@@ -0,0 +1,231 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccounts = `legacy_id,owner,currency,balance,created_at
+A1,alice,USD,150,2019-05-01
+A2,alice,EUR,0,2019-05-01T10:00:00Z
+B1,bob,USD,-20,2020-01-15
+`
+
+// Entries are out of order on purpose, and the columns aren't in the order
+// the import lists them.
+const testEntries = `amount,account_legacy_id,created_at,description,reference
+-50,A1,2019-06-01T09:00:00Z,"card payment, shop",old-77
+200,A1,2019-05-02,opening deposit,
+-20,B1,2020-02-01,fee,
+`
+
+func expectChecks(store *mockdb.MockStore, imported ...string) {
+	for _, id := range imported {
+		store.EXPECT().GetLegacyAccount(gomock.Any(), gomock.Eq(id)).Times(1).Return(db.LegacyAccount{LegacyID: id}, nil)
+	}
+	store.EXPECT().GetLegacyAccount(gomock.Any(), gomock.Any()).AnyTimes().Return(db.LegacyAccount{}, sql.ErrNoRows)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().Return(db.User{}, nil)
+	store.EXPECT().GetCurrency(gomock.Any(), gomock.Any()).AnyTimes().Return(db.Currency{Enabled: true}, nil)
+	store.EXPECT().GetAccountByOwner(gomock.Any(), gomock.Any()).AnyTimes().Return(db.Account{}, sql.ErrNoRows)
+}
+
+func TestRead(t *testing.T) {
+	accounts, problems, err := read(strings.NewReader(testAccounts), strings.NewReader(testEntries), time.Now())
+	require.NoError(t, err)
+	require.Empty(t, problems)
+	require.Len(t, accounts, 3)
+
+	a1 := accounts[0]
+	require.Equal(t, "A1", a1.LegacyID)
+	require.Equal(t, "alice", a1.Owner)
+	require.Equal(t, int64(150), a1.Balance)
+	require.Equal(t, time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), a1.CreatedAt)
+	require.Equal(t, []db.ImportEntry{
+		{Amount: 200, CreatedAt: time.Date(2019, 5, 2, 0, 0, 0, 0, time.UTC), Description: "opening deposit"},
+		{Amount: -50, CreatedAt: time.Date(2019, 6, 1, 9, 0, 0, 0, time.UTC), Description: "card payment, shop", ExternalReference: "old-77"},
+	}, a1.Entries)
+
+	require.Empty(t, accounts[1].Entries)
+	require.Len(t, accounts[2].Entries, 1)
+}
+
+func TestReadProblems(t *testing.T) {
+	testcase := []struct {
+		name     string
+		accounts string
+		entries  string
+		problem  Problem
+	}{
+		{
+			name:     "Unbalanced",
+			accounts: strings.Replace(testAccounts, "A1,alice,USD,150", "A1,alice,USD,151", 1),
+			entries:  testEntries,
+			problem:  Problem{File: FileAccounts, LegacyID: "A1", Message: "account A1 has balance 151 but its entries add up to 150"},
+		},
+		{
+			name:     "RepeatedLegacyID",
+			accounts: testAccounts + "A1,carol,USD,0,2019-05-01\n",
+			entries:  testEntries,
+			problem:  Problem{File: FileAccounts, Line: 5, LegacyID: "A1", Message: "legacy_id is repeated"},
+		},
+		{
+			name:     "SecondAccountInCurrency",
+			accounts: testAccounts + "A3,alice,USD,0,2019-05-01\n",
+			entries:  testEntries,
+			problem:  Problem{File: FileAccounts, Line: 5, LegacyID: "A3", Message: "owner alice already has USD account A1 in the file"},
+		},
+		{
+			name:     "Currency",
+			accounts: strings.Replace(testAccounts, "A2,alice,EUR", "A2,alice,XYZ", 1),
+			entries:  testEntries,
+			problem:  Problem{File: FileAccounts, Line: 3, LegacyID: "A2", Message: `unsupported currency "XYZ"`},
+		},
+		{
+			name:     "UnknownAccount",
+			accounts: testAccounts,
+			entries:  testEntries + "10,Z9,2020-02-01,,\n",
+			problem:  Problem{File: FileEntries, Line: 5, LegacyID: "Z9", Message: `unknown account "Z9"`},
+		},
+		{
+			name:     "Amount",
+			accounts: testAccounts,
+			entries:  testEntries + "1.50,A2,2020-02-01,,\n",
+			problem:  Problem{File: FileEntries, Line: 5, LegacyID: "A2", Message: `invalid amount "1.50"`},
+		},
+		{
+			name:     "BeforeAccountOpened",
+			accounts: strings.Replace(testAccounts, "A2,alice,EUR,0", "A2,alice,EUR,5", 1),
+			entries:  testEntries + "5,A2,2019-04-30,,\n",
+			problem:  Problem{File: FileEntries, Line: 5, LegacyID: "A2", Message: "booked before its account was opened"},
+		},
+		{
+			name:     "Future",
+			accounts: strings.Replace(testAccounts, "A2,alice,EUR,0", "A2,alice,EUR,5", 1),
+			entries:  testEntries + "5,A2,2999-01-01,,\n",
+			problem:  Problem{File: FileEntries, Line: 5, LegacyID: "A2", Message: "created_at is in the future"},
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			_, problems, err := read(strings.NewReader(tc.accounts), strings.NewReader(tc.entries), time.Now())
+			require.NoError(t, err)
+			require.Equal(t, []Problem{tc.problem}, problems)
+		})
+	}
+}
+
+func TestReadMissingColumn(t *testing.T) {
+	_, _, err := read(strings.NewReader("legacy_id,owner,currency,balance\n"), strings.NewReader(testEntries), time.Now())
+	require.EqualError(t, err, "accounts file has no created_at column")
+
+	_, _, err = read(strings.NewReader(testAccounts), strings.NewReader(""), time.Now())
+	require.EqualError(t, err, "entries file is empty")
+}
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	expectChecks(store, "A2")
+
+	// A2 was imported by an earlier run, the others go in chunks of one.
+	gomock.InOrder(
+		store.EXPECT().
+			ImportAccountsTX(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.ImportAccountsTxParams) (db.ImportAccountsTxResult, error) {
+				require.Len(t, arg.Accounts, 1)
+				require.Equal(t, "A1", arg.Accounts[0].LegacyID)
+				return db.ImportAccountsTxResult{Imported: []db.LegacyAccount{{LegacyID: "A1"}}, Entries: 2}, nil
+			}),
+		store.EXPECT().
+			ImportAccountsTX(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.ImportAccountsTxParams) (db.ImportAccountsTxResult, error) {
+				require.Equal(t, "B1", arg.Accounts[0].LegacyID)
+				return db.ImportAccountsTxResult{Imported: []db.LegacyAccount{{LegacyID: "B1"}}, Entries: 1}, nil
+			}),
+	)
+
+	report, err := Import(context.Background(), store, strings.NewReader(testAccounts), strings.NewReader(testEntries), Options{ChunkSize: 1})
+	require.NoError(t, err)
+	require.True(t, report.Clean())
+	require.Equal(t, Report{
+		Accounts:        3,
+		Entries:         3,
+		Imported:        2,
+		ImportedEntries: 3,
+		Skipped:         []string{"A2"},
+	}, report)
+}
+
+func TestImportDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	expectChecks(store)
+	store.EXPECT().ImportAccountsTX(gomock.Any(), gomock.Any()).Times(0)
+
+	report, err := Import(context.Background(), store, strings.NewReader(testAccounts), strings.NewReader(testEntries), Options{DryRun: true})
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 3, report.Imported)
+	require.Equal(t, 3, report.ImportedEntries)
+}
+
+func TestImportDatabaseProblems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetLegacyAccount(gomock.Any(), gomock.Any()).Times(3).Return(db.LegacyAccount{}, sql.ErrNoRows)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq("alice")).Times(1).Return(db.User{}, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq("bob")).Times(1).Return(db.User{}, sql.ErrNoRows)
+	store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq("USD")).Times(1).Return(db.Currency{Code: "USD"}, nil)
+	store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq("EUR")).Times(1).Return(db.Currency{}, sql.ErrNoRows)
+	store.EXPECT().
+		GetAccountByOwner(gomock.Any(), gomock.Eq(db.GetAccountByOwnerParams{Owner: "alice", Currency: "EUR"})).
+		Times(1).
+		Return(db.Account{ID: 7}, nil)
+	store.EXPECT().GetAccountByOwner(gomock.Any(), gomock.Any()).Times(2).Return(db.Account{}, sql.ErrNoRows)
+	store.EXPECT().ImportAccountsTX(gomock.Any(), gomock.Any()).Times(0)
+
+	report, err := Import(context.Background(), store, strings.NewReader(testAccounts), strings.NewReader(testEntries), Options{})
+	require.NoError(t, err)
+	require.False(t, report.Clean())
+	require.Zero(t, report.Imported)
+	require.Equal(t, []Problem{
+		{File: FileAccounts, LegacyID: "A2", Message: "currency EUR isn't in the currency catalog"},
+		{File: FileAccounts, LegacyID: "A2", Message: "owner alice already has EUR account 7"},
+		{File: FileAccounts, LegacyID: "B1", Message: "owner bob doesn't exist"},
+	}, report.Problems)
+}
+
+func TestImportChunkFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	expectChecks(store)
+	gomock.InOrder(
+		store.EXPECT().ImportAccountsTX(gomock.Any(), gomock.Any()).
+			Return(db.ImportAccountsTxResult{Imported: make([]db.LegacyAccount, 2), Entries: 2}, nil),
+		store.EXPECT().ImportAccountsTX(gomock.Any(), gomock.Any()).
+			Return(db.ImportAccountsTxResult{}, sql.ErrConnDone),
+	)
+
+	report, err := Import(context.Background(), store, strings.NewReader(testAccounts), strings.NewReader(testEntries), Options{ChunkSize: 2})
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Equal(t, 2, report.Imported)
+	require.Equal(t, sql.ErrConnDone.Error(), report.Error)
+	require.False(t, report.Clean())
+}
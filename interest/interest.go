@@ -0,0 +1,121 @@
+// Package interest computes interest on account balances. Interest accrues
+// daily on the end-of-day balance and is kept unrounded, in millionths of a
+// minor unit, until it is posted and rounded to whole minor units.
+package interest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// MicrosPerUnit is the number of accrual units, micros, in a minor unit.
+const MicrosPerUnit = 1_000_000
+
+// ErrOverflow is returned when interest doesn't fit in an int64.
+var ErrOverflow = errors.New("interest overflows")
+
+// DayCount is a day count convention, deciding the fraction of the annual
+// rate a single day earns.
+type DayCount string
+
+const (
+	// Actual365 counts every year as 365 days.
+	Actual365 DayCount = "act/365"
+	// Actual360 counts every year as 360 days, so a year of days earns a
+	// little more than the annual rate.
+	Actual360 DayCount = "act/360"
+	// ActualActual counts the days of the actual year, 365 or 366.
+	ActualActual DayCount = "act/act"
+	// Thirty360 counts every month as 30 days of a 360 day year. The 31st
+	// earns nothing and the last day of February earns the days up to the
+	// 30th.
+	Thirty360 DayCount = "30/360"
+)
+
+// Rounding is how accrued interest is rounded to minor units when it is
+// posted.
+type Rounding string
+
+const (
+	HalfUp   Rounding = "half_up"
+	HalfEven Rounding = "half_even"
+	// Down truncates, never paying out a fraction of a minor unit.
+	Down Rounding = "down"
+)
+
+// Days returns the days of the year day earns and the days the year is
+// counted as.
+func (dc DayCount) Days(day time.Time) (days, year int64, err error) {
+	day = day.UTC()
+	switch dc {
+	case Actual365:
+		return 1, 365, nil
+	case Actual360:
+		return 1, 360, nil
+	case ActualActual:
+		if isLeap(day.Year()) {
+			return 1, 366, nil
+		}
+		return 1, 365, nil
+	case Thirty360:
+		switch {
+		case day.Day() == 31:
+			return 0, 360, nil
+		case day.Month() == time.February && day.AddDate(0, 0, 1).Month() != time.February:
+			return int64(31 - day.Day()), 360, nil
+		default:
+			return 1, 360, nil
+		}
+	default:
+		return 0, 0, fmt.Errorf("unknown day count convention %q", dc)
+	}
+}
+
+// Daily returns the interest, in micros, balance earns over day at an annual
+// rate in basis points. It is truncated to whole micros.
+func Daily(balance int64, rateBps int32, dc DayCount, day time.Time) (int64, error) {
+	days, year, err := dc.Days(day)
+	if err != nil {
+		return 0, err
+	}
+
+	// balance * rate/10000 * days/year in minor units, times a million.
+	micros := new(big.Int).SetInt64(balance)
+	micros.Mul(micros, big.NewInt(int64(rateBps)))
+	micros.Mul(micros, big.NewInt(days*MicrosPerUnit))
+	micros.Quo(micros, big.NewInt(10000*year))
+	if !micros.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return micros.Int64(), nil
+}
+
+// Round converts micros to minor units.
+func (rounding Rounding) Round(micros int64) (int64, error) {
+	units, rest := micros/MicrosPerUnit, micros%MicrosPerUnit
+	sign := int64(1)
+	if rest < 0 {
+		sign, rest = -1, -rest
+	}
+
+	switch rounding {
+	case Down:
+	case HalfUp:
+		if rest >= MicrosPerUnit/2 {
+			units += sign
+		}
+	case HalfEven:
+		if rest > MicrosPerUnit/2 || (rest == MicrosPerUnit/2 && units%2 != 0) {
+			units += sign
+		}
+	default:
+		return 0, fmt.Errorf("unknown rounding %q", rounding)
+	}
+	return units, nil
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,112 @@
+package interest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// yearDays sums the days every day of a year earns.
+func yearDays(t *testing.T, dc DayCount, year int) (days, length int64) {
+	for day := date(year, time.January, 1); day.Year() == year; day = day.AddDate(0, 0, 1) {
+		d, l, err := dc.Days(day)
+		require.NoError(t, err)
+		days += d
+		length = l
+	}
+	return days, length
+}
+
+func TestDays(t *testing.T) {
+	testCases := []struct {
+		dc           DayCount
+		year         int
+		days, length int64
+	}{
+		{Actual365, 2023, 365, 365},
+		{Actual365, 2024, 366, 365},
+		{Actual360, 2023, 365, 360},
+		{ActualActual, 2023, 365, 365},
+		{ActualActual, 2024, 366, 366},
+		{Thirty360, 2023, 360, 360},
+		{Thirty360, 2024, 360, 360},
+	}
+
+	for _, tc := range testCases {
+		days, length := yearDays(t, tc.dc, tc.year)
+		require.Equal(t, tc.days, days, "%s %d", tc.dc, tc.year)
+		require.Equal(t, tc.length, length, "%s %d", tc.dc, tc.year)
+	}
+
+	_, _, err := DayCount("act/364").Days(date(2024, time.January, 1))
+	require.Error(t, err)
+}
+
+func TestThirty360Months(t *testing.T) {
+	for _, tc := range []struct {
+		day  time.Time
+		days int64
+	}{
+		{date(2023, time.January, 30), 1},
+		{date(2023, time.January, 31), 0},
+		{date(2023, time.February, 27), 1},
+		{date(2023, time.February, 28), 3},
+		{date(2024, time.February, 28), 1},
+		{date(2024, time.February, 29), 2},
+		{date(2024, time.April, 30), 1},
+	} {
+		days, _, err := Thirty360.Days(tc.day)
+		require.NoError(t, err)
+		require.Equal(t, tc.days, days, tc.day.Format("2006-01-02"))
+	}
+}
+
+func TestDaily(t *testing.T) {
+	// 3.65% on 1000.00 is 0.10 a day under act/365.
+	micros, err := Daily(100000, 365, Actual365, date(2023, time.March, 1))
+	require.NoError(t, err)
+	require.Equal(t, int64(10*MicrosPerUnit), micros)
+
+	// 1.00% on 0.01 a day is far below a cent, but still accrues.
+	micros, err = Daily(1, 100, Actual360, date(2023, time.March, 1))
+	require.NoError(t, err)
+	require.Equal(t, int64(27), micros)
+
+	micros, err = Daily(100000, 365, Thirty360, date(2023, time.March, 31))
+	require.NoError(t, err)
+	require.Zero(t, micros)
+
+	_, err = Daily(1<<62, 10000, Actual360, date(2023, time.March, 1))
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestRound(t *testing.T) {
+	testCases := []struct {
+		micros                 int64
+		halfUp, halfEven, down int64
+	}{
+		{0, 0, 0, 0},
+		{1_499_999, 1, 1, 1},
+		{1_500_000, 2, 2, 1},
+		{2_500_000, 3, 2, 2},
+		{2_500_001, 3, 3, 2},
+		{-2_500_000, -3, -2, -2},
+		{999_999, 1, 1, 0},
+	}
+
+	for _, tc := range testCases {
+		for rounding, want := range map[Rounding]int64{HalfUp: tc.halfUp, HalfEven: tc.halfEven, Down: tc.down} {
+			got, err := rounding.Round(tc.micros)
+			require.NoError(t, err)
+			require.Equal(t, want, got, "%s %d", rounding, tc.micros)
+		}
+	}
+
+	_, err := Rounding("ceiling").Round(1)
+	require.Error(t, err)
+}
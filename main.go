@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"time"
 
 	_ "github.com/lib/pq"
 
 	"github.com/aryan-more/simple_bank/api"
 	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/importer"
 	"github.com/aryan-more/simple_bank/util"
+	"github.com/aryan-more/simple_bank/webhook"
+	"github.com/aryan-more/simple_bank/worker"
 )
 
 func main() {
@@ -25,12 +35,64 @@ func main() {
 		log.Println("Connected to db")
 	}
 
-	store := db.NewStore(conn)
+	isolation, err := db.ParseIsolationLevel(config.DBIsolationLevel)
+	if err != nil {
+		log.Fatal("Invalid DB_ISOLATION_LEVEL:", err)
+	}
+
+	store := db.NewStore(conn,
+		db.WithIsolationLevel(isolation),
+		db.WithMaxRetries(config.DBTxMaxRetries),
+	)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			os.Exit(reconcile(store))
+		case "import":
+			os.Exit(importHistory(store, os.Args[2:]))
+		default:
+			log.Fatalf("Unknown command %s", os.Args[1])
+		}
+	}
+
+	go expireApprovals(store, time.Minute)
+	if config.ReconcileInterval > 0 {
+		go reconcileLedger(store, config.ReconcileInterval)
+	}
+	if config.SnapshotInterval > 0 {
+		go snapshotBalances(store, config.SnapshotInterval)
+	}
+	if config.InterestInterval > 0 {
+		go accrueInterest(store, config.InterestInterval)
+	}
+
+	sink, err := newOutboxSink(config)
+	if err != nil {
+		log.Fatal("Cannot create outbox sink:", err)
+	}
+	sinks := worker.MultiSink{webhook.NewFanout(store)}
+	if sink != nil {
+		sinks = append(sinks, sink)
+	}
+	relay := worker.NewRelay(store, sinks, config.OutboxPollInterval, config.OutboxBatchSize)
+	go relay.Run(context.Background())
+
+	deliverer := webhook.NewDeliverer(store, webhook.NewClient(config.WebhookTimeout),
+		config.WebhookMaxAttempts, config.WebhookBackoff, config.WebhookMaxBackoff)
+	dispatcher := webhook.NewDispatcher(store, deliverer, config.WebhookPollInterval, config.WebhookBatchSize, deliverer.Lease())
+	go dispatcher.Run(context.Background())
 
 	server, err := api.NewServer(store, config)
 	if err != nil {
 		log.Fatalf("Failed to create server %s", err.Error())
 	}
+	if err := server.RefreshCurrencies(context.Background()); err != nil {
+		log.Fatal("Cannot load currencies:", err)
+	}
+	if config.CurrencyRefresh > 0 {
+		go refreshCurrencies(server, config.CurrencyRefresh)
+	}
 
 	err = server.Start(config.Address)
 	if err != nil {
@@ -39,3 +101,176 @@ func main() {
 		log.Panicln("Server Started")
 	}
 }
+
+// expireApprovals periodically marks pending transfer approvals past their
+// expiry time as expired.
+func expireApprovals(store db.Store, interval time.Duration) {
+	for range time.Tick(interval) {
+		expired, err := store.ExpireTransferApprovals(context.Background())
+		if err != nil {
+			log.Println("Cannot expire transfer approvals:", err)
+			continue
+		}
+		if expired > 0 {
+			log.Printf("Expired %d transfer approvals", expired)
+		}
+	}
+}
+
+// refreshCurrencies periodically reloads the currency catalog, picking up
+// changes made through other servers.
+func refreshCurrencies(server *api.Server, interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := server.RefreshCurrencies(context.Background()); err != nil {
+			log.Println("Cannot refresh currencies:", err)
+		}
+	}
+}
+
+// accrueInterest accrues interest for the days snapshotted since its last
+// run and posts the months accrued to their end. Like the snapshots, it runs
+// more often than daily to catch up soon after a missed run.
+func accrueInterest(store db.Store, interval time.Duration) {
+	for range time.Tick(interval) {
+		accrued, err := store.AccrueInterestTX(context.Background())
+		if err != nil {
+			log.Println("Cannot accrue interest:", err)
+			continue
+		}
+		if accrued.Days > 0 {
+			log.Printf("Accrued %d interest accruals over %d days", accrued.Accruals, accrued.Days)
+		}
+
+		posted, err := store.PostInterestTX(context.Background())
+		if err != nil {
+			log.Println("Cannot post interest:", err)
+			continue
+		}
+		if posted.Postings > 0 {
+			log.Printf("Posted interest of %d account months in %d journals", posted.Postings, len(posted.Journals))
+		}
+	}
+}
+
+// reconcile runs a single reconciliation and prints its report. The exit code
+// is 1 when the ledger has problems, so it can fail a cron job or CI step.
+func reconcile(store db.Store) int {
+	run, report, err := store.ReconcileTX(context.Background())
+	if err != nil {
+		log.Println("Cannot reconcile ledger:", err)
+		return 2
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(run); err != nil {
+		log.Println("Cannot print reconciliation report:", err)
+		return 2
+	}
+
+	if !report.Clean() {
+		return 1
+	}
+	return 0
+}
+
+// importHistory imports accounts and their entries from the old core and
+// prints the import report:
+//
+//	simple_bank import [-dry-run] [-chunk-size n] accounts.csv entries.csv
+//
+// The exit code is 1 when the files have problems, 2 when the import failed.
+// A failed import is resumed by running it again with the same files.
+func importHistory(store db.Store, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "check the files and report what would be imported")
+	chunkSize := flags.Int("chunk-size", importer.DefaultChunkSize, "accounts imported per transaction")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		log.Println("Usage: import [-dry-run] [-chunk-size n] accounts.csv entries.csv")
+		return 2
+	}
+
+	accounts, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Println("Cannot open accounts file:", err)
+		return 2
+	}
+	defer accounts.Close()
+
+	entries, err := os.Open(flags.Arg(1))
+	if err != nil {
+		log.Println("Cannot open entries file:", err)
+		return 2
+	}
+	defer entries.Close()
+
+	report, importErr := importer.Import(context.Background(), store, accounts, entries, importer.Options{
+		DryRun:    *dryRun,
+		ChunkSize: *chunkSize,
+	})
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Println("Cannot print import report:", err)
+		return 2
+	}
+
+	if importErr != nil {
+		log.Println("Cannot import history:", importErr)
+		return 2
+	}
+	if !report.Clean() {
+		return 1
+	}
+	return 0
+}
+
+// reconcileLedger periodically checks the ledger and logs any drift found.
+func reconcileLedger(store db.Store, interval time.Duration) {
+	for range time.Tick(interval) {
+		run, report, err := store.ReconcileTX(context.Background())
+		if err != nil {
+			log.Println("Cannot reconcile ledger:", err)
+			continue
+		}
+		if !report.Clean() {
+			log.Printf("Reconciliation %d found %d drifted accounts and %d broken transfers",
+				run.ID, run.DriftedAccounts, run.TransferIssues)
+		}
+	}
+}
+
+// snapshotBalances takes the daily balance snapshots. It runs more often than
+// daily so a restart or failed run doesn't hold a day back for long.
+func snapshotBalances(store db.Store, interval time.Duration) {
+	for range time.Tick(interval) {
+		result, err := store.SnapshotBalancesTX(context.Background(), time.Now())
+		if err != nil {
+			log.Println("Cannot snapshot balances:", err)
+			continue
+		}
+		if result.Days > 0 {
+			log.Printf("Snapshotted %d balances over %d days", result.Snapshots, result.Days)
+		}
+	}
+}
+
+// newOutboxSink picks where outbox events are published besides webhooks,
+// "none" only feeds webhooks.
+func newOutboxSink(config util.Config) (worker.Sink, error) {
+	switch config.OutboxSink {
+	case "none":
+		return nil, nil
+	case "", "log":
+		return worker.NewLogSink(log.New(os.Stdout, "", log.LstdFlags)), nil
+	case "http":
+		if config.OutboxURL == "" {
+			return nil, fmt.Errorf("OUTBOX_URL is required for the http sink")
+		}
+		return worker.NewHTTPSink(config.OutboxURL, &http.Client{Timeout: 10 * time.Second}), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox sink %q", config.OutboxSink)
+	}
+}
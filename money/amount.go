@@ -0,0 +1,98 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errAmountJSON = errors.New("amount must be a whole number of minor units or a decimal string")
+
+// Amount is an amount in an API request. A JSON number is a count of minor
+// units, the way the API has always taken amounts, while a JSON string is a
+// decimal in major units such as "12.34". The currency deciding the scale
+// usually comes in another field of the request, so an Amount is resolved
+// with In once it is known.
+type Amount struct {
+	minor   int64
+	decimal string
+}
+
+// MinorUnits returns an amount of minor units.
+func MinorUnits(amount int64) Amount {
+	return Amount{minor: amount}
+}
+
+// IsDecimal reports whether the amount was given as a decimal, so it needs
+// its currency to be resolved.
+func (a Amount) IsDecimal() bool {
+	return a.decimal != ""
+}
+
+// Sign returns -1, 0 or 1 by the sign of the amount. It's all validation
+// needs to know before the currency is.
+func (a Amount) Sign() int64 {
+	if !a.IsDecimal() {
+		switch {
+		case a.minor < 0:
+			return -1
+		case a.minor > 0:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	negative, whole, fraction, _ := splitDecimal(a.decimal)
+	switch {
+	case strings.Trim(whole+fraction, "0") == "":
+		return 0
+	case negative:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// In resolves the amount in the given currency.
+func (a Amount) In(currency Currency) (Money, error) {
+	if !a.IsDecimal() {
+		return New(a.minor, currency), nil
+	}
+	return Parse(a.decimal, currency)
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if strings.HasPrefix(string(data), `"`) {
+		var decimal string
+		if err := json.Unmarshal(data, &decimal); err != nil {
+			return err
+		}
+		// The scale is checked once the currency is known, the syntax can
+		// be checked right away.
+		if _, _, _, ok := splitDecimal(decimal); !ok {
+			return fmt.Errorf("%w %q: must be a decimal number like 12.34", ErrInvalidAmount, decimal)
+		}
+		*a = Amount{decimal: decimal}
+		return nil
+	}
+
+	var minor int64
+	if err := json.Unmarshal(data, &minor); err != nil {
+		return errAmountJSON
+	}
+	*a = MinorUnits(minor)
+	return nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	if a.IsDecimal() {
+		return json.Marshal(a.decimal)
+	}
+	return json.Marshal(a.minor)
+}
@@ -0,0 +1,65 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountJSON(t *testing.T) {
+	testcase := []struct {
+		json    string
+		decimal bool
+		sign    int64
+		usd     int64
+		jpy     int64
+		jpyErr  bool
+	}{
+		{json: `1234`, sign: 1, usd: 1234, jpy: 1234},
+		{json: `-5`, sign: -1, usd: -5, jpy: -5},
+		{json: `0`, sign: 0},
+		{json: `"12.34"`, decimal: true, sign: 1, usd: 1234, jpyErr: true},
+		{json: `"12"`, decimal: true, sign: 1, usd: 1200, jpy: 12},
+		{json: `"-0.50"`, decimal: true, sign: -1, usd: -50, jpyErr: true},
+		{json: `"0.00"`, decimal: true, sign: 0},
+	}
+
+	for _, tc := range testcase {
+		var body struct {
+			Amount Amount `json:"amount"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"amount":`+tc.json+`}`), &body), tc.json)
+
+		amount := body.Amount
+		require.Equal(t, tc.decimal, amount.IsDecimal(), tc.json)
+		require.Equal(t, tc.sign, amount.Sign(), tc.json)
+
+		m, err := amount.In(usd)
+		require.NoError(t, err)
+		require.Equal(t, tc.usd, m.Amount(), tc.json)
+
+		m, err = amount.In(jpy)
+		if tc.jpyErr {
+			require.ErrorIs(t, err, ErrInvalidAmount, tc.json)
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, tc.jpy, m.Amount(), tc.json)
+		}
+
+		data, err := json.Marshal(amount)
+		require.NoError(t, err)
+		require.Equal(t, tc.json, string(data))
+	}
+}
+
+func TestAmountJSONInvalid(t *testing.T) {
+	for _, value := range []string{`1.5`, `"1,5"`, `"abc"`, `""`, `true`, `{}`} {
+		var amount Amount
+		require.Error(t, json.Unmarshal([]byte(value), &amount), value)
+	}
+
+	amount := MinorUnits(7)
+	require.NoError(t, json.Unmarshal([]byte(`null`), &amount))
+	require.Equal(t, MinorUnits(7), amount)
+}
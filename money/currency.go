@@ -0,0 +1,120 @@
+// Package money handles amounts of money. Amounts are kept as a whole number
+// of the currency's minor unit, e.g. cents, and only become decimals at the
+// edges where they are read or shown.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownCurrency is returned for codes that aren't in the registry.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Currency is an ISO 4217 currency.
+type Currency struct {
+	Code string `json:"code"`
+	// Numeric is the three digit numeric code, e.g. "840" for USD.
+	Numeric string `json:"numeric"`
+	// MinorUnits is the number of decimal places of the minor unit, 2 for
+	// cents and 0 for currencies without one.
+	MinorUnits int    `json:"minor_units"`
+	Name       string `json:"name"`
+}
+
+// registry holds the ISO 4217 currencies money knows about. Whether accounts
+// can be held in a currency is decided elsewhere, this only says how its
+// amounts are written.
+var registry = map[string]Currency{}
+
+func init() {
+	for _, currency := range []Currency{
+		{"AED", "784", 2, "UAE Dirham"},
+		{"ARS", "032", 2, "Argentine Peso"},
+		{"AUD", "036", 2, "Australian Dollar"},
+		{"BDT", "050", 2, "Taka"},
+		{"BGN", "975", 2, "Bulgarian Lev"},
+		{"BHD", "048", 3, "Bahraini Dinar"},
+		{"BRL", "986", 2, "Brazilian Real"},
+		{"CAD", "124", 2, "Canadian Dollar"},
+		{"CHF", "756", 2, "Swiss Franc"},
+		{"CLF", "990", 4, "Unidad de Fomento"},
+		{"CLP", "152", 0, "Chilean Peso"},
+		{"CNY", "156", 2, "Yuan Renminbi"},
+		{"COP", "170", 2, "Colombian Peso"},
+		{"CZK", "203", 2, "Czech Koruna"},
+		{"DKK", "208", 2, "Danish Krone"},
+		{"EGP", "818", 2, "Egyptian Pound"},
+		{"EUR", "978", 2, "Euro"},
+		{"GBP", "826", 2, "Pound Sterling"},
+		{"HKD", "344", 2, "Hong Kong Dollar"},
+		{"HUF", "348", 2, "Forint"},
+		{"IDR", "360", 2, "Rupiah"},
+		{"ILS", "376", 2, "New Israeli Sheqel"},
+		{"INR", "356", 2, "Indian Rupee"},
+		{"IQD", "368", 3, "Iraqi Dinar"},
+		{"ISK", "352", 0, "Iceland Krona"},
+		{"JOD", "400", 3, "Jordanian Dinar"},
+		{"JPY", "392", 0, "Yen"},
+		{"KES", "404", 2, "Kenyan Shilling"},
+		{"KRW", "410", 0, "Won"},
+		{"KWD", "414", 3, "Kuwaiti Dinar"},
+		{"LYD", "434", 3, "Libyan Dinar"},
+		{"MAD", "504", 2, "Moroccan Dirham"},
+		{"MXN", "484", 2, "Mexican Peso"},
+		{"MYR", "458", 2, "Malaysian Ringgit"},
+		{"NGN", "566", 2, "Naira"},
+		{"NOK", "578", 2, "Norwegian Krone"},
+		{"NZD", "554", 2, "New Zealand Dollar"},
+		{"OMR", "512", 3, "Rial Omani"},
+		{"PEN", "604", 2, "Sol"},
+		{"PHP", "608", 2, "Philippine Peso"},
+		{"PKR", "586", 2, "Pakistan Rupee"},
+		{"PLN", "985", 2, "Zloty"},
+		{"QAR", "634", 2, "Qatari Rial"},
+		{"RON", "946", 2, "Romanian Leu"},
+		{"SAR", "682", 2, "Saudi Riyal"},
+		{"SEK", "752", 2, "Swedish Krona"},
+		{"SGD", "702", 2, "Singapore Dollar"},
+		{"THB", "764", 2, "Baht"},
+		{"TND", "788", 3, "Tunisian Dinar"},
+		{"TRY", "949", 2, "Turkish Lira"},
+		{"TWD", "901", 2, "New Taiwan Dollar"},
+		{"UAH", "980", 2, "Hryvnia"},
+		{"UGX", "800", 0, "Uganda Shilling"},
+		{"USD", "840", 2, "US Dollar"},
+		{"UYU", "858", 2, "Peso Uruguayo"},
+		{"VND", "704", 0, "Dong"},
+		{"XAF", "950", 0, "CFA Franc BEAC"},
+		{"XOF", "952", 0, "CFA Franc BCEAO"},
+		{"ZAR", "710", 2, "Rand"},
+	} {
+		registry[currency.Code] = currency
+	}
+}
+
+// Lookup returns the currency with the given alphabetic code.
+func Lookup(code string) (Currency, error) {
+	currency, ok := registry[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w %q", ErrUnknownCurrency, code)
+	}
+	return currency, nil
+}
+
+// Currencies returns every currency in the registry, ordered by code.
+func Currencies() []Currency {
+	currencies := make([]Currency, 0, len(registry))
+	for _, currency := range registry {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+	return currencies
+}
+
+func (currency Currency) String() string {
+	return currency.Code
+}
@@ -0,0 +1,42 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	usd, err := Lookup("USD")
+	require.NoError(t, err)
+	require.Equal(t, Currency{Code: "USD", Numeric: "840", MinorUnits: 2, Name: "US Dollar"}, usd)
+
+	jpy, err := Lookup("JPY")
+	require.NoError(t, err)
+	require.Zero(t, jpy.MinorUnits)
+
+	kwd, err := Lookup("KWD")
+	require.NoError(t, err)
+	require.Equal(t, 3, kwd.MinorUnits)
+
+	_, err = Lookup("usd")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+	_, err = Lookup("XYZ")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestCurrencies(t *testing.T) {
+	currencies := Currencies()
+	require.Len(t, currencies, len(registry))
+
+	numerics := make(map[string]bool)
+	for i, currency := range currencies {
+		if i > 0 {
+			require.Less(t, currencies[i-1].Code, currency.Code)
+		}
+		require.Regexp(t, `^[A-Z]{3}$`, currency.Code)
+		require.Regexp(t, `^\d{3}$`, currency.Numeric)
+		require.False(t, numerics[currency.Numeric], "numeric code %s is repeated", currency.Numeric)
+		numerics[currency.Numeric] = true
+	}
+}
@@ -0,0 +1,225 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrOverflow is returned when a result doesn't fit in int64 minor units.
+	ErrOverflow = errors.New("amount out of range")
+	// ErrCurrencyMismatch is returned when combining amounts of different
+	// currencies.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrInvalidAmount is wrapped by every error Parse returns.
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// Money is an amount of a currency, in minor units.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+func New(amount int64, currency Currency) Money {
+	return Money{amount: amount, currency: currency}
+}
+
+// Parse converts a decimal such as "12.34" or "-5" into money. Values with
+// more decimals than the currency's minor unit are rejected unless the extra
+// decimals are zeros, so the conversion is always exact.
+func Parse(value string, currency Currency) (Money, error) {
+	invalid := func(reason string) (Money, error) {
+		return Money{}, fmt.Errorf("%w %q: %s", ErrInvalidAmount, value, reason)
+	}
+
+	negative, whole, fraction, ok := splitDecimal(value)
+	if !ok {
+		return invalid("must be a decimal number like 12.34")
+	}
+
+	if len(fraction) > currency.MinorUnits {
+		if strings.Trim(fraction[currency.MinorUnits:], "0") != "" {
+			return invalid(fmt.Sprintf("%s has %d decimals", currency.Code, currency.MinorUnits))
+		}
+		fraction = fraction[:currency.MinorUnits]
+	}
+	fraction += strings.Repeat("0", currency.MinorUnits-len(fraction))
+
+	var amount int64
+	for _, digit := range whole + fraction {
+		var err error
+		if amount, err = mul(amount, 10); err == nil {
+			amount, err = add(amount, int64(digit-'0'))
+		}
+		if err != nil {
+			return invalid(err.Error())
+		}
+	}
+	if negative {
+		amount = -amount
+	}
+	return New(amount, currency), nil
+}
+
+// Format renders minor units of the currency with the given code as a
+// decimal.
+func Format(amount int64, code string) (string, error) {
+	currency, err := Lookup(code)
+	if err != nil {
+		return "", err
+	}
+	return New(amount, currency).Decimal(), nil
+}
+
+// Amount returns the amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	amount, err := add(m.amount, other.amount)
+	return New(amount, m.currency), err
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	if other.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	amount, err := add(m.amount, -other.amount)
+	return New(amount, m.currency), err
+}
+
+func (m Money) Neg() (Money, error) {
+	if m.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return New(-m.amount, m.currency), nil
+}
+
+// Mul multiplies the amount by n, e.g. a fee per item by a count.
+func (m Money) Mul(n int64) (Money, error) {
+	amount, err := mul(m.amount, n)
+	return New(amount, m.currency), err
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or 1.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Decimal renders the amount in major units with exactly the currency's
+// number of decimals, e.g. "-0.05" or "1500" for yen.
+func (m Money) Decimal() string {
+	sign := ""
+	// Working on the magnitude as uint64 keeps math.MinInt64 exact.
+	magnitude := uint64(m.amount)
+	if m.amount < 0 {
+		sign = "-"
+		magnitude = -magnitude
+	}
+
+	digits := fmt.Sprintf("%0*d", m.currency.MinorUnits+1, magnitude)
+	if m.currency.MinorUnits == 0 {
+		return sign + digits
+	}
+	point := len(digits) - m.currency.MinorUnits
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// String renders the amount with its currency, e.g. "12.34 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.currency.Code
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON writes money as its decimal amount and currency code.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.currency.Code})
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.currency.Code != other.currency.Code {
+		return fmt.Errorf("%w %s vs %s", ErrCurrencyMismatch, m.currency.Code, other.currency.Code)
+	}
+	return nil
+}
+
+func add(a, b int64) (int64, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+func mul(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, ErrOverflow
+	}
+	return product, nil
+}
+
+// splitDecimal splits a decimal into its sign, whole and fraction digits.
+func splitDecimal(value string) (negative bool, whole, fraction string, ok bool) {
+	digits := value
+	negative = strings.HasPrefix(digits, "-")
+	if negative {
+		digits = digits[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+	ok = isDigits(whole) && (!hasPoint || isDigits(fraction))
+	return negative, whole, fraction, ok
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	usd = Currency{Code: "USD", Numeric: "840", MinorUnits: 2}
+	eur = Currency{Code: "EUR", Numeric: "978", MinorUnits: 2}
+	jpy = Currency{Code: "JPY", Numeric: "392", MinorUnits: 0}
+	kwd = Currency{Code: "KWD", Numeric: "414", MinorUnits: 3}
+)
+
+func TestParse(t *testing.T) {
+	testcase := []struct {
+		value    string
+		currency Currency
+		amount   int64
+	}{
+		{"12.34", usd, 1234},
+		{"12.3", usd, 1230},
+		{"12", usd, 1200},
+		{"0.05", usd, 5},
+		{"-0.05", usd, -5},
+		{"12.340", usd, 1234},
+		{"007.50", usd, 750},
+		{"1500", jpy, 1500},
+		{"1500.00", jpy, 1500},
+		{"1.234", kwd, 1234},
+		{"92233720368547758.07", usd, math.MaxInt64},
+	}
+
+	for _, tc := range testcase {
+		m, err := Parse(tc.value, tc.currency)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.amount, m.Amount(), tc.value)
+		require.Equal(t, tc.currency, m.Currency())
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testcase := []struct {
+		value    string
+		currency Currency
+	}{
+		{"", usd},
+		{"-", usd},
+		{".5", usd},
+		{"5.", usd},
+		{"+5", usd},
+		{"1,000.00", usd},
+		{"1e3", usd},
+		{" 5", usd},
+		{"12.345", usd},
+		{"12.5", jpy},
+		{"92233720368547758.08", usd},
+		{"99999999999999999999", jpy},
+	}
+
+	for _, tc := range testcase {
+		_, err := Parse(tc.value, tc.currency)
+		require.ErrorIs(t, err, ErrInvalidAmount, tc.value)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	require.Equal(t, "12.34", New(1234, usd).Decimal())
+	require.Equal(t, "0.05", New(5, usd).Decimal())
+	require.Equal(t, "-0.05", New(-5, usd).Decimal())
+	require.Equal(t, "0.00", New(0, usd).Decimal())
+	require.Equal(t, "1500", New(1500, jpy).Decimal())
+	require.Equal(t, "-1.234", New(-1234, kwd).Decimal())
+	require.Equal(t, "-92233720368547758.08", New(math.MinInt64, usd).Decimal())
+	require.Equal(t, "12.34 USD", New(1234, usd).String())
+
+	formatted, err := Format(-250, "EUR")
+	require.NoError(t, err)
+	require.Equal(t, "-2.50", formatted)
+	_, err = Format(1, "XYZ")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestDecimalRoundTrip(t *testing.T) {
+	for _, currency := range []Currency{usd, jpy, kwd} {
+		for _, amount := range []int64{0, 1, -1, 99, 100, -12345, math.MaxInt64, math.MinInt64 + 1} {
+			m, err := Parse(New(amount, currency).Decimal(), currency)
+			require.NoError(t, err)
+			require.Equal(t, amount, m.Amount())
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	sum, err := New(150, usd).Add(New(-50, usd))
+	require.NoError(t, err)
+	require.Equal(t, int64(100), sum.Amount())
+
+	diff, err := New(150, usd).Sub(New(200, usd))
+	require.NoError(t, err)
+	require.Equal(t, int64(-50), diff.Amount())
+
+	neg, err := New(150, usd).Neg()
+	require.NoError(t, err)
+	require.Equal(t, int64(-150), neg.Amount())
+
+	product, err := New(-25, usd).Mul(4)
+	require.NoError(t, err)
+	require.Equal(t, int64(-100), product.Amount())
+
+	cmp, err := New(1, usd).Cmp(New(2, usd))
+	require.NoError(t, err)
+	require.Equal(t, -1, cmp)
+
+	_, err = New(1, usd).Add(New(1, eur))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = New(1, usd).Sub(New(1, eur))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = New(1, usd).Cmp(New(1, eur))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestOverflow(t *testing.T) {
+	max := New(math.MaxInt64, usd)
+	min := New(math.MinInt64, usd)
+
+	_, err := max.Add(New(1, usd))
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = min.Add(New(-1, usd))
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = min.Sub(New(1, usd))
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = New(0, usd).Sub(min)
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = max.Sub(New(-1, usd))
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = min.Neg()
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = max.Mul(2)
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = min.Mul(-1)
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = New(-1, usd).Mul(math.MinInt64)
+	require.ErrorIs(t, err, ErrOverflow)
+
+	sum, err := max.Add(New(-1, usd))
+	require.NoError(t, err)
+	require.Equal(t, int64(math.MaxInt64-1), sum.Amount())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(New(-1234, usd))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"-12.34","currency":"USD"}`, string(data))
+}
@@ -0,0 +1,320 @@
+// Package pain reads ISO 20022 customer credit transfer initiations
+// (pain.001) and writes the payment status reports (pain.002) answering them.
+// Accounts are identified by their id in the Othr>Id element, the same way
+// camt.053 statements identify them.
+package pain
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aryan-more/simple_bank/money"
+)
+
+// Pain001Namespace is the credit transfer initiation version Parse accepts.
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// pain.001 text length limits.
+const (
+	maxID   = 35
+	maxText = 140
+
+	// notProvided is the end to end id of transactions that have none.
+	notProvided = "NOTPROVIDED"
+)
+
+// ErrInvalidDocument is wrapped by every error Parse returns for a document
+// that isn't a valid pain.001 file.
+var ErrInvalidDocument = errors.New("invalid pain.001 document")
+
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type accountID struct {
+	ID   string `xml:"Id>Othr>Id"`
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type document struct {
+	XMLName    xml.Name `xml:"Document"`
+	Initiation struct {
+		GroupHeader struct {
+			MessageID    string `xml:"MsgId"`
+			CreatedAt    string `xml:"CreDtTm"`
+			Transactions string `xml:"NbOfTxs"`
+			ControlSum   string `xml:"CtrlSum"`
+			Initiator    string `xml:"InitgPty>Nm"`
+		} `xml:"GrpHdr"`
+		Payments []struct {
+			ID           string    `xml:"PmtInfId"`
+			Method       string    `xml:"PmtMtd"`
+			Transactions string    `xml:"NbOfTxs"`
+			ControlSum   string    `xml:"CtrlSum"`
+			Debtor       string    `xml:"Dbtr>Nm"`
+			Account      accountID `xml:"DbtrAcct"`
+			Transfers    []struct {
+				InstructionID string    `xml:"PmtId>InstrId"`
+				EndToEndID    string    `xml:"PmtId>EndToEndId"`
+				Amount        amount    `xml:"Amt>InstdAmt"`
+				Creditor      string    `xml:"Cdtr>Nm"`
+				Account       accountID `xml:"CdtrAcct"`
+				Remittance    []string  `xml:"RmtInf>Ustrd"`
+			} `xml:"CdtTrfTxInf"`
+		} `xml:"PmtInf"`
+	} `xml:"CstmrCdtTrfInitn"`
+}
+
+// Initiation is a parsed pain.001 message.
+type Initiation struct {
+	MessageID string
+	// Transactions and ControlSum are the totals declared in the group
+	// header, ControlSum is empty when the header leaves it out.
+	Transactions int
+	ControlSum   string
+	Payments     []Payment
+}
+
+// Payment is a payment information block, a group of credit transfers out
+// of the same debtor account.
+type Payment struct {
+	ID            string
+	FromAccountID int64
+	Transfers     []CreditTransfer
+}
+
+// CreditTransfer is a single credit transfer transaction. Amount is in minor
+// units. Rejection is set when the transaction itself is unusable, e.g. its
+// creditor account isn't one of ours, while the rest of the file is fine.
+type CreditTransfer struct {
+	InstructionID string
+	EndToEndID    string
+	ToAccountID   int64
+	Amount        int64
+	Currency      string
+	Creditor      string
+	Remittance    string
+	Rejection     *Status
+}
+
+// Reference returns the end to end id, unless the debtor didn't provide one.
+func (transfer CreditTransfer) Reference() string {
+	if transfer.EndToEndID == notProvided {
+		return ""
+	}
+	return transfer.EndToEndID
+}
+
+// Parse reads a pain.001 document. Problems with the document as a whole are
+// returned as errors wrapping ErrInvalidDocument, problems with a single
+// credit transfer are reported in its Rejection.
+func Parse(r io.Reader) (*Initiation, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if doc.XMLName.Space != Pain001Namespace {
+		return nil, fmt.Errorf("%w: namespace must be %s", ErrInvalidDocument, Pain001Namespace)
+	}
+
+	header := doc.Initiation.GroupHeader
+	if err := checkID("GrpHdr/MsgId", header.MessageID); err != nil {
+		return nil, err
+	}
+	transactions, err := strconv.Atoi(header.Transactions)
+	if err != nil || transactions < 1 {
+		return nil, fmt.Errorf("%w: GrpHdr/NbOfTxs must be a positive number", ErrInvalidDocument)
+	}
+	if len(doc.Initiation.Payments) == 0 {
+		return nil, fmt.Errorf("%w: PmtInf is required", ErrInvalidDocument)
+	}
+
+	initiation := &Initiation{
+		MessageID:    header.MessageID,
+		Transactions: transactions,
+		ControlSum:   header.ControlSum,
+		Payments:     make([]Payment, len(doc.Initiation.Payments)),
+	}
+
+	for i, block := range doc.Initiation.Payments {
+		path := fmt.Sprintf("PmtInf[%d]", i+1)
+		if err := checkID(path+"/PmtInfId", block.ID); err != nil {
+			return nil, err
+		}
+		if block.Method != "TRF" {
+			return nil, fmt.Errorf("%w: %s/PmtMtd must be TRF", ErrInvalidDocument, path)
+		}
+		from, err := parseAccountID(block.Account)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s/DbtrAcct: %v", ErrInvalidDocument, path, err)
+		}
+		if len(block.Transfers) == 0 {
+			return nil, fmt.Errorf("%w: %s/CdtTrfTxInf is required", ErrInvalidDocument, path)
+		}
+
+		payment := Payment{
+			ID:            block.ID,
+			FromAccountID: from,
+			Transfers:     make([]CreditTransfer, len(block.Transfers)),
+		}
+		for j, tx := range block.Transfers {
+			txPath := fmt.Sprintf("%s/CdtTrfTxInf[%d]", path, j+1)
+			if err := checkID(txPath+"/PmtId/EndToEndId", tx.EndToEndID); err != nil {
+				return nil, err
+			}
+
+			transfer := CreditTransfer{
+				InstructionID: tx.InstructionID,
+				EndToEndID:    tx.EndToEndID,
+				Currency:      tx.Amount.Currency,
+				Creditor:      tx.Creditor,
+				Remittance:    strings.Join(tx.Remittance, " "),
+			}
+			if transfer.Amount, err = ParseAmount(tx.Amount.Value, tx.Amount.Currency); errors.Is(err, money.ErrUnknownCurrency) {
+				transfer.Rejection = Reject(ReasonCurrency, err)
+			} else if err != nil {
+				transfer.Rejection = Reject(ReasonInvalidAmount, err)
+			} else if transfer.ToAccountID, err = parseAccountID(tx.Account); err != nil {
+				transfer.Rejection = Reject(ReasonIncorrectAccount, err)
+			} else if utf8.RuneCountInString(transfer.Remittance) > maxText {
+				err := fmt.Errorf("remittance information longer than %d characters", maxText)
+				transfer.Rejection = Reject(ReasonNarrative, err)
+			}
+			payment.Transfers[j] = transfer
+		}
+		initiation.Payments[i] = payment
+	}
+
+	return initiation, nil
+}
+
+// Count returns the number of credit transfers in the file.
+func (initiation *Initiation) Count() int {
+	count := 0
+	for _, payment := range initiation.Payments {
+		count += len(payment.Transfers)
+	}
+	return count
+}
+
+// Sum returns the control sum of the file, the total of the instructed
+// amounts whatever their currency. It's exact, the currencies don't all
+// have the same minor units.
+func (initiation *Initiation) Sum() *big.Rat {
+	sum := new(big.Rat)
+	for _, payment := range initiation.Payments {
+		for _, transfer := range payment.Transfers {
+			currency, err := money.Lookup(transfer.Currency)
+			if err != nil {
+				continue
+			}
+			scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.MinorUnits)), nil)
+			sum.Add(sum, new(big.Rat).SetFrac(big.NewInt(transfer.Amount), scale))
+		}
+	}
+	return sum
+}
+
+// CheckTotals compares the totals declared in the group header with the
+// transactions in the file, returning the rejection of the whole file when
+// they don't match.
+func (initiation *Initiation) CheckTotals() *Status {
+	if count := initiation.Count(); count != initiation.Transactions {
+		err := fmt.Errorf("NbOfTxs is %d but the file has %d transactions", initiation.Transactions, count)
+		return Reject(ReasonTransactionCount, err)
+	}
+	if initiation.ControlSum == "" {
+		return nil
+	}
+	controlSum, ok := parseDecimal(initiation.ControlSum)
+	if !ok {
+		return Reject(ReasonControlSum, fmt.Errorf("CtrlSum: invalid amount %q", initiation.ControlSum))
+	}
+	if sum := initiation.Sum(); controlSum.Cmp(sum) != 0 {
+		err := fmt.Errorf("CtrlSum is %s but the transactions add up to %s",
+			initiation.ControlSum, formatDecimal(sum))
+		return Reject(ReasonControlSum, err)
+	}
+	return nil
+}
+
+// ParseAmount converts a positive decimal amount into minor units of its
+// currency, which must not have more fraction digits than the currency.
+func ParseAmount(value, currency string) (int64, error) {
+	c, err := money.Lookup(currency)
+	if err != nil {
+		return 0, err
+	}
+	amount, err := money.Parse(strings.TrimSpace(value), c)
+	if err != nil {
+		return 0, err
+	}
+	if amount.IsNegative() || amount.IsZero() {
+		return 0, fmt.Errorf("%w %q: must be positive", money.ErrInvalidAmount, value)
+	}
+	return amount.Amount(), nil
+}
+
+// parseDecimal parses a plain decimal such as the CtrlSum, which has no
+// currency and so no limit on its fraction digits.
+func parseDecimal(value string) (*big.Rat, bool) {
+	value = strings.TrimSpace(value)
+	whole, fraction, found := strings.Cut(value, ".")
+	if !isDigits(whole) || (found && !isDigits(fraction)) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(value)
+}
+
+// formatDecimal renders a sum with as many fraction digits as it needs, at
+// least two.
+func formatDecimal(sum *big.Rat) string {
+	s := sum.FloatString(18)
+	s = strings.TrimRight(s, "0")
+	whole, fraction, _ := strings.Cut(s, ".")
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	return whole + "." + fraction
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func checkID(path, value string) error {
+	if value == "" || utf8.RuneCountInString(value) > maxID {
+		return fmt.Errorf("%w: %s must be 1 to %d characters", ErrInvalidDocument, path, maxID)
+	}
+	return nil
+}
+
+func parseAccountID(account accountID) (int64, error) {
+	if account.ID == "" {
+		if account.IBAN != "" {
+			return 0, fmt.Errorf("IBAN %s isn't held here", account.IBAN)
+		}
+		return 0, errors.New("account Id/Othr/Id is required")
+	}
+
+	id, err := strconv.ParseInt(account.ID, 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("account %q isn't held here", account.ID)
+	}
+	return id, nil
+}
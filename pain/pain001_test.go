@@ -0,0 +1,135 @@
+package pain
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aryan-more/simple_bank/money"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestFile(t *testing.T) *Initiation {
+	file, err := os.Open("testdata/pain001.xml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	initiation, err := Parse(file)
+	require.NoError(t, err)
+	return initiation
+}
+
+func TestParse(t *testing.T) {
+	initiation := parseTestFile(t)
+
+	require.Equal(t, "PAYROLL-2024-03", initiation.MessageID)
+	require.Equal(t, 4, initiation.Transactions)
+	require.Equal(t, 4, initiation.Count())
+	require.Len(t, initiation.Payments, 2)
+
+	salaries := initiation.Payments[0]
+	require.Equal(t, "SALARIES", salaries.ID)
+	require.Equal(t, int64(10), salaries.FromAccountID)
+	require.Equal(t, CreditTransfer{
+		InstructionID: "I-1",
+		EndToEndID:    "SAL-ALICE",
+		ToAccountID:   20,
+		Amount:        150050,
+		Currency:      "USD",
+		Creditor:      "Alice",
+		Remittance:    "Salary March",
+	}, salaries.Transfers[0])
+	require.Equal(t, "SAL-ALICE", salaries.Transfers[0].Reference())
+
+	iban := salaries.Transfers[1]
+	require.Empty(t, iban.Reference())
+	require.NotNil(t, iban.Rejection)
+	require.Equal(t, ReasonIncorrectAccount, iban.Rejection.Reason)
+
+	expenses := initiation.Payments[1]
+	require.Equal(t, int64(11), expenses.FromAccountID)
+	require.Equal(t, "Travel expenses", expenses.Transfers[0].Remittance)
+	require.Nil(t, expenses.Transfers[0].Rejection)
+	require.NotNil(t, expenses.Transfers[1].Rejection)
+	require.Equal(t, ReasonInvalidAmount, expenses.Transfers[1].Rejection.Reason)
+
+	require.Equal(t, "2100.50", formatDecimal(initiation.Sum()))
+	require.Nil(t, initiation.CheckTotals())
+}
+
+func TestParseInvalidDocument(t *testing.T) {
+	valid, err := os.ReadFile("testdata/pain001.xml")
+	require.NoError(t, err)
+
+	testcase := []struct {
+		name string
+		doc  string
+	}{
+		{"Malformed", "<Document>"},
+		{"Namespace", strings.Replace(string(valid), "pain.001.001.03", "pain.001.001.09", 1)},
+		{"MissingMsgId", strings.Replace(string(valid), "<MsgId>PAYROLL-2024-03</MsgId>", "", 1)},
+		{"NbOfTxs", strings.Replace(string(valid), "<NbOfTxs>4</NbOfTxs>", "<NbOfTxs>four</NbOfTxs>", 1)},
+		{"PmtMtd", strings.Replace(string(valid), "<PmtMtd>TRF</PmtMtd>", "<PmtMtd>CHK</PmtMtd>", 1)},
+		{"DebtorAccount", strings.Replace(string(valid), "<Id>10</Id>", "<Id>x</Id>", 1)},
+		{"EndToEndId", strings.Replace(string(valid), "<EndToEndId>EXP-BOB</EndToEndId>", "<EndToEndId>"+strings.Repeat("x", 36)+"</EndToEndId>", 1)},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.doc))
+			require.ErrorIs(t, err, ErrInvalidDocument)
+		})
+	}
+}
+
+func TestCheckTotals(t *testing.T) {
+	initiation := &Initiation{
+		Transactions: 3,
+		Payments: []Payment{{Transfers: []CreditTransfer{
+			{Amount: 1050, Currency: "USD"},
+			{Amount: 1, Currency: "EUR"},
+			{Amount: 5, Currency: "KWD"},
+		}}},
+	}
+	require.Nil(t, initiation.CheckTotals())
+
+	initiation.ControlSum = "10.515"
+	require.Nil(t, initiation.CheckTotals())
+
+	initiation.ControlSum = "10.51"
+	require.Equal(t, ReasonControlSum, initiation.CheckTotals().Reason)
+
+	initiation.ControlSum = "1e1"
+	require.Equal(t, ReasonControlSum, initiation.CheckTotals().Reason)
+
+	initiation.ControlSum = "10.515"
+	initiation.Transactions = 4
+	require.Equal(t, ReasonTransactionCount, initiation.CheckTotals().Reason)
+}
+
+func TestParseAmount(t *testing.T) {
+	for value, want := range map[string]int64{"1": 100, "1.5": 150, "0.01": 1, "1500.50": 150050} {
+		amount, err := ParseAmount(value, "USD")
+		require.NoError(t, err)
+		require.Equal(t, want, amount, value)
+	}
+
+	for _, value := range []string{"", "0", "0.00", "-1", "+1", "1.005", "1,5", ".5", "1e3"} {
+		_, err := ParseAmount(value, "USD")
+		require.Error(t, err, value)
+	}
+
+	amount, err := ParseAmount("1500", "JPY")
+	require.NoError(t, err)
+	require.Equal(t, int64(1500), amount)
+
+	amount, err = ParseAmount("1.005", "KWD")
+	require.NoError(t, err)
+	require.Equal(t, int64(1005), amount)
+
+	_, err = ParseAmount("1.5", "JPY")
+	require.ErrorIs(t, err, money.ErrInvalidAmount)
+
+	_, err = ParseAmount("1", "XYZ")
+	require.ErrorIs(t, err, money.ErrUnknownCurrency)
+}
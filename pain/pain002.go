@@ -0,0 +1,196 @@
+package pain
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Pain002Namespace is the payment status report version Report produces.
+const Pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+const (
+	pain001Name       = "pain.001.001.03"
+	dateTimeLayout    = "2006-01-02T15:04:05Z"
+	maxAdditionalInfo = 105
+)
+
+// Transaction and group status codes.
+const (
+	StatusAccepted = "ACSC"
+	StatusPartial  = "PART"
+	StatusRejected = "RJCT"
+)
+
+// ISO 20022 status reason codes.
+const (
+	ReasonIncorrectAccount     = "AC01"
+	ReasonTransactionForbidden = "AG01"
+	ReasonAmountNotAllowed     = "AM02"
+	ReasonCurrency             = "AM03"
+	ReasonInsufficientFunds    = "AM04"
+	ReasonControlSum           = "AM10"
+	ReasonInvalidAmount        = "AM12"
+	ReasonTransactionCount     = "AM18"
+	ReasonNarrative            = "NARR"
+)
+
+// Status is the outcome of a credit transfer, or of the whole file when it
+// was rejected. Reference is our reference for an accepted transfer.
+type Status struct {
+	Code      string
+	Reason    string
+	Info      string
+	Reference string
+}
+
+// Accept returns the status of an executed transfer.
+func Accept(reference string) Status {
+	return Status{Code: StatusAccepted, Reference: reference}
+}
+
+// Reject returns a rejection with the given reason code, explained by err.
+func Reject(reason string, err error) *Status {
+	return &Status{Code: StatusRejected, Reason: reason, Info: err.Error()}
+}
+
+// Report is a payment status report answering an initiation.
+type Report struct {
+	MessageID  string
+	CreatedAt  time.Time
+	Initiation *Initiation
+	// Rejection rejects the file as a whole, no transfer was executed.
+	Rejection *Status
+	// Statuses holds the status of each credit transfer, indexed the same
+	// way as the payments and their transfers.
+	Statuses [][]Status
+}
+
+type reasonXML struct {
+	Code string `xml:"Rsn>Cd,omitempty"`
+	Info string `xml:"AddtlInf,omitempty"`
+}
+
+type transactionXML struct {
+	InstructionID string     `xml:"OrgnlInstrId,omitempty"`
+	EndToEndID    string     `xml:"OrgnlEndToEndId"`
+	Status        string     `xml:"TxSts"`
+	Reason        *reasonXML `xml:"StsRsnInf,omitempty"`
+	Reference     string     `xml:"AcctSvcrRef,omitempty"`
+}
+
+type paymentXML struct {
+	ID           string           `xml:"OrgnlPmtInfId"`
+	Transactions int              `xml:"OrgnlNbOfTxs"`
+	Status       string           `xml:"PmtInfSts"`
+	Transfers    []transactionXML `xml:"TxInfAndSts"`
+}
+
+type reportXML struct {
+	XMLName   xml.Name `xml:"Document"`
+	Namespace string   `xml:"xmlns,attr"`
+	Report    struct {
+		GroupHeader struct {
+			MessageID string `xml:"MsgId"`
+			CreatedAt string `xml:"CreDtTm"`
+		} `xml:"GrpHdr"`
+		Original struct {
+			MessageID    string     `xml:"OrgnlMsgId"`
+			MessageName  string     `xml:"OrgnlMsgNmId"`
+			Transactions int        `xml:"OrgnlNbOfTxs,omitempty"`
+			Status       string     `xml:"GrpSts"`
+			Reason       *reasonXML `xml:"StsRsnInf,omitempty"`
+		} `xml:"OrgnlGrpInfAndSts"`
+		Payments []paymentXML `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+// Marshal renders the report as a pain.002 document.
+func (report *Report) Marshal() ([]byte, error) {
+	doc := reportXML{Namespace: Pain002Namespace}
+	doc.Report.GroupHeader.MessageID = truncate(report.MessageID, maxID)
+	doc.Report.GroupHeader.CreatedAt = report.CreatedAt.UTC().Format(dateTimeLayout)
+
+	original := &doc.Report.Original
+	original.MessageName = pain001Name
+	if report.Initiation != nil {
+		original.MessageID = report.Initiation.MessageID
+		original.Transactions = report.Initiation.Transactions
+	}
+
+	if report.Rejection != nil {
+		original.Status = StatusRejected
+		original.Reason = reason(*report.Rejection)
+		return marshal(doc)
+	}
+
+	var groupCodes []string
+	for i, payment := range report.Initiation.Payments {
+		element := paymentXML{
+			ID:           payment.ID,
+			Transactions: len(payment.Transfers),
+		}
+
+		var codes []string
+		for j, transfer := range payment.Transfers {
+			status := report.Statuses[i][j]
+			codes = append(codes, status.Code)
+			element.Transfers = append(element.Transfers, transactionXML{
+				InstructionID: transfer.InstructionID,
+				EndToEndID:    transfer.EndToEndID,
+				Status:        status.Code,
+				Reason:        reason(status),
+				Reference:     status.Reference,
+			})
+		}
+
+		element.Status = combine(codes)
+		groupCodes = append(groupCodes, codes...)
+		doc.Report.Payments = append(doc.Report.Payments, element)
+	}
+	original.Status = combine(groupCodes)
+
+	return marshal(doc)
+}
+
+func marshal(doc reportXML) ([]byte, error) {
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(xml.Header), data...), '\n'), nil
+}
+
+func reason(status Status) *reasonXML {
+	if status.Reason == "" && status.Info == "" {
+		return nil
+	}
+	return &reasonXML{Code: status.Reason, Info: truncate(status.Info, maxAdditionalInfo)}
+}
+
+// combine returns the status of a group of transactions, partial when some
+// of them were accepted and others rejected.
+func combine(codes []string) string {
+	accepted := 0
+	for _, code := range codes {
+		if code == StatusAccepted {
+			accepted++
+		}
+	}
+	switch accepted {
+	case len(codes):
+		return StatusAccepted
+	case 0:
+		return StatusRejected
+	default:
+		return StatusPartial
+	}
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
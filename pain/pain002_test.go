@@ -0,0 +1,73 @@
+package pain
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	path := "testdata/" + name
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestReport(t *testing.T) {
+	initiation := parseTestFile(t)
+
+	report := &Report{
+		MessageID:  "STS-PAYROLL-2024-03",
+		CreatedAt:  time.Date(2024, 3, 28, 9, 5, 0, 0, time.UTC),
+		Initiation: initiation,
+		Statuses: [][]Status{
+			{Accept("1001"), *initiation.Payments[0].Transfers[1].Rejection},
+			{Accept("1002"), *initiation.Payments[1].Transfers[1].Rejection},
+		},
+	}
+
+	data, err := report.Marshal()
+	require.NoError(t, err)
+	checkGolden(t, "pain002.golden", data)
+}
+
+func TestReportRejected(t *testing.T) {
+	initiation := parseTestFile(t)
+	initiation.Transactions = 5
+
+	report := &Report{
+		MessageID:  "STS-PAYROLL-2024-03",
+		CreatedAt:  time.Date(2024, 3, 28, 9, 5, 0, 0, time.UTC),
+		Initiation: initiation,
+		Rejection:  initiation.CheckTotals(),
+	}
+
+	data, err := report.Marshal()
+	require.NoError(t, err)
+	checkGolden(t, "pain002_rejected.golden", data)
+}
+
+func TestCombine(t *testing.T) {
+	require.Equal(t, StatusAccepted, combine([]string{StatusAccepted, StatusAccepted}))
+	require.Equal(t, StatusRejected, combine([]string{StatusRejected}))
+	require.Equal(t, StatusPartial, combine([]string{StatusRejected, StatusAccepted}))
+}
+
+func TestReasonTruncated(t *testing.T) {
+	long := make([]byte, 200)
+	for i := range long {
+		long[i] = 'x'
+	}
+	status := Reject(ReasonNarrative, errors.New(string(long)))
+	require.Len(t, reason(*status).Info, maxAdditionalInfo)
+}
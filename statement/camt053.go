@@ -0,0 +1,208 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CAMT053Namespace is the ISO 20022 bank to customer statement version the
+// camt.053 writer produces.
+const CAMT053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+const (
+	camtDateLayout     = "2006-01-02"
+	camtDateTimeLayout = "2006-01-02T15:04:05Z"
+
+	camtMaxText = 140
+)
+
+// ISO 20022 codes used by the writer.
+const (
+	camtOpeningBooked = "OPBD"
+	camtClosingBooked = "CLBD"
+	camtCredit        = "CRDT"
+	camtDebit         = "DBIT"
+	camtBooked        = "BOOK"
+)
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtGroupHeader struct {
+	XMLName   xml.Name `xml:"GrpHdr"`
+	MessageID string   `xml:"MsgId"`
+	CreatedAt string   `xml:"CreDtTm"`
+}
+
+type camtPeriod struct {
+	XMLName xml.Name `xml:"FrToDt"`
+	From    string   `xml:"FrDtTm"`
+	To      string   `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	XMLName  xml.Name `xml:"Acct"`
+	ID       string   `xml:"Id>Othr>Id"`
+	Currency string   `xml:"Ccy"`
+	Owner    string   `xml:"Ownr>Nm"`
+}
+
+type camtBalance struct {
+	XMLName     xml.Name   `xml:"Bal"`
+	Code        string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Date        string     `xml:"Dt>Dt"`
+}
+
+type camtParty struct {
+	Name string `xml:"Nm"`
+}
+
+type camtPartyAccount struct {
+	ID string `xml:"Id>Othr>Id"`
+}
+
+type camtRelatedParties struct {
+	Debtor          *camtParty        `xml:"Dbtr,omitempty"`
+	DebtorAccount   *camtPartyAccount `xml:"DbtrAcct,omitempty"`
+	Creditor        *camtParty        `xml:"Cdtr,omitempty"`
+	CreditorAccount *camtPartyAccount `xml:"CdtrAcct,omitempty"`
+}
+
+type camtTransactionDetails struct {
+	EndToEndID     string              `xml:"Refs>EndToEndId,omitempty"`
+	RelatedParties *camtRelatedParties `xml:"RltdPties,omitempty"`
+	Unstructured   string              `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+type camtEntry struct {
+	XMLName         xml.Name               `xml:"Ntry"`
+	Reference       string                 `xml:"NtryRef"`
+	Amount          camtAmount             `xml:"Amt"`
+	CreditDebit     string                 `xml:"CdtDbtInd"`
+	Status          string                 `xml:"Sts"`
+	BookingDate     string                 `xml:"BookgDt>DtTm"`
+	ValueDate       string                 `xml:"ValDt>DtTm"`
+	TransactionCode string                 `xml:"BkTxCd>Prtry>Cd"`
+	Details         camtTransactionDetails `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo  string                 `xml:"AddtlNtryInf,omitempty"`
+}
+
+// CAMT053Writer writes a statement as an ISO 20022 camt.053 document. Both
+// balances come before the entries in camt.053, so the closing balance is
+// taken from the header.
+type CAMT053Writer struct {
+	x        *xmlWriter
+	currency string
+}
+
+var _ Writer = (*CAMT053Writer)(nil)
+
+func NewCAMT053Writer(w io.Writer) *CAMT053Writer {
+	return &CAMT053Writer{x: newXMLWriter(w)}
+}
+
+func (writer *CAMT053Writer) WriteHeader(header Header) error {
+	writer.currency = header.Currency
+	id := statementID(header)
+	createdAt := header.CreatedAt.UTC().Format(camtDateTimeLayout)
+
+	x := writer.x
+	x.procInst("xml", `version="1.0" encoding="UTF-8"`)
+	x.start("Document", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: CAMT053Namespace})
+	x.start("BkToCstmrStmt")
+	x.encode(camtGroupHeader{MessageID: id, CreatedAt: createdAt})
+	x.start("Stmt")
+	x.element("Id", id)
+	x.element("CreDtTm", createdAt)
+	x.encode(camtPeriod{
+		From: header.From.UTC().Format(camtDateTimeLayout),
+		To:   header.To.UTC().Format(camtDateTimeLayout),
+	})
+	x.encode(camtAccount{
+		ID:       strconv.FormatInt(header.AccountID, 10),
+		Currency: header.Currency,
+		Owner:    header.Owner,
+	})
+	x.encode(writer.balance(camtOpeningBooked, header.OpeningBalance, header.From))
+	x.encode(writer.balance(camtClosingBooked, header.ClosingBalance, header.To))
+	return x.flush()
+}
+
+func (writer *CAMT053Writer) WriteLine(line Line) error {
+	entry := camtEntry{
+		Reference:       strconv.FormatInt(line.EntryID, 10),
+		Amount:          writer.amount(line.Amount),
+		CreditDebit:     creditDebit(line.Amount),
+		Status:          camtBooked,
+		BookingDate:     line.Time.UTC().Format(camtDateTimeLayout),
+		ValueDate:       line.Time.UTC().Format(camtDateTimeLayout),
+		TransactionCode: strings.ToUpper(line.Kind),
+		Details: camtTransactionDetails{
+			EndToEndID:   truncate(line.Reference, 35),
+			Unstructured: truncate(line.Description, camtMaxText),
+		},
+		AdditionalInfo: truncate(line.Description, 500),
+	}
+
+	if line.CounterpartyAccountID != 0 {
+		party := &camtParty{Name: truncate(line.Counterparty, camtMaxText)}
+		account := &camtPartyAccount{ID: strconv.FormatInt(line.CounterpartyAccountID, 10)}
+		// Money coming in was paid by the counterparty, money going out was
+		// paid to it.
+		if line.Amount > 0 {
+			entry.Details.RelatedParties = &camtRelatedParties{Debtor: party, DebtorAccount: account}
+		} else {
+			entry.Details.RelatedParties = &camtRelatedParties{Creditor: party, CreditorAccount: account}
+		}
+	}
+
+	return writer.x.entry(entry)
+}
+
+func (writer *CAMT053Writer) Close(footer Footer) error {
+	writer.x.end("Stmt")
+	writer.x.end("BkToCstmrStmt")
+	writer.x.end("Document")
+	return writer.x.close()
+}
+
+func (writer *CAMT053Writer) balance(code string, balance int64, at time.Time) camtBalance {
+	return camtBalance{
+		Code:        code,
+		Amount:      writer.amount(balance),
+		CreditDebit: creditDebit(balance),
+		Date:        at.UTC().Format(camtDateLayout),
+	}
+}
+
+// amount renders the absolute amount, camt.053 carries the sign in the
+// credit debit indicator next to it.
+func (writer *CAMT053Writer) amount(amount int64) camtAmount {
+	if amount < 0 {
+		amount = -amount
+	}
+	return camtAmount{Currency: writer.currency, Value: formatAmount(amount, writer.currency)}
+}
+
+// creditDebit returns the indicator for an amount, zero counts as a credit.
+func creditDebit(amount int64) string {
+	if amount < 0 {
+		return camtDebit
+	}
+	return camtCredit
+}
+
+// statementID identifies the statement of an account over a period, the
+// same statement always gets the same id.
+func statementID(header Header) string {
+	return fmt.Sprintf("%d-%s-%s", header.AccountID,
+		header.From.UTC().Format("20060102"), header.To.UTC().Format("20060102"))
+}
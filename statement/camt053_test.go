@@ -0,0 +1,57 @@
+package statement
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCAMT053Writer(t *testing.T) {
+	var buf bytes.Buffer
+	writeTestStatement(t, NewCAMT053Writer(&buf))
+
+	validateSchema(t, camt053Schema, buf.Bytes())
+	checkGolden(t, "statement.camt053.golden", buf.Bytes())
+}
+
+func TestCAMT053WriterNegativeBalance(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCAMT053Writer(&buf)
+
+	require.NoError(t, w.WriteHeader(Header{
+		AccountID:      1,
+		Owner:          "dave",
+		Currency:       "EUR",
+		From:           testFrom,
+		To:             testTo,
+		OpeningBalance: -150,
+		ClosingBalance: -150,
+		CreatedAt:      testTo,
+	}))
+	require.NoError(t, w.Close(Footer{ClosingBalance: -150}))
+
+	validateSchema(t, camt053Schema, buf.Bytes())
+	require.Contains(t, buf.String(), `<Amt Ccy="EUR">1.50</Amt>`)
+	require.Contains(t, buf.String(), `<CdtDbtInd>DBIT</CdtDbtInd>`)
+}
+
+func TestCAMT053WriterManyEntries(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCAMT053Writer(&buf)
+
+	require.NoError(t, w.WriteHeader(Header{AccountID: 1, Owner: "erin", Currency: "CAD", From: testFrom, To: testTo}))
+	for i, entry := range testEntries(3*xmlFlushEntries, 1) {
+		require.NoError(t, w.WriteLine(Line{
+			EntryID:     entry.ID,
+			Time:        entry.CreatedAt.Add(time.Duration(i) * time.Second),
+			Kind:        entry.Kind,
+			Description: entry.Description,
+			Amount:      entry.Amount,
+		}))
+	}
+	require.NoError(t, w.Close(Footer{}))
+
+	validateSchema(t, camt053Schema, buf.Bytes())
+}
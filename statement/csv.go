@@ -0,0 +1,81 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvFlushLines is how many lines are buffered before they are written out.
+const csvFlushLines = 100
+
+var csvColumns = []string{
+	"date", "entry_id", "kind", "description", "reference",
+	"counterparty_account_id", "counterparty", "amount", "balance",
+}
+
+// CSVWriter writes a statement as CSV. The opening and closing balances are
+// rows of their own, without an entry id, before and after the entries.
+type CSVWriter struct {
+	w        *csv.Writer
+	to       time.Time
+	buffered int
+}
+
+var _ Writer = (*CSVWriter)(nil)
+
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+func (writer *CSVWriter) WriteHeader(header Header) error {
+	writer.to = header.To
+	if err := writer.w.Write(csvColumns); err != nil {
+		return err
+	}
+	return writer.writeBalance(header.From, "opening balance", header.OpeningBalance)
+}
+
+func (writer *CSVWriter) WriteLine(line Line) error {
+	counterpartyID := ""
+	if line.CounterpartyAccountID != 0 {
+		counterpartyID = strconv.FormatInt(line.CounterpartyAccountID, 10)
+	}
+
+	err := writer.w.Write([]string{
+		line.Time.UTC().Format(time.RFC3339),
+		strconv.FormatInt(line.EntryID, 10),
+		line.Kind,
+		line.Description,
+		line.Reference,
+		counterpartyID,
+		line.Counterparty,
+		strconv.FormatInt(line.Amount, 10),
+		strconv.FormatInt(line.Balance, 10),
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.buffered++
+	if writer.buffered >= csvFlushLines {
+		writer.buffered = 0
+		writer.w.Flush()
+		return writer.w.Error()
+	}
+	return nil
+}
+
+func (writer *CSVWriter) Close(footer Footer) error {
+	return writer.writeBalance(writer.to, "closing balance", footer.ClosingBalance)
+}
+
+func (writer *CSVWriter) writeBalance(at time.Time, description string, balance int64) error {
+	err := writer.w.Write([]string{at.UTC().Format(time.RFC3339), "", "", description, "", "", "", "", strconv.FormatInt(balance, 10)})
+	if err != nil {
+		return err
+	}
+	writer.w.Flush()
+	return writer.w.Error()
+}
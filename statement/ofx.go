@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// BankID identifies the bank in OFX account aggregates, at most nine
+// characters.
+const BankID = "SIMPLEBNK"
+
+const ofxDateTimeLayout = "20060102150405.000[0:GMT]"
+
+// OFX transaction types used by the writer.
+const (
+	ofxCredit = "CREDIT"
+	ofxDebit  = "DEBIT"
+	ofxFee    = "FEE"
+)
+
+type ofxStatus struct {
+	XMLName  xml.Name `xml:"STATUS"`
+	Code     int      `xml:"CODE"`
+	Severity string   `xml:"SEVERITY"`
+}
+
+var ofxOK = ofxStatus{Code: 0, Severity: "INFO"}
+
+type ofxSignon struct {
+	XMLName  xml.Name `xml:"SONRS"`
+	Status   ofxStatus
+	DTServer string `xml:"DTSERVER"`
+	Language string `xml:"LANGUAGE"`
+}
+
+type ofxBankAccount struct {
+	XMLName   xml.Name `xml:"BANKACCTFROM"`
+	BankID    string   `xml:"BANKID"`
+	AccountID string   `xml:"ACCTID"`
+	Type      string   `xml:"ACCTTYPE"`
+}
+
+type ofxTransaction struct {
+	XMLName   xml.Name `xml:"STMTTRN"`
+	Type      string   `xml:"TRNTYPE"`
+	DTPosted  string   `xml:"DTPOSTED"`
+	Amount    string   `xml:"TRNAMT"`
+	FITID     string   `xml:"FITID"`
+	Reference string   `xml:"REFNUM,omitempty"`
+	Name      string   `xml:"NAME,omitempty"`
+	Memo      string   `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	XMLName xml.Name `xml:"LEDGERBAL"`
+	Amount  string   `xml:"BALAMT"`
+	DTAsOf  string   `xml:"DTASOF"`
+}
+
+// OFXWriter writes a statement as an OFX 2.2 bank statement response. OFX
+// has no opening balance, the ledger balance after the transactions is the
+// closing balance.
+type OFXWriter struct {
+	x        *xmlWriter
+	asOf     string
+	currency string
+}
+
+var _ Writer = (*OFXWriter)(nil)
+
+func NewOFXWriter(w io.Writer) *OFXWriter {
+	return &OFXWriter{x: newXMLWriter(w)}
+}
+
+func (writer *OFXWriter) WriteHeader(header Header) error {
+	writer.asOf = header.To.UTC().Format(ofxDateTimeLayout)
+	writer.currency = header.Currency
+
+	x := writer.x
+	x.procInst("xml", `version="1.0" encoding="UTF-8" standalone="no"`)
+	x.procInst("OFX", `OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"`)
+	x.start("OFX")
+	x.start("SIGNONMSGSRSV1")
+	x.encode(ofxSignon{
+		Status:   ofxOK,
+		DTServer: header.CreatedAt.UTC().Format(ofxDateTimeLayout),
+		Language: "ENG",
+	})
+	x.end("SIGNONMSGSRSV1")
+	x.start("BANKMSGSRSV1")
+	x.start("STMTTRNRS")
+	x.element("TRNUID", statementID(header))
+	x.encode(ofxOK)
+	x.start("STMTRS")
+	x.element("CURDEF", header.Currency)
+	x.encode(ofxBankAccount{
+		BankID:    BankID,
+		AccountID: strconv.FormatInt(header.AccountID, 10),
+		Type:      "CHECKING",
+	})
+	x.start("BANKTRANLIST")
+	x.element("DTSTART", header.From.UTC().Format(ofxDateTimeLayout))
+	x.element("DTEND", writer.asOf)
+	return x.flush()
+}
+
+func (writer *OFXWriter) WriteLine(line Line) error {
+	trnType := ofxCredit
+	switch {
+	case line.Kind == "fee":
+		trnType = ofxFee
+	case line.Amount < 0:
+		trnType = ofxDebit
+	}
+
+	return writer.x.entry(ofxTransaction{
+		Type:      trnType,
+		DTPosted:  line.Time.UTC().Format(ofxDateTimeLayout),
+		Amount:    formatAmount(line.Amount, writer.currency),
+		FITID:     strconv.FormatInt(line.EntryID, 10),
+		Reference: truncate(line.Reference, 32),
+		Name:      truncate(line.Counterparty, 32),
+		Memo:      truncate(line.Description, 255),
+	})
+}
+
+func (writer *OFXWriter) Close(footer Footer) error {
+	x := writer.x
+	x.end("BANKTRANLIST")
+	x.encode(ofxBalance{
+		Amount: formatAmount(footer.ClosingBalance, writer.currency),
+		DTAsOf: writer.asOf,
+	})
+	x.end("STMTRS")
+	x.end("STMTTRNRS")
+	x.end("BANKMSGSRSV1")
+	x.end("OFX")
+	return x.close()
+}
@@ -0,0 +1,27 @@
+package statement
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOFXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writeTestStatement(t, NewOFXWriter(&buf))
+
+	validateSchema(t, ofxSchema, buf.Bytes())
+	checkGolden(t, "statement.ofx.golden", buf.Bytes())
+}
+
+func TestOFXWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewOFXWriter(&buf)
+
+	require.NoError(t, w.WriteHeader(Header{AccountID: 1, Owner: "dave", Currency: "EUR", From: testFrom, To: testTo}))
+	require.NoError(t, w.Close(Footer{ClosingBalance: -150}))
+
+	validateSchema(t, ofxSchema, buf.Bytes())
+	require.Contains(t, buf.String(), "<BALAMT>-1.50</BALAMT>")
+}
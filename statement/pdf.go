@@ -0,0 +1,212 @@
+package statement
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The PDF is laid out on A4 in Courier, a standard font every reader has,
+// so nothing has to be embedded and columns line up by character count.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 40
+	pdfFontSize   = 8
+	pdfLeading    = 11
+	pdfLineWidth  = 96
+
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// Objects written before the pages. The page tree is written last, once
+// every page is known, and the catalog refers to it by its fixed number.
+const (
+	pdfCatalogObject = 1
+	pdfPagesObject   = 2
+	pdfFontObject    = 3
+	pdfFirstObject   = 4
+)
+
+const pdfDateLayout = "2006-01-02 15:04"
+
+var _ Writer = (*PDFWriter)(nil)
+
+// PDFWriter writes a statement as a PDF. Each page is written out as soon
+// as it is full, only the object offsets are kept until the end.
+type PDFWriter struct {
+	w       *bufio.Writer
+	offset  int64
+	offsets []int64
+	pages   []int
+	lines   []string
+	err     error
+}
+
+func NewPDFWriter(w io.Writer) *PDFWriter {
+	writer := &PDFWriter{
+		w:       bufio.NewWriter(w),
+		offsets: make([]int64, pdfFirstObject),
+	}
+	writer.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	writer.object(pdfCatalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObject))
+	writer.object(pdfFontObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	return writer
+}
+
+func (writer *PDFWriter) WriteHeader(header Header) error {
+	writer.line(fmt.Sprintf("Statement of account %d (%s)", header.AccountID, header.Currency))
+	writer.line("Owner: " + header.Owner)
+	writer.line(fmt.Sprintf("Period: %s to %s",
+		header.From.UTC().Format(pdfDateLayout), header.To.UTC().Format(pdfDateLayout)))
+	writer.line("")
+	writer.line(fmt.Sprintf("Opening balance: %d", header.OpeningBalance))
+	writer.line("")
+	writer.columns()
+	return writer.err
+}
+
+func (writer *PDFWriter) WriteLine(line Line) error {
+	counterparty := ""
+	if line.CounterpartyAccountID != 0 {
+		counterparty = fmt.Sprintf("#%d %s", line.CounterpartyAccountID, line.Counterparty)
+	}
+	description := line.Description
+	if line.Reference != "" {
+		description += " [" + line.Reference + "]"
+	}
+
+	writer.line(fmt.Sprintf("%-16s %-8s %-29s %-16s %11d %11d",
+		line.Time.UTC().Format(pdfDateLayout),
+		truncate(line.Kind, 8),
+		truncate(description, 29),
+		truncate(counterparty, 16),
+		line.Amount,
+		line.Balance,
+	))
+	return writer.err
+}
+
+func (writer *PDFWriter) Close(footer Footer) error {
+	writer.line("")
+	writer.line(fmt.Sprintf("Entries: %d  Credits: %d  Debits: %d", footer.Entries, footer.Credits, footer.Debits))
+	writer.line(fmt.Sprintf("Closing balance: %d", footer.ClosingBalance))
+	writer.flushPage()
+
+	kids := make([]string, len(writer.pages))
+	for i, page := range writer.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	writer.object(pdfPagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>",
+		strings.Join(kids, " "), len(writer.pages)))
+
+	xref := writer.offset
+	writer.printf("xref\n0 %d\n0000000000 65535 f \n", len(writer.offsets))
+	for _, offset := range writer.offsets[1:] {
+		writer.printf("%010d 00000 n \n", offset)
+	}
+	writer.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(writer.offsets), pdfCatalogObject, xref)
+
+	if writer.err != nil {
+		return writer.err
+	}
+	return writer.w.Flush()
+}
+
+// columns adds the column titles, repeated at the top of every page.
+func (writer *PDFWriter) columns() {
+	writer.lines = append(writer.lines,
+		fmt.Sprintf("%-16s %-8s %-29s %-16s %11s %11s", "Date", "Kind", "Description", "Counterparty", "Amount", "Balance"),
+		strings.Repeat("-", pdfLineWidth),
+	)
+}
+
+func (writer *PDFWriter) line(text string) {
+	if len(writer.lines) >= pdfLinesPerPage-1 {
+		writer.flushPage()
+		writer.columns()
+	}
+	writer.lines = append(writer.lines, text)
+}
+
+// flushPage writes the buffered lines as a page with its number at the
+// bottom.
+func (writer *PDFWriter) flushPage() {
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+	for _, line := range writer.lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", escapePDF(line))
+	}
+	fmt.Fprintf(&content, "ET\nBT\n/F1 %d Tf\n%d %d Td\n(Page %d) Tj\nET\n",
+		pdfFontSize, pdfPageWidth-pdfMargin-40, pdfMargin/2, len(writer.pages)+1)
+	writer.lines = writer.lines[:0]
+
+	contents := len(writer.offsets)
+	writer.offsets = append(writer.offsets, 0)
+	writer.stream(contents, content.Bytes())
+
+	page := len(writer.offsets)
+	writer.offsets = append(writer.offsets, 0)
+	writer.object(page, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObject, pdfPageWidth, pdfPageHeight, pdfFontObject, contents))
+	writer.pages = append(writer.pages, page)
+
+	if writer.err == nil {
+		writer.err = writer.w.Flush()
+	}
+}
+
+func (writer *PDFWriter) object(number int, body string) {
+	writer.offsets[number] = writer.offset
+	writer.printf("%d 0 obj\n%s\nendobj\n", number, body)
+}
+
+func (writer *PDFWriter) stream(number int, data []byte) {
+	writer.offsets[number] = writer.offset
+	writer.printf("%d 0 obj\n<< /Length %d >>\nstream\n", number, len(data))
+	writer.write(data)
+	writer.printf("\nendstream\nendobj\n")
+}
+
+func (writer *PDFWriter) printf(format string, args ...any) {
+	writer.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (writer *PDFWriter) write(data []byte) {
+	if writer.err != nil {
+		return
+	}
+	n, err := writer.w.Write(data)
+	writer.offset += int64(n)
+	writer.err = err
+}
+
+// escapePDF makes text safe inside a PDF string. Characters outside ASCII
+// would need the font's encoding and are replaced.
+func escapePDF(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-1]) + "~"
+}
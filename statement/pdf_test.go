@@ -0,0 +1,61 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPDFWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewPDFWriter(&buf)
+
+	require.NoError(t, w.WriteHeader(Header{AccountID: 42, Owner: "alice", Currency: "USD", From: testFrom, To: testTo}))
+	lines := 3 * pdfLinesPerPage
+	for i, entry := range testEntries(lines, 1) {
+		require.NoError(t, w.WriteLine(Line{
+			EntryID:     entry.ID,
+			Time:        entry.CreatedAt,
+			Kind:        entry.Kind,
+			Description: fmt.Sprintf("memo (%d) \\ é", i),
+			Amount:      entry.Amount,
+			Balance:     int64(i+1) * entry.Amount,
+		}))
+	}
+	require.NoError(t, w.Close(Footer{Entries: lines, ClosingBalance: int64(lines) * 10}))
+
+	pdf := buf.Bytes()
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	require.Contains(t, buf.String(), `memo \(0\) \\ ?`)
+
+	// Header lines and repeated column titles push the entries onto a
+	// fourth page.
+	count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(pdf)
+	require.NotNil(t, count)
+	require.Equal(t, "4", string(count[1]))
+
+	// Every object the xref table points at starts exactly there.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	require.Len(t, offsets, len(w.offsets)-1)
+	for i, offset := range offsets {
+		n, err := strconv.Atoi(string(offset[1]))
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(pdf[n:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))))
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	require.Equal(t, "short", truncate("short", 8))
+	require.Equal(t, "a longe~", truncate("a longer text", 8))
+}
@@ -0,0 +1,91 @@
+package statement
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Schemas under testdata/xsd the XML writers are validated against.
+const (
+	camt053Schema = "camt.053.001.02.xsd"
+	ofxSchema     = "ofx-2.2.xsd"
+)
+
+// goldenSchemas maps the golden file of each XML format to its schema.
+var goldenSchemas = map[string]string{
+	"statement.camt053.golden": camt053Schema,
+	"statement.ofx.golden":     ofxSchema,
+}
+
+// validateSchema validates data against an XSD with xmllint.
+func validateSchema(t *testing.T, schema string, data []byte) {
+	t.Helper()
+
+	out, err := xmllint(t, schema, data)
+	require.NoError(t, err, "%s", out)
+}
+
+// xmllint runs xmllint on data with one of the schemas. Without xmllint the
+// test is skipped, except on CI where it has to be installed.
+func xmllint(t *testing.T, schema string, data []byte) ([]byte, error) {
+	t.Helper()
+
+	path, err := exec.LookPath("xmllint")
+	if err != nil {
+		if os.Getenv("CI") != "" {
+			t.Fatal("xmllint is required to validate statements on CI")
+		}
+		t.Skip("xmllint not installed, skipping schema validation")
+	}
+
+	if schema == ofxSchema {
+		data = ofxRootNamespace(data)
+	}
+
+	cmd := exec.Command(path, "--noout", "--nonet", "--schema", filepath.Join("testdata", "xsd", schema), "-")
+	cmd.Stdin = bytes.NewReader(data)
+	return cmd.CombinedOutput()
+}
+
+// ofxRootNamespace puts the OFX root element in the schema's namespace. OFX
+// documents carry none, only the root is qualified in the schema.
+func ofxRootNamespace(data []byte) []byte {
+	s := string(data)
+	s = strings.Replace(s, "<OFX>", `<ofx:OFX xmlns:ofx="http://ofx.net/types/2003/04">`, 1)
+	s = strings.Replace(s, "</OFX>", "</ofx:OFX>", 1)
+	return []byte(s)
+}
+
+func TestGoldenFilesValidate(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.golden"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+		t.Run(name, func(t *testing.T) {
+			schema, ok := goldenSchemas[name]
+			require.True(t, ok, "no schema for %s", name)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			validateSchema(t, schema, data)
+		})
+	}
+}
+
+func TestValidateSchemaRejects(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "statement.camt053.golden"))
+	require.NoError(t, err)
+	invalid := bytes.Replace(data, []byte("<Sts>BOOK</Sts>"), []byte("<Sts>DONE</Sts>"), 1)
+
+	out, err := xmllint(t, camt053Schema, invalid)
+	require.Error(t, err)
+	require.Contains(t, string(out), "Sts")
+}
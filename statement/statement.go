@@ -0,0 +1,218 @@
+// Package statement renders account statements. Entries are read from the
+// database a page at a time and written out as they arrive, so a statement
+// over any period only keeps a single page in memory.
+package statement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/money"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatPDF     = "pdf"
+	FormatCAMT053 = "camt053"
+	FormatOFX     = "ofx"
+)
+
+type format struct {
+	contentType string
+	extension   string
+	newWriter   func(w io.Writer) Writer
+}
+
+var formats = map[string]format{
+	FormatCSV:     {"text/csv; charset=utf-8", "csv", func(w io.Writer) Writer { return NewCSVWriter(w) }},
+	FormatPDF:     {"application/pdf", "pdf", func(w io.Writer) Writer { return NewPDFWriter(w) }},
+	FormatCAMT053: {"application/xml", "xml", func(w io.Writer) Writer { return NewCAMT053Writer(w) }},
+	FormatOFX:     {"application/x-ofx", "ofx", func(w io.Writer) Writer { return NewOFXWriter(w) }},
+}
+
+// ErrBalanceMismatch is returned when the entries of a statement don't add
+// up to its closing balance. Read from one snapshot they always do, unless
+// the ledger itself is off.
+var ErrBalanceMismatch = errors.New("statement entries don't add up to the closing balance")
+
+// PageSize is the number of entries read from the database at once.
+const PageSize = 500
+
+// Header opens a statement.
+type Header struct {
+	AccountID      int64
+	Owner          string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	// ClosingBalance is read before the entries, for formats that list it
+	// ahead of them.
+	ClosingBalance int64
+	// CreatedAt is when the statement was generated.
+	CreatedAt time.Time
+}
+
+// Line is a single entry of a statement. CounterpartyAccountID is zero when
+// the entry has no counterparty, e.g. for fees.
+type Line struct {
+	EntryID               int64
+	Time                  time.Time
+	Kind                  string
+	Description           string
+	Reference             string
+	CounterpartyAccountID int64
+	Counterparty          string
+	Amount                int64
+	// Balance is the account's balance right after the entry.
+	Balance int64
+}
+
+// Footer closes a statement.
+type Footer struct {
+	Entries        int
+	Credits        int64
+	Debits         int64
+	ClosingBalance int64
+}
+
+// Writer renders a statement. WriteHeader is called once, then WriteLine for
+// every entry in order and finally Close.
+type Writer interface {
+	WriteHeader(header Header) error
+	WriteLine(line Line) error
+	Close(footer Footer) error
+}
+
+// ValidFormat reports whether format is a known statement format.
+func ValidFormat(format string) bool {
+	_, ok := formats[format]
+	return ok
+}
+
+func lookupFormat(name string) format {
+	f, ok := formats[name]
+	if !ok {
+		return formats[FormatCSV]
+	}
+	return f
+}
+
+// ContentType returns the MIME type of a statement format.
+func ContentType(format string) string {
+	return lookupFormat(format).contentType
+}
+
+// NewWriter returns the writer for a statement format, CSV by default.
+func NewWriter(format string, w io.Writer) Writer {
+	return lookupFormat(format).newWriter(w)
+}
+
+// Filename names the statement of an account over a period.
+func Filename(accountID int64, from, to time.Time, format string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s", accountID,
+		from.UTC().Format("20060102"), to.UTC().Format("20060102"), lookupFormat(format).extension)
+}
+
+// formatAmount renders an amount, kept in minor units, as a decimal with the
+// minor units of its currency.
+func formatAmount(amount int64, code string) string {
+	currency, err := money.Lookup(code)
+	if err != nil {
+		// Accounts only hold known currencies, two decimals is the most
+		// common scale for anything else.
+		currency = money.Currency{Code: code, MinorUnits: 2}
+	}
+	return money.New(amount, currency).Decimal()
+}
+
+// Generate writes the statement of account for the entries created from
+// from to to, both included. q has to read from a single snapshot, see
+// db.Store.ReadTX, or entries committed meanwhile break the statement after
+// it started streaming.
+func Generate(ctx context.Context, q db.Querier, account db.Account, from, to time.Time, w Writer) error {
+	// Balances cover every entry up to and including their time and
+	// Postgres keeps microseconds, so this is the balance just before from.
+	opening, err := q.GetBalanceAt(ctx, db.GetBalanceAtParams{
+		AccountID: account.ID,
+		At:        from.Add(-time.Microsecond),
+	})
+	if err != nil {
+		return err
+	}
+
+	closing, err := q.GetBalanceAt(ctx, db.GetBalanceAtParams{
+		AccountID: account.ID,
+		At:        to,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = w.WriteHeader(Header{
+		AccountID:      account.ID,
+		Owner:          account.Owner,
+		Currency:       account.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening.Balance,
+		ClosingBalance: closing.Balance,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	footer := Footer{ClosingBalance: opening.Balance}
+	arg := db.ListStatementEntriesParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+		PageSize:  PageSize,
+	}
+	for {
+		entries, err := q.ListStatementEntries(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			footer.Entries++
+			footer.ClosingBalance += entry.Amount
+			if entry.Amount > 0 {
+				footer.Credits += entry.Amount
+			} else {
+				footer.Debits -= entry.Amount
+			}
+
+			err := w.WriteLine(Line{
+				EntryID:               entry.ID,
+				Time:                  entry.CreatedAt,
+				Kind:                  entry.Kind,
+				Description:           entry.Description,
+				Reference:             entry.ExternalReference,
+				CounterpartyAccountID: entry.CounterpartyAccountID.Int64,
+				Counterparty:          entry.CounterpartyOwner.String,
+				Amount:                entry.Amount,
+				Balance:               footer.ClosingBalance,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < PageSize {
+			break
+		}
+		arg.AfterID = entries[len(entries)-1].ID
+	}
+
+	if footer.ClosingBalance != closing.Balance {
+		return ErrBalanceMismatch
+	}
+	return w.Close(footer)
+}
@@ -0,0 +1,216 @@
+package statement
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var (
+	testFrom = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2024, 3, 31, 23, 59, 59, 999999000, time.UTC)
+)
+
+// writeTestStatement writes a small statement covering debits, credits, fees
+// and text that needs escaping.
+func writeTestStatement(t *testing.T, w Writer) {
+	require.NoError(t, w.WriteHeader(Header{
+		AccountID:      42,
+		Owner:          "Alice & Co",
+		Currency:       "USD",
+		From:           testFrom,
+		To:             testTo,
+		OpeningBalance: 10000,
+		ClosingBalance: 7250,
+		CreatedAt:      time.Date(2024, 4, 1, 8, 30, 0, 0, time.UTC),
+	}))
+
+	lines := []Line{
+		{
+			EntryID: 101, Time: testFrom.Add(9 * time.Hour), Kind: db.EntryKindTransfer,
+			Description: "rent <march>", Reference: "INV-2024-03",
+			CounterpartyAccountID: 7, Counterparty: "bob",
+			Amount: -2500, Balance: 7500,
+		},
+		{
+			EntryID: 102, Time: testFrom.Add(9 * time.Hour), Kind: db.EntryKindFee,
+			Description: "transfer fee",
+			Amount:      -25, Balance: 7475,
+		},
+		{
+			EntryID: 230, Time: testFrom.Add(15*24*time.Hour + 30*time.Minute), Kind: db.EntryKindTransfer,
+			Description:           "refund",
+			CounterpartyAccountID: 9, Counterparty: "carol",
+			Amount: 1275, Balance: 8750,
+		},
+		{
+			EntryID: 231, Time: testFrom.Add(20 * 24 * time.Hour), Kind: db.EntryKindTransfer,
+			Amount: -1500, Balance: 7250,
+		},
+	}
+	for _, line := range lines {
+		require.NoError(t, w.WriteLine(line))
+	}
+
+	require.NoError(t, w.Close(Footer{Entries: len(lines), Credits: 1275, Debits: 4025, ClosingBalance: 7250}))
+}
+
+// checkGolden compares got with testdata/name, rewriting it with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func testEntries(n int, firstID int64) []db.ListStatementEntriesRow {
+	entries := make([]db.ListStatementEntriesRow, n)
+	for i := range entries {
+		entries[i] = db.ListStatementEntriesRow{
+			ID:          firstID + int64(i),
+			Amount:      10,
+			CreatedAt:   testFrom.Add(time.Duration(i) * time.Minute),
+			Kind:        db.EntryKindTransfer,
+			Description: "rent",
+		}
+	}
+	return entries
+}
+
+func TestGenerate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	account := db.Account{ID: 42, Owner: "alice", Currency: "USD"}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetBalanceAt(gomock.Any(), gomock.Eq(db.GetBalanceAtParams{AccountID: account.ID, At: testFrom.Add(-time.Microsecond)})).
+		Times(1).
+		Return(db.GetBalanceAtRow{Balance: 100}, nil)
+
+	closing := int64(100 - 30 + 10*(PageSize-1) + 10*2)
+	store.EXPECT().
+		GetBalanceAt(gomock.Any(), gomock.Eq(db.GetBalanceAtParams{AccountID: account.ID, At: testTo})).
+		Times(1).
+		Return(db.GetBalanceAtRow{Balance: closing}, nil)
+
+	first := testEntries(PageSize, 1)
+	first[0].Amount = -30
+	first[0].Kind = db.EntryKindFee
+	second := testEntries(2, PageSize+1)
+	second[1].CounterpartyAccountID = sql.NullInt64{Int64: 7, Valid: true}
+	second[1].CounterpartyOwner = sql.NullString{String: "bob", Valid: true}
+	second[1].ExternalReference = "INV-1"
+
+	gomock.InOrder(
+		store.EXPECT().
+			ListStatementEntries(gomock.Any(), gomock.Eq(db.ListStatementEntriesParams{
+				AccountID: account.ID,
+				FromTime:  testFrom,
+				ToTime:    testTo,
+				PageSize:  PageSize,
+			})).
+			Times(1).
+			Return(first, nil),
+		store.EXPECT().
+			ListStatementEntries(gomock.Any(), gomock.Eq(db.ListStatementEntriesParams{
+				AccountID: account.ID,
+				FromTime:  testFrom,
+				ToTime:    testTo,
+				AfterID:   PageSize,
+				PageSize:  PageSize,
+			})).
+			Times(1).
+			Return(second, nil),
+	)
+
+	var buf bytes.Buffer
+	err := Generate(context.Background(), store, account, testFrom, testTo, NewCSVWriter(&buf))
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1+1+PageSize+2+1)
+
+	require.Equal(t, csvColumns, records[0])
+	require.Equal(t, "opening balance", records[1][3])
+	require.Equal(t, "100", records[1][8])
+
+	require.Equal(t, "fee", records[2][2])
+	require.Equal(t, "-30", records[2][7])
+	require.Equal(t, "70", records[2][8])
+
+	last := records[len(records)-2]
+	require.Equal(t, "INV-1", last[4])
+	require.Equal(t, "7", last[5])
+	require.Equal(t, "bob", last[6])
+
+	require.Equal(t, "closing balance", records[len(records)-1][3])
+	require.Equal(t, strconv.FormatInt(closing, 10), last[8])
+	require.Equal(t, last[8], records[len(records)-1][8])
+}
+
+func TestGenerateBalanceMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	account := db.Account{ID: 42, Currency: "USD"}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(1).Return(db.GetBalanceAtRow{Balance: 100}, nil)
+	store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(1).Return(db.GetBalanceAtRow{Balance: 200}, nil)
+	store.EXPECT().ListStatementEntries(gomock.Any(), gomock.Any()).Times(1).Return(testEntries(1, 1), nil)
+
+	var buf bytes.Buffer
+	err := Generate(context.Background(), store, account, testFrom, testTo, NewCSVWriter(&buf))
+	require.ErrorIs(t, err, ErrBalanceMismatch)
+}
+
+func TestGenerateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(1).Return(db.GetBalanceAtRow{}, sql.ErrConnDone)
+	store.EXPECT().ListStatementEntries(gomock.Any(), gomock.Any()).Times(0)
+
+	var buf bytes.Buffer
+	err := Generate(context.Background(), store, db.Account{ID: 1}, testFrom, testTo, NewCSVWriter(&buf))
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Zero(t, buf.Len())
+}
+
+func TestFilename(t *testing.T) {
+	require.Equal(t, "statement-42-20240301-20240331.csv", Filename(42, testFrom, testTo, ""))
+	require.Equal(t, "statement-42-20240301-20240331.pdf", Filename(42, testFrom, testTo, FormatPDF))
+	require.Equal(t, "statement-42-20240301-20240331.xml", Filename(42, testFrom, testTo, FormatCAMT053))
+	require.Equal(t, "statement-42-20240301-20240331.ofx", Filename(42, testFrom, testTo, FormatOFX))
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "0.00", formatAmount(0, "USD"))
+	require.Equal(t, "0.05", formatAmount(5, "USD"))
+	require.Equal(t, "12.34", formatAmount(1234, "EUR"))
+	require.Equal(t, "-1.00", formatAmount(-100, "USD"))
+	require.Equal(t, "1234", formatAmount(1234, "JPY"))
+	require.Equal(t, "1.234", formatAmount(1234, "KWD"))
+}
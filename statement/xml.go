@@ -0,0 +1,82 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// xmlFlushEntries is how many entries are buffered before they are written
+// out.
+const xmlFlushEntries = 100
+
+// xmlWriter streams an XML document token by token, keeping the first error
+// so writers can check it once per statement part.
+type xmlWriter struct {
+	enc      *xml.Encoder
+	buffered int
+	err      error
+}
+
+func newXMLWriter(w io.Writer) *xmlWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &xmlWriter{enc: enc}
+}
+
+func (x *xmlWriter) token(t xml.Token) {
+	if x.err == nil {
+		x.err = x.enc.EncodeToken(t)
+	}
+}
+
+// procInst writes a processing instruction on a line of its own, they only
+// appear ahead of the root element.
+func (x *xmlWriter) procInst(target, inst string) {
+	x.token(xml.ProcInst{Target: target, Inst: []byte(inst)})
+	x.token(xml.CharData("\n"))
+}
+
+func (x *xmlWriter) start(name string, attrs ...xml.Attr) {
+	x.token(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+func (x *xmlWriter) end(name string) {
+	x.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (x *xmlWriter) element(name, value string) {
+	if x.err == nil {
+		x.err = x.enc.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: name}})
+	}
+}
+
+// encode writes v, which names its element with an XMLName field.
+func (x *xmlWriter) encode(v any) {
+	if x.err == nil {
+		x.err = x.enc.Encode(v)
+	}
+}
+
+// entry writes a single entry, flushing every xmlFlushEntries entries.
+func (x *xmlWriter) entry(v any) error {
+	x.encode(v)
+	x.buffered++
+	if x.buffered >= xmlFlushEntries {
+		x.buffered = 0
+		return x.flush()
+	}
+	return x.err
+}
+
+// close ends the document with a newline and flushes it.
+func (x *xmlWriter) close() error {
+	x.token(xml.CharData("\n"))
+	return x.flush()
+}
+
+func (x *xmlWriter) flush() error {
+	if x.err == nil {
+		x.err = x.enc.Flush()
+	}
+	return x.err
+}
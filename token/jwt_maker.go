@@ -14,8 +14,8 @@ type JWTMaker struct {
 	secret string
 }
 
-func (j *JWTMaker) CreateToken(username string, duration time.Duration) (string, error) {
-	payload, err := NewPayload(username, duration)
+func (j *JWTMaker) CreateToken(username string, role string, duration time.Duration) (string, error) {
+	payload, err := NewPayload(username, role, duration)
 
 	if err != nil {
 		return "", err
@@ -5,7 +5,7 @@ import (
 )
 
 type Maker interface {
-	CreateToken(username string, duration time.Duration) (string, error)
+	CreateToken(username string, role string, duration time.Duration) (string, error)
 
 	VerifyToken(token string) (*Payload, error)
 }
@@ -7,11 +7,31 @@ import (
 )
 
 type Config struct {
-	DBDriver   string        `mapstructure:"DB_DRIVER"`
-	DBSource   string        `mapstructure:"DB_URL"`
-	Address    string        `mapstructure:"ADDRESS"`
-	TokenKey   string        `mapstructure:"TOKEN_KEY"`
-	AccessTime time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	DBDriver            string        `mapstructure:"DB_DRIVER"`
+	DBSource            string        `mapstructure:"DB_URL"`
+	DBIsolationLevel    string        `mapstructure:"DB_ISOLATION_LEVEL"`
+	DBTxMaxRetries      int           `mapstructure:"DB_TX_MAX_RETRIES"`
+	Address             string        `mapstructure:"ADDRESS"`
+	TokenKey            string        `mapstructure:"TOKEN_KEY"`
+	AccessTime          time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	HoldDuration        time.Duration `mapstructure:"HOLD_DURATION"`
+	BatchChunkSize      int           `mapstructure:"BATCH_CHUNK_SIZE"`
+	PayeeCoolingOff     time.Duration `mapstructure:"PAYEE_COOLING_OFF"`
+	ApprovalDuration    time.Duration `mapstructure:"APPROVAL_DURATION"`
+	OutboxSink          string        `mapstructure:"OUTBOX_SINK"`
+	OutboxURL           string        `mapstructure:"OUTBOX_URL"`
+	OutboxPollInterval  time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	OutboxBatchSize     int32         `mapstructure:"OUTBOX_BATCH_SIZE"`
+	WebhookTimeout      time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookMaxAttempts  int32         `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookBackoff      time.Duration `mapstructure:"WEBHOOK_BACKOFF"`
+	WebhookMaxBackoff   time.Duration `mapstructure:"WEBHOOK_MAX_BACKOFF"`
+	WebhookPollInterval time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	WebhookBatchSize    int32         `mapstructure:"WEBHOOK_BATCH_SIZE"`
+	ReconcileInterval   time.Duration `mapstructure:"RECONCILE_INTERVAL"`
+	SnapshotInterval    time.Duration `mapstructure:"SNAPSHOT_INTERVAL"`
+	CurrencyRefresh     time.Duration `mapstructure:"CURRENCY_REFRESH_INTERVAL"`
+	InterestInterval    time.Duration `mapstructure:"INTEREST_INTERVAL"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
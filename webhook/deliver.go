@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// EventTest is the event type of the sample event sent by the test endpoint.
+const EventTest = "webhook.test"
+
+// Target is a delivery together with where and how to send it.
+type Target struct {
+	DeliveryID int64
+	EventType  string
+	Attempts   int32
+	Payload    []byte
+	URL        string
+	Secret     string
+	// Deadline is when the attempt has to be over, zero for no limit besides
+	// the client's timeout.
+	Deadline time.Time
+}
+
+// defaultLease is used when the client has no timeout to size leases from.
+const defaultLease = time.Minute
+
+// Deliverer sends deliveries and records every attempt in the delivery log.
+// Failed attempts are retried with exponential backoff until MaxAttempts.
+type Deliverer struct {
+	store       db.Store
+	client      *http.Client
+	maxAttempts int32
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+func NewDeliverer(store db.Store, client *http.Client, maxAttempts int32, backoff time.Duration, maxBackoff time.Duration) *Deliverer {
+	return &Deliverer{
+		store:       store,
+		client:      client,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		maxBackoff:  maxBackoff,
+	}
+}
+
+// RetryDelay is the wait after the given number of failed attempts, doubling
+// from the base backoff up to the maximum.
+func (deliverer *Deliverer) RetryDelay(attempts int32) time.Duration {
+	delay := deliverer.backoff
+	for i := int32(1); i < attempts && delay < deliverer.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > deliverer.maxBackoff {
+		delay = deliverer.maxBackoff
+	}
+	return delay
+}
+
+// Lease is how long a delivery is held for one attempt: the first half to
+// send it, the second to record the outcome.
+func (deliverer *Deliverer) Lease() time.Duration {
+	if deliverer.client.Timeout <= 0 {
+		return defaultLease
+	}
+	return 2 * deliverer.client.Timeout
+}
+
+// Deliver makes one attempt to send target and records its outcome. The
+// returned error is only about recording, a failed send is reported in the
+// delivery itself.
+func (deliverer *Deliverer) Deliver(ctx context.Context, target Target) (db.WebhookDelivery, error) {
+	sendCtx := ctx
+	if !target.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		sendCtx, cancel = context.WithDeadline(ctx, target.Deadline)
+		defer cancel()
+	}
+	status, err := deliverer.send(sendCtx, target)
+
+	arg := db.RecordWebhookAttemptParams{
+		ID:            target.DeliveryID,
+		Status:        DeliveryStatusSucceeded,
+		NextAttemptAt: time.Now(),
+	}
+	if status != 0 {
+		arg.ResponseStatus = sql.NullInt32{Int32: int32(status), Valid: true}
+	}
+	if err != nil {
+		attempts := target.Attempts + 1
+		arg.LastError = err.Error()
+		arg.Status = DeliveryStatusPending
+		arg.NextAttemptAt = time.Now().Add(deliverer.RetryDelay(attempts))
+		if attempts >= deliverer.maxAttempts {
+			arg.Status = DeliveryStatusFailed
+		}
+	}
+
+	return deliverer.store.RecordWebhookAttempt(ctx, arg)
+}
+
+// send posts the payload signed with the subscription's secret and returns
+// the response status, zero if there was no response.
+func (deliverer *Deliverer) send(ctx context.Context, target Target) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(target.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(target.Secret, now, target.Payload))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(target.DeliveryID, 10))
+	req.Header.Set(EventHeader, target.EventType)
+
+	rsp, err := deliverer.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(rsp.Body, 4096))
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return rsp.StatusCode, fmt.Errorf("webhook %s responded %s", target.URL, rsp.Status)
+	}
+	return rsp.StatusCode, nil
+}
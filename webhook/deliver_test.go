@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTarget(url string) Target {
+	return Target{
+		DeliveryID: util.RandomInt(1, 1000),
+		EventType:  db.EventEntryCreated,
+		Payload:    []byte(`{"event_type":"entry.created"}`),
+		URL:        url,
+		Secret:     util.RandomString(32),
+	}
+}
+
+// recordAttempt makes the mock store return the recorded attempt as the
+// updated delivery.
+func recordAttempt(store *mockdb.MockStore, got *db.RecordWebhookAttemptParams) {
+	store.EXPECT().
+		RecordWebhookAttempt(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.RecordWebhookAttemptParams) (db.WebhookDelivery, error) {
+			*got = arg
+			return db.WebhookDelivery{ID: arg.ID, Status: arg.Status, ResponseStatus: arg.ResponseStatus}, nil
+		})
+}
+
+func TestDeliver(t *testing.T) {
+	var target Target
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, target.Payload, body)
+
+		unix, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+		require.True(t, Verify(target.Secret, time.Unix(unix, 0), body, r.Header.Get(SignatureHeader)))
+		require.Equal(t, strconv.FormatInt(target.DeliveryID, 10), r.Header.Get(DeliveryHeader))
+		require.Equal(t, target.EventType, r.Header.Get(EventHeader))
+	}))
+	defer server.Close()
+	target = randomTarget(server.URL)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	var got db.RecordWebhookAttemptParams
+	recordAttempt(store, &got)
+
+	deliverer := NewDeliverer(store, server.Client(), 3, time.Minute, time.Hour)
+	delivery, err := deliverer.Deliver(context.Background(), target)
+	require.NoError(t, err)
+	require.Equal(t, DeliveryStatusSucceeded, delivery.Status)
+	require.Equal(t, int32(http.StatusOK), got.ResponseStatus.Int32)
+	require.Empty(t, got.LastError)
+}
+
+func TestDeliverRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		attempts int32
+		status   string
+	}{
+		{name: "Retry", attempts: 0, status: DeliveryStatusPending},
+		{name: "GiveUp", attempts: 2, status: DeliveryStatusFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			var got db.RecordWebhookAttemptParams
+			recordAttempt(store, &got)
+
+			target := randomTarget(server.URL)
+			target.Attempts = tc.attempts
+
+			deliverer := NewDeliverer(store, server.Client(), 3, time.Minute, time.Hour)
+			_, err := deliverer.Deliver(context.Background(), target)
+			require.NoError(t, err)
+			require.Equal(t, tc.status, got.Status)
+			require.Equal(t, int32(http.StatusServiceUnavailable), got.ResponseStatus.Int32)
+			require.NotEmpty(t, got.LastError)
+			require.WithinDuration(t, time.Now().Add(deliverer.RetryDelay(tc.attempts+1)), got.NextAttemptAt, time.Second)
+		})
+	}
+}
+
+func TestDeliverUnreachable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	var got db.RecordWebhookAttemptParams
+	recordAttempt(store, &got)
+
+	deliverer := NewDeliverer(store, http.DefaultClient, 3, time.Minute, time.Hour)
+	_, err := deliverer.Deliver(context.Background(), randomTarget("http://127.0.0.1:0"))
+	require.NoError(t, err)
+	require.Equal(t, DeliveryStatusPending, got.Status)
+	require.False(t, got.ResponseStatus.Valid)
+}
+
+func TestRetryDelay(t *testing.T) {
+	deliverer := NewDeliverer(nil, nil, 10, time.Minute, 10*time.Minute)
+
+	require.Equal(t, time.Minute, deliverer.RetryDelay(1))
+	require.Equal(t, 2*time.Minute, deliverer.RetryDelay(2))
+	require.Equal(t, 8*time.Minute, deliverer.RetryDelay(4))
+	require.Equal(t, 10*time.Minute, deliverer.RetryDelay(5))
+	require.Equal(t, 10*time.Minute, deliverer.RetryDelay(20))
+}
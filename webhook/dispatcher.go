@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+)
+
+// Dispatcher sends queued deliveries once they are due. A claimed delivery
+// isn't claimed again until its lease runs out, and every attempt is over
+// before then, so several dispatchers can run side by side.
+type Dispatcher struct {
+	store     db.Store
+	deliverer *Deliverer
+	interval  time.Duration
+	batchSize int32
+	lease     time.Duration
+}
+
+func NewDispatcher(store db.Store, deliverer *Deliverer, interval time.Duration, batchSize int32, lease time.Duration) *Dispatcher {
+	return &Dispatcher{
+		store:     store,
+		deliverer: deliverer,
+		interval:  interval,
+		batchSize: batchSize,
+		lease:     lease,
+	}
+}
+
+// Run dispatches due deliveries every interval until ctx is done.
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := dispatcher.DispatchOnce(ctx); err != nil {
+			log.Println("Cannot dispatch webhook deliveries:", err)
+		}
+	}
+}
+
+// DispatchOnce makes one attempt at every due delivery of a batch and returns
+// the recorded deliveries. The batch is sent concurrently, each send has to
+// be over by half the lease so the outcome is recorded within it. A delivery
+// whose attempt can't be recorded is logged and retried once its lease runs
+// out, the rest of the batch isn't held up by it.
+func (dispatcher *Dispatcher) DispatchOnce(ctx context.Context) ([]db.WebhookDelivery, error) {
+	claimedAt := time.Now()
+	claimed, err := dispatcher.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LeaseUntil: claimedAt.Add(dispatcher.lease),
+		Limit:      dispatcher.batchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := claimedAt.Add(dispatcher.lease / 2)
+	recorded := make([]db.WebhookDelivery, len(claimed))
+	errs := make([]error, len(claimed))
+	var wg sync.WaitGroup
+	for i, row := range claimed {
+		wg.Add(1)
+		go func(i int, row db.ClaimWebhookDeliveriesRow) {
+			defer wg.Done()
+			recorded[i], errs[i] = dispatcher.deliverer.Deliver(ctx, Target{
+				DeliveryID: row.ID,
+				EventType:  row.EventType,
+				Attempts:   row.Attempts,
+				Payload:    row.Payload,
+				URL:        row.Url,
+				Secret:     row.Secret,
+				Deadline:   deadline,
+			})
+		}(i, row)
+	}
+	wg.Wait()
+
+	deliveries := make([]db.WebhookDelivery, 0, len(claimed))
+	for i, row := range claimed {
+		if errs[i] != nil {
+			log.Printf("Cannot record webhook delivery %d: %v", row.ID, errs[i])
+			continue
+		}
+		deliveries = append(deliveries, recorded[i])
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func claimedRows(url string, n int) []db.ClaimWebhookDeliveriesRow {
+	rows := make([]db.ClaimWebhookDeliveriesRow, n)
+	for i := range rows {
+		target := randomTarget(url)
+		rows[i] = db.ClaimWebhookDeliveriesRow{
+			ID:        int64(i + 1),
+			EventType: target.EventType,
+			Payload:   target.Payload,
+			Url:       target.URL,
+			Secret:    target.Secret,
+		}
+	}
+	return rows
+}
+
+func TestDispatchOnceSendsBatchConcurrently(t *testing.T) {
+	const batch = 3
+
+	// Every request waits for the whole batch to arrive, which only happens
+	// when they are sent side by side.
+	var arrived sync.WaitGroup
+	arrived.Add(batch)
+	all := make(chan struct{})
+	go func() {
+		arrived.Wait()
+		close(all)
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		select {
+		case <-all:
+		case <-time.After(time.Second):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	client := server.Client()
+	client.Timeout = 5 * time.Second
+	deliverer := NewDeliverer(store, client, 3, time.Minute, time.Hour)
+	dispatcher := NewDispatcher(store, deliverer, time.Minute, batch, deliverer.Lease())
+
+	store.EXPECT().
+		ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.ClaimWebhookDeliveriesParams) ([]db.ClaimWebhookDeliveriesRow, error) {
+			require.Equal(t, int32(batch), arg.Limit)
+			require.WithinDuration(t, time.Now().Add(10*time.Second), arg.LeaseUntil, time.Second)
+			return claimedRows(server.URL, batch), nil
+		})
+	store.EXPECT().
+		RecordWebhookAttempt(gomock.Any(), gomock.Any()).
+		Times(batch).
+		DoAndReturn(func(ctx context.Context, arg db.RecordWebhookAttemptParams) (db.WebhookDelivery, error) {
+			return db.WebhookDelivery{ID: arg.ID, Status: arg.Status}, nil
+		})
+
+	deliveries, err := dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, deliveries, batch)
+	for i, delivery := range deliveries {
+		require.Equal(t, int64(i+1), delivery.ID)
+		require.Equal(t, DeliveryStatusSucceeded, delivery.Status)
+	}
+}
+
+func TestDeliverDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	var got db.RecordWebhookAttemptParams
+	recordAttempt(store, &got)
+
+	target := randomTarget(server.URL)
+	target.Deadline = time.Now().Add(50 * time.Millisecond)
+
+	deliverer := NewDeliverer(store, server.Client(), 3, time.Minute, time.Hour)
+	_, err := deliverer.Deliver(context.Background(), target)
+	require.NoError(t, err)
+	require.Equal(t, DeliveryStatusPending, got.Status)
+	require.Contains(t, got.LastError, "deadline exceeded")
+}
+
+func TestLease(t *testing.T) {
+	deliverer := NewDeliverer(nil, &http.Client{Timeout: 10 * time.Second}, 3, time.Minute, time.Hour)
+	require.Equal(t, 20*time.Second, deliverer.Lease())
+
+	deliverer = NewDeliverer(nil, &http.Client{}, 3, time.Minute, time.Hour)
+	require.Equal(t, defaultLease, deliverer.Lease())
+}
+
+func TestDispatchOnceRecordError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	deliverer := NewDeliverer(store, server.Client(), 3, time.Minute, time.Hour)
+	dispatcher := NewDispatcher(store, deliverer, time.Minute, 3, deliverer.Lease())
+
+	store.EXPECT().
+		ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(claimedRows(server.URL, 3), nil)
+	store.EXPECT().
+		RecordWebhookAttempt(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(ctx context.Context, arg db.RecordWebhookAttemptParams) (db.WebhookDelivery, error) {
+			if arg.ID == 2 {
+				return db.WebhookDelivery{}, sql.ErrConnDone
+			}
+			return db.WebhookDelivery{ID: arg.ID, Status: arg.Status}, nil
+		})
+
+	deliveries, err := dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, int64(1), deliveries[0].ID)
+	require.Equal(t, int64(3), deliveries[1].ID)
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import db "github.com/aryan-more/simple_bank/db/sqlc"
+
+// EventTypes lists the outbox events users can subscribe to.
+var EventTypes = []string{
+	db.EventAccountCreated,
+	db.EventEntryCreated,
+	db.TransferEvent(db.TransferStatusPending),
+	db.TransferEvent(db.TransferStatusProcessing),
+	db.TransferEvent(db.TransferStatusCompleted),
+	db.TransferEvent(db.TransferStatusFailed),
+	db.TransferEvent(db.TransferStatusReversed),
+}
+
+// ValidEventType reports whether users can subscribe to eventType.
+func ValidEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
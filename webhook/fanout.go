@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/worker"
+)
+
+// Fanout is an outbox sink that queues a delivery for every subscription
+// interested in an event. Queuing is idempotent, so an event relayed twice is
+// still delivered once per subscription.
+type Fanout struct {
+	store db.Store
+}
+
+func NewFanout(store db.Store) *Fanout {
+	return &Fanout{store: store}
+}
+
+func (fanout *Fanout) Publish(ctx context.Context, event db.Outbox) error {
+	recipients, err := fanout.eventRecipients(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(worker.NewMessage(event))
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range recipients {
+		subscriptions, err := fanout.store.ListEventWebhookSubscriptions(ctx, db.ListEventWebhookSubscriptionsParams{
+			Owner:     recipient,
+			EventType: event.EventType,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, subscription := range subscriptions {
+			_, err := fanout.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+				SubscriptionID: subscription.ID,
+				EventID:        sql.NullInt64{Int64: event.ID, Valid: true},
+				EventType:      event.EventType,
+				Payload:        payload,
+			})
+			// No rows means the delivery was queued by an earlier relay.
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// eventRecipients returns the users an event concerns: the members of the
+// account, the members of both accounts of a transfer or the user itself.
+// Each user is returned once, even if they belong to both accounts.
+func (fanout *Fanout) eventRecipients(ctx context.Context, event db.Outbox) ([]string, error) {
+	var accountIDs []int64
+	switch event.AggregateType {
+	case db.AggregateUser:
+		return []string{event.AggregateID}, nil
+	case db.AggregateAccount:
+		id, err := strconv.ParseInt(event.AggregateID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		accountIDs = []int64{id}
+	case db.AggregateTransfer:
+		var transfer db.Transfer
+		if err := json.Unmarshal(event.Payload, &transfer); err != nil {
+			return nil, err
+		}
+		accountIDs = []int64{transfer.FromAccountID, transfer.ToAccountID}
+	default:
+		return nil, nil
+	}
+
+	var recipients []string
+	seen := make(map[string]bool)
+	for _, id := range accountIDs {
+		// A deleted account has no members left to notify.
+		members, err := fanout.store.ListAccountMembers(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if !member.Allows(db.MemberRoleViewer) || seen[member.Username] {
+				continue
+			}
+			seen[member.Username] = true
+			recipients = append(recipients, member.Username)
+		}
+	}
+	return recipients, nil
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// accepted marks members who accepted their invitation.
+var accepted = sql.NullTime{Time: time.Now(), Valid: true}
+
+func TestFanoutEntryEvent(t *testing.T) {
+	account := db.Account{ID: util.RandomInt(1, 1000), Owner: util.RandomOwner()}
+	entry := db.Entry{ID: util.RandomInt(1, 1000), AccountID: account.ID, Amount: 10}
+	payload, err := json.Marshal(entry)
+	require.NoError(t, err)
+
+	event := db.Outbox{
+		ID:            util.RandomInt(1, 1000),
+		AggregateType: db.AggregateAccount,
+		AggregateID:   strconv.FormatInt(account.ID, 10),
+		EventType:     db.EventEntryCreated,
+		Payload:       payload,
+	}
+	subscriptions := []db.WebhookSubscription{{ID: 1, Owner: account.Owner}, {ID: 2, Owner: account.Owner}}
+	members := []db.AccountMember{
+		{AccountID: account.ID, Username: account.Owner, Role: db.MemberRoleOwner, AcceptedAt: accepted},
+		{AccountID: account.ID, Username: util.RandomOwner(), Role: db.MemberRoleViewer, AcceptedAt: accepted},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().ListAccountMembers(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(members, nil)
+	store.EXPECT().
+		ListEventWebhookSubscriptions(gomock.Any(), gomock.Eq(db.ListEventWebhookSubscriptionsParams{
+			Owner:     account.Owner,
+			EventType: db.EventEntryCreated,
+		})).
+		Times(1).
+		Return(subscriptions, nil)
+	// The viewer is notified of the joint account's events too.
+	store.EXPECT().
+		ListEventWebhookSubscriptions(gomock.Any(), gomock.Eq(db.ListEventWebhookSubscriptionsParams{
+			Owner:     members[1].Username,
+			EventType: db.EventEntryCreated,
+		})).
+		Times(1).
+		Return([]db.WebhookSubscription{{ID: 3, Owner: members[1].Username}}, nil)
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(ctx context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+			require.Equal(t, event.ID, arg.EventID.Int64)
+			require.Equal(t, event.EventType, arg.EventType)
+			// The second subscription already has the delivery.
+			if arg.SubscriptionID == 2 {
+				return db.WebhookDelivery{}, sql.ErrNoRows
+			}
+			return db.WebhookDelivery{ID: 1}, nil
+		})
+
+	require.NoError(t, NewFanout(store).Publish(context.Background(), event))
+}
+
+func TestFanoutTransferEvent(t *testing.T) {
+	from := db.Account{ID: 1, Owner: util.RandomOwner()}
+	to := db.Account{ID: 2, Owner: util.RandomOwner()}
+	payload, err := json.Marshal(db.Transfer{ID: 1, FromAccountID: from.ID, ToAccountID: to.ID})
+	require.NoError(t, err)
+
+	event := db.Outbox{
+		ID:            util.RandomInt(1, 1000),
+		AggregateType: db.AggregateTransfer,
+		AggregateID:   "1",
+		EventType:     db.TransferEvent(db.TransferStatusCompleted),
+		Payload:       payload,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	// The owner of the payee account is also a signer on the payer account,
+	// and is only notified once.
+	store.EXPECT().
+		ListAccountMembers(gomock.Any(), gomock.Eq(from.ID)).
+		Times(1).
+		Return([]db.AccountMember{
+			{AccountID: from.ID, Username: from.Owner, Role: db.MemberRoleOwner, AcceptedAt: accepted},
+			{AccountID: from.ID, Username: to.Owner, Role: db.MemberRoleSigner, AcceptedAt: accepted},
+		}, nil)
+	store.EXPECT().
+		ListAccountMembers(gomock.Any(), gomock.Eq(to.ID)).
+		Times(1).
+		Return([]db.AccountMember{{AccountID: to.ID, Username: to.Owner, Role: db.MemberRoleOwner, AcceptedAt: accepted}}, nil)
+	for _, owner := range []string{from.Owner, to.Owner} {
+		store.EXPECT().
+			ListEventWebhookSubscriptions(gomock.Any(), gomock.Eq(db.ListEventWebhookSubscriptionsParams{
+				Owner:     owner,
+				EventType: event.EventType,
+			})).
+			Times(1).
+			Return([]db.WebhookSubscription{}, nil)
+	}
+	store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+	require.NoError(t, NewFanout(store).Publish(context.Background(), event))
+}
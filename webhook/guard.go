@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for webhooks pointing inside the bank's
+// network, loopback, private and link-local addresses included.
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// publicIP reports whether webhooks may be delivered to ip.
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified())
+}
+
+// CheckURL checks that a webhook URL uses https and that every address its
+// host resolves to is public. The address is checked again when deliveries
+// connect, as the host may resolve differently by then.
+func CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("webhook url must use https, not %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("webhook url has no host")
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, u.Hostname(), addr.IP)
+		}
+	}
+	return nil
+}
+
+// NewClient returns the client deliveries are sent with. It only sends https
+// requests, redirects included, and refuses to connect to addresses that
+// aren't public. The address is checked once the host is resolved, so a
+// name can't be pointed inside the network after the webhook is created.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialControl,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the webhook host.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport: httpsOnly{transport},
+		Timeout:   timeout,
+	}
+}
+
+// dialControl runs before every connection, with the resolved address.
+func dialControl(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+type httpsOnly struct {
+	next http.RoundTripper
+}
+
+func (transport httpsOnly) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("webhook url must use https, not %q", req.URL.Scheme)
+	}
+	return transport.next.RoundTrip(req)
+}
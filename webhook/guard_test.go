@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURL(t *testing.T) {
+	testCases := []struct {
+		name string
+		url  string
+		ok   bool
+	}{
+		{name: "Public", url: "https://203.0.113.10/hooks", ok: true},
+		{name: "PublicIPv6", url: "https://[2001:db8::1]/hooks", ok: true},
+		{name: "HTTP", url: "http://203.0.113.10/hooks"},
+		{name: "Loopback", url: "https://127.0.0.1/hooks"},
+		{name: "LoopbackIPv6", url: "https://[::1]/hooks"},
+		{name: "Private", url: "https://10.1.2.3/hooks"},
+		{name: "Private192", url: "https://192.168.0.10:8443/hooks"},
+		{name: "Metadata", url: "https://169.254.169.254/latest/meta-data"},
+		{name: "Unspecified", url: "https://0.0.0.0/hooks"},
+		{name: "NoHost", url: "https:///hooks"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckURL(context.Background(), tc.url)
+			if tc.ok {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+
+	// The test server listens on loopback.
+	_, err := client.Post(server.URL, "application/json", nil)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrForbiddenAddress))
+
+	_, err = client.Post("http://203.0.113.10/hooks", "application/json", nil)
+	require.ErrorContains(t, err, "https")
+}
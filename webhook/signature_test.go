@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 64)
+
+	now := time.Now()
+	body := []byte(`{"event_type":"entry.created"}`)
+	signature := Sign(secret, now, body)
+
+	require.True(t, Verify(secret, now, body, signature))
+	require.False(t, Verify(secret, now, []byte(`{"event_type":"entry.deleted"}`), signature))
+	require.False(t, Verify(secret, now.Add(time.Second), body, signature))
+	require.False(t, Verify("other secret", now, body, signature))
+	require.False(t, Verify(secret, now, body, signature[len(signaturePrefix):]))
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+)
+
+// Relay moves events from the outbox to a sink. Events are delivered at least
+// once and in order per aggregate.
+type Relay struct {
+	store     db.Store
+	sink      Sink
+	interval  time.Duration
+	batchSize int32
+}
+
+func NewRelay(store db.Store, sink Sink, interval time.Duration, batchSize int32) *Relay {
+	return &Relay{
+		store:     store,
+		sink:      sink,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run polls the outbox every interval until ctx is done. A full batch that
+// made progress is followed by the next one straight away so a backlog drains
+// quickly.
+func (relay *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			result, err := relay.RelayOnce(ctx)
+			if err != nil {
+				log.Println("Cannot relay outbox events:", err)
+				break
+			}
+			if result.Failed > 0 {
+				log.Printf("Failed to publish %d outbox events", result.Failed)
+			}
+			if result.Published == 0 || int32(result.Published+result.Failed+result.Skipped) < relay.batchSize {
+				break
+			}
+		}
+	}
+}
+
+// RelayOnce publishes a single batch of events.
+func (relay *Relay) RelayOnce(ctx context.Context) (db.PublishOutboxTxResult, error) {
+	return relay.store.PublishOutboxTX(ctx, db.PublishOutboxTxParams{
+		Limit:   relay.batchSize,
+		Publish: relay.sink.Publish,
+	})
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/aryan-more/simple_bank/db/mock"
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []db.Outbox{randomEvent(), randomEvent()}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		PublishOutboxTX(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.PublishOutboxTxParams) (db.PublishOutboxTxResult, error) {
+			require.Equal(t, int32(10), arg.Limit)
+
+			result := db.PublishOutboxTxResult{Locked: true}
+			for _, event := range events {
+				require.NoError(t, arg.Publish(ctx, event))
+				result.Published++
+			}
+			return result, nil
+		})
+
+	sink := make(ChanSink, len(events))
+	relay := NewRelay(store, sink, time.Second, 10)
+
+	result, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, len(events), result.Published)
+
+	for _, event := range events {
+		require.Equal(t, NewMessage(event), <-sink)
+	}
+}
+
+func TestRelayRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	event := randomEvent()
+	sink := make(ChanSink)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		PublishOutboxTX(gomock.Any(), gomock.Any()).
+		MinTimes(1).
+		DoAndReturn(func(ctx context.Context, arg db.PublishOutboxTxParams) (db.PublishOutboxTxResult, error) {
+			if err := arg.Publish(ctx, event); err != nil {
+				return db.PublishOutboxTxResult{Locked: true, Failed: 1}, nil
+			}
+			return db.PublishOutboxTxResult{Locked: true, Published: 1}, nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewRelay(store, sink, time.Millisecond, 10).Run(ctx)
+		close(done)
+	}()
+
+	require.Equal(t, NewMessage(event), <-sink)
+	cancel()
+	<-done
+}
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+)
+
+// Message is the wire format of an outbox event. ID is the same on every
+// delivery of an event, so consumers can drop duplicates.
+type Message struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func NewMessage(event db.Outbox) Message {
+	return Message{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       event.Payload,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+// Sink receives outbox events from the relay. An event is only marked
+// published once Publish returned nil.
+type Sink interface {
+	Publish(ctx context.Context, event db.Outbox) error
+}
+
+// LogSink writes every event to a logger.
+type LogSink struct {
+	logger *log.Logger
+}
+
+func NewLogSink(logger *log.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (sink *LogSink) Publish(ctx context.Context, event db.Outbox) error {
+	data, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return err
+	}
+
+	sink.logger.Printf("outbox event %s", data)
+	return nil
+}
+
+// HTTPSink posts every event as JSON to a URL. Any status other than 2xx
+// fails the delivery.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSink(url string, client *http.Client) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: client,
+	}
+}
+
+func (sink *HTTPSink) Publish(ctx context.Context, event db.Outbox) error {
+	data, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", strconv.FormatInt(event.ID, 10))
+
+	rsp, err := sink.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("outbox sink %s responded %s", sink.url, rsp.Status)
+	}
+	return nil
+}
+
+// MultiSink publishes every event to each of its sinks in turn. An event that
+// fails in one sink is published again to all of them, which at-least-once
+// delivery allows.
+type MultiSink []Sink
+
+func (sinks MultiSink) Publish(ctx context.Context, event db.Outbox) error {
+	for _, sink := range sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChanSink sends every event to a channel, mostly for tests.
+type ChanSink chan Message
+
+func (sink ChanSink) Publish(ctx context.Context, event db.Outbox) error {
+	select {
+	case sink <- NewMessage(event):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
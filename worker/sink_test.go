@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/aryan-more/simple_bank/db/sqlc"
+	"github.com/aryan-more/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEvent() db.Outbox {
+	return db.Outbox{
+		ID:            util.RandomInt(1, 1000),
+		AggregateType: db.AggregateAccount,
+		AggregateID:   util.RandomString(6),
+		EventType:     db.EventAccountCreated,
+		Payload:       json.RawMessage(`{"owner":"` + util.RandomOwner() + `"}`),
+		CreatedAt:     time.Now().Truncate(time.Second),
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	event := randomEvent()
+
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NotEmpty(t, r.Header.Get("Idempotency-Key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, server.Client())
+	require.NoError(t, sink.Publish(context.Background(), event))
+
+	require.Equal(t, event.ID, received.ID)
+	require.Equal(t, event.EventType, received.EventType)
+	require.Equal(t, event.AggregateID, received.AggregateID)
+	require.JSONEq(t, string(event.Payload), string(received.Payload))
+	require.WithinDuration(t, event.CreatedAt, received.CreatedAt, time.Second)
+}
+
+func TestHTTPSinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, server.Client())
+	require.Error(t, sink.Publish(context.Background(), randomEvent()))
+}
+
+func TestChanSink(t *testing.T) {
+	event := randomEvent()
+	sink := make(ChanSink, 1)
+
+	require.NoError(t, sink.Publish(context.Background(), event))
+	require.Equal(t, NewMessage(event), <-sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	unbuffered := make(ChanSink)
+	require.ErrorIs(t, unbuffered.Publish(ctx, event), context.Canceled)
+}